@@ -1,29 +1,39 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/go-clix/cli"
 	"github.com/grafana/grizzly/pkg/config"
-	"github.com/grafana/grizzly/pkg/grizzly"
 	"github.com/spf13/viper"
 )
 
 // LoggingOpts contains logging options (used in all commands)
 type LoggingOpts struct {
 	LogLevel string
+	Verbose  bool
+	Quiet    bool
 }
 
 // Opts contains options for most Grizzly commands
 type Opts struct {
 	LoggingOpts
+	Context      string
+	OrgID        int64
 	Directory    bool // Deprecated: now is gathered with os.Stat(<resource-path>)
 	JsonnetPaths []string
 	Targets      []string
 	OutputFormat string
 	DisableStats bool
+	NoColor      bool
+	Strict       bool
+	InputFormat  string
+	EventFormat  string
+	OnlyChanges  bool
+	OnlyKinds    []string
 	IsDir        bool // used internally to denote that the resource path argument pointed at a directory
 
 	// Used for supporting resources without envelopes
@@ -31,6 +41,14 @@ type Opts struct {
 	HasOnlySpec  bool
 	FolderUID    string
 	ResourceKind string
+	DeriveUIDs   bool
+
+	// Used for patching parsed resources
+	Overlays   []string
+	Transforms []string
+
+	// Used for moving resources stored in folders to another folder
+	FolderOverride string
 
 	// Used for supporting the proxy server
 	OpenBrowser     bool
@@ -199,10 +217,10 @@ func createContextCmd() *cli.Command {
 	return initialiseLogging(cmd, &opts)
 }
 
-func checkCmd(registry grizzly.Registry) *cli.Command {
+func checkCmd() *cli.Command {
 	cmd := &cli.Command{
 		Use:   "check",
-		Short: "Create a configuration context",
+		Short: "Check the configuration, and that each provider can be reached",
 	}
 	var opts LoggingOpts
 
@@ -215,10 +233,14 @@ func checkCmd(registry grizzly.Registry) *cli.Command {
 		if err != nil {
 			return err
 		}
+		registry := createRegistry(gCtx)
 
 		fmt.Printf("Configuration file: %s\n", green(viper.ConfigFileUsed()))
 		fmt.Printf("Current context: %s\n\n", green(gCtx.Name))
 
+		activeProviders := 0
+		var offline []string
+
 		for i, provider := range registry.Providers {
 			fmt.Println(yellow(provider.Name()))
 			fmt.Println(yellow(strings.Repeat("=", len(provider.Name()))))
@@ -232,6 +254,9 @@ func checkCmd(registry grizzly.Registry) *cli.Command {
 			fmt.Printf("Active: %s\n", activeMsg)
 
 			onlineMsg := green("true")
+			if status.Online && status.OnlineDetails != "" {
+				onlineMsg = fmt.Sprintf("%s - %s", green("true"), status.OnlineDetails)
+			}
 			if !status.Active || !status.Online {
 				onlineMsg = red("false")
 			}
@@ -240,11 +265,24 @@ func checkCmd(registry grizzly.Registry) *cli.Command {
 			}
 			fmt.Printf("Online: %s\n", onlineMsg)
 
+			if status.Active {
+				activeProviders++
+				if !status.Online {
+					offline = append(offline, provider.Name())
+				}
+			}
+
 			if i != len(registry.Providers)-1 {
 				fmt.Printf("\n")
 			}
 		}
 
+		if activeProviders == 0 {
+			return errors.New("no provider is configured")
+		}
+		if len(offline) > 0 {
+			return fmt.Errorf("could not connect to: %s", strings.Join(offline, ", "))
+		}
 		return nil
 	}
 	return initialiseLogging(cmd, &opts)
@@ -1,14 +1,19 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/go-clix/cli"
 	"github.com/grafana/grizzly/internal/logger"
 	"github.com/grafana/grizzly/pkg/config"
 	"github.com/grafana/grizzly/pkg/grafana"
 	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/grafana/grizzly/pkg/grizzly/notifier"
 	"github.com/grafana/grizzly/pkg/mimir"
 	"github.com/grafana/grizzly/pkg/syntheticmonitoring"
 	log "github.com/sirupsen/logrus"
@@ -27,6 +32,27 @@ func (err silentError) Error() string {
 	return err.Err.Error()
 }
 
+// signalContext returns the context given to workflows, which is cancelled on
+// the first interrupt (Ctrl-C, or SIGTERM from a CI timeout) to abort pending
+// requests. A second interrupt terminates grr right away.
+func signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(signals)
+		select {
+		case sig := <-signals:
+			notifier.Warn(nil, fmt.Sprintf("%s received, aborting", sig))
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
 func main() {
 	rootCmd := &cli.Command{
 		Use:     "grr",
@@ -45,28 +71,35 @@ func main() {
 		log.Fatalln(err)
 	}
 
-	context, err := config.CurrentContext()
+	secrets, err := contextSecrets()
 	if err != nil {
 		log.Fatalln(err)
 	}
+	log.AddHook(logger.NewSecretsRedactor(secrets))
 
-	log.AddHook(logger.NewSecretsRedactor(context.Secrets()))
-
-	registry := createRegistry(context)
 	// workflow commands
 	rootCmd.AddCommand(
-		getCmd(registry),
-		listCmd(registry),
-		pullCmd(registry),
-		showCmd(registry),
-		diffCmd(registry),
-		applyCmd(registry),
-		watchCmd(registry),
-		exportCmd(registry),
-		snapshotCmd(registry),
-		providersCmd(registry),
-		configCmd(registry),
-		serveCmd(registry),
+		getCmd(),
+		listCmd(),
+		pullCmd(),
+		showCmd(),
+		diffCmd(),
+		diffLocalCmd(),
+		validateCmd(),
+		conflictsCmd(),
+		checkDatasourcesCmd(),
+		applyCmd(),
+		watchCmd(),
+		exportCmd(),
+		restoreCmd(),
+		snapshotCmd(),
+		snapshotsCmd(),
+		renderCmd(),
+		rollbackCmd(),
+		versionsCmd(),
+		providersCmd(),
+		configCmd(),
+		serveCmd(),
 		selfUpdateCmd(),
 	)
 
@@ -81,6 +114,64 @@ func main() {
 	}
 }
 
+// contextSecrets returns the secrets of all contexts, so that they are
+// redacted from the logs whichever context a command selects with --context
+func contextSecrets() ([]string, error) {
+	names, err := config.GetContexts()
+	if err != nil {
+		return nil, err
+	}
+	secrets := []string{}
+	for _, name := range names {
+		context, err := config.GetContext(name)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, context.Secrets()...)
+	}
+	// the current context may only be set from the environment
+	current, err := config.CurrentContext()
+	if err != nil {
+		return nil, err
+	}
+	return append(secrets, current.Secrets()...), nil
+}
+
+// runContext returns the context a command works with: the one selected
+// with --context, or the current context, in the organization selected with
+// --org. Each call returns a new copy, that commands are free to alter for
+// their run.
+func runContext(opts Opts) (*config.Context, error) {
+	context, err := config.CurrentContext()
+	if err != nil {
+		return nil, err
+	}
+	if opts.OrgID != 0 {
+		context.Grafana.OrgID = opts.OrgID
+	}
+	return context, nil
+}
+
+// newRegistry returns the registry of a command, working with its context
+// (see runContext)
+func newRegistry(opts Opts) (grizzly.Registry, error) {
+	context, err := runContext(opts)
+	if err != nil {
+		return grizzly.Registry{}, err
+	}
+	return createRegistry(context), nil
+}
+
+// defaultRegistry returns the registry of a command working with the current
+// context, for commands without --context
+func defaultRegistry() (grizzly.Registry, error) {
+	context, err := config.CurrentContext()
+	if err != nil {
+		return grizzly.Registry{}, err
+	}
+	return createRegistry(context), nil
+}
+
 func createRegistry(context *config.Context) grizzly.Registry {
 	providers := []grizzly.Provider{
 		grafana.NewProvider(&context.Grafana),
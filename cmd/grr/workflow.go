@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/go-clix/cli"
 	"github.com/grafana/grizzly/pkg/config"
+	"github.com/grafana/grizzly/pkg/grafana"
 	"github.com/grafana/grizzly/pkg/grizzly"
 	"github.com/grafana/grizzly/pkg/grizzly/notifier"
 	"github.com/hashicorp/go-multierror"
@@ -17,43 +23,111 @@ import (
 
 const generalFolderUID = "general"
 
-func getCmd(registry grizzly.Registry) *cli.Command {
+func getCmd() *cli.Command {
 	cmd := &cli.Command{
-		Use:   "get <resource-type>.<resource-uid>",
-		Short: "retrieve resource",
+		Use:   "get <resource-type>.<resource-uid>...",
+		Short: "retrieve resources",
+		Args:  cli.ArgsMin(1),
+	}
+	var opts Opts
+
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		registry, err := newRegistry(opts)
+		if err != nil {
+			return err
+		}
+		format, onlySpec, err := getOutputFormat(opts)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signalContext()
+		defer stop()
+		return grizzly.Get(ctx, registry, args, onlySpec, format)
+	}
+	cmd = initialiseOnlySpec(cmd, &opts)
+	return initialiseCmd(cmd, &opts)
+}
+
+func rollbackCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:   "rollback <resource-type>.<resource-uid>",
+		Short: "restore a previous version of a remote resource",
 		Args:  cli.ArgsExact(1),
 	}
 	var opts Opts
+	var version int64
+	var writePath string
+	cmd.Flags().Int64Var(&version, "version", 0, "version to restore")
+	cmd.Flags().StringVar(&writePath, "write", "", "directory to write the restored resource to")
 
 	cmd.Run = func(cmd *cli.Command, args []string) error {
-		uid := args[0]
+		registry, err := newRegistry(opts)
+		if err != nil {
+			return err
+		}
+		if version <= 0 {
+			return fmt.Errorf("a version to restore is required, using --version")
+		}
 		format, onlySpec, err := getOutputFormat(opts)
 		if err != nil {
 			return err
 		}
-		return grizzly.Get(registry, uid, onlySpec, format)
+		ctx, stop := signalContext()
+		defer stop()
+		return grizzly.Rollback(ctx, registry, args[0], version, writePath, onlySpec, format)
 	}
 	cmd = initialiseOnlySpec(cmd, &opts)
 	return initialiseCmd(cmd, &opts)
 }
 
-func listCmd(registry grizzly.Registry) *cli.Command {
+func versionsCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:   "versions <resource-type>.<resource-uid>",
+		Short: "list the versions of a remote resource",
+		Args:  cli.ArgsExact(1),
+	}
+	var opts Opts
+	var format string
+	cmd.Flags().StringVar(&format, "format", "default", "format for listing, one of default, json, yaml")
+
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		registry, err := newRegistry(opts)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signalContext()
+		defer stop()
+		return grizzly.Versions(ctx, registry, args[0], format)
+	}
+	return initialiseCmd(cmd, &opts)
+}
+
+func listCmd() *cli.Command {
 	cmd := &cli.Command{
-		Use:   "list [-r] [<resource-path>]",
+		Use:   "list [-r] [<resource-path>...]",
 		Short: "list resource keys from file",
-		Args:  cli.ArgsRange(0, 1),
+		Args:  cli.ArgsAny(),
 	}
 	var opts Opts
 	var isRemote bool
 	var format string
 	cmd.Flags().BoolVarP(&isRemote, "remote", "r", false, "list remote resources")
+	var selectors []string
+	var tags []string
 	cmd.Flags().StringVarP(&format, "format", "f", "default", "format for listing, one of default, wide, json, yaml")
+	cmd.Flags().StringArrayVar(&selectors, "selector", nil, "only process resources matching key=value or key!=value, comma-separated (ex: folder=team-a,tag=infra), can be repeated")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "only process resources with this tag (ex: dashboard tags), can be repeated")
 
 	cmd.Run = func(cmd *cli.Command, args []string) error {
-		currentContext, err := config.CurrentContext()
+		selector, err := parseSelector(selectors, tags)
+		if err != nil {
+			return err
+		}
+		currentContext, err := runContext(opts)
 		if err != nil {
 			return err
 		}
+		registry := createRegistry(currentContext)
 		targets := currentContext.GetTargets(opts.Targets)
 
 		if isRemote {
@@ -61,8 +135,13 @@ func listCmd(registry grizzly.Registry) *cli.Command {
 				notifier.Error(nil, "No resource-path required when listing remote resources")
 				return nil
 			}
+			if len(selector) > 0 {
+				return fmt.Errorf("--selector and --tag only apply to local resources")
+			}
 
-			return grizzly.ListRemote(registry, targets, format)
+			ctx, stop := signalContext()
+			defer stop()
+			return grizzly.ListRemote(ctx, registry, targets, format)
 		}
 		if len(args) == 0 {
 			notifier.Error(nil, "resource-path required when listing local resources")
@@ -74,20 +153,31 @@ func listCmd(registry grizzly.Registry) *cli.Command {
 			return err
 		}
 
-		resources, err := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths).Parse(args[0], grizzly.ParserOptions{
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
+		resources, err := grizzly.ParsePaths(registry, parser, args, grizzly.ParserOptions{
 			DefaultResourceKind: resourceKind,
 			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
+			Overlays:            opts.Overlays,
+			Transforms:          currentContext.GetTransforms(opts.Transforms),
 		})
 		if err != nil {
 			return err
 		}
 
-		return grizzly.List(registry, resources, format)
+		resources, err = registry.FilterKinds(resources, opts.OnlyKinds)
+		if err != nil {
+			return err
+		}
+
+		return grizzly.List(registry, selector.Filter(resources), format)
 	}
+	cmd = initialiseKindFilter(cmd, &opts)
+	cmd = initialiseOverlays(cmd, &opts)
 	return initialiseCmd(cmd, &opts)
 }
 
-func pullCmd(registry grizzly.Registry) *cli.Command {
+func pullCmd() *cli.Command {
 	cmd := &cli.Command{
 		Use:   "pull <resource-path>",
 		Short: "Pulls remote resources and writes them to local sources",
@@ -95,8 +185,12 @@ func pullCmd(registry grizzly.Registry) *cli.Command {
 	}
 	var opts Opts
 	var continueOnError bool
+	var excludeFolders []string
+	var raw bool
 
 	cmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "e", false, "don't stop pulling on error")
+	cmd.Flags().StringArrayVar(&excludeFolders, "exclude-folder", nil, "skip the resources stored in this folder (UID or title), can be repeated")
+	cmd.Flags().BoolVar(&raw, "raw", false, "write resources exactly as the remote endpoints return them, for faithful backups (requires -o json --only-spec)")
 
 	cmd.Run = func(cmd *cli.Command, args []string) error {
 		eventsRecorder := getEventsRecorder(opts)
@@ -105,14 +199,17 @@ func pullCmd(registry grizzly.Registry) *cli.Command {
 			return err
 		}
 
-		currentContext, err := config.CurrentContext()
+		currentContext, err := runContext(opts)
 		if err != nil {
 			return err
 		}
+		registry := createRegistry(currentContext)
 
 		targets := currentContext.GetTargets(opts.Targets)
 
-		err = grizzly.Pull(registry, args[0], onlySpec, format, targets, continueOnError, eventsRecorder)
+		ctx, stop := signalContext()
+		defer stop()
+		err = grizzly.Pull(ctx, registry, args[0], onlySpec, format, raw, targets, excludeFolders, continueOnError, eventsRecorder)
 
 		notifier.Info(nil, eventsRecorder.Summary().AsString("resource"))
 
@@ -126,14 +223,15 @@ func pullCmd(registry grizzly.Registry) *cli.Command {
 	}
 
 	cmd = initialiseOnlySpec(cmd, &opts)
+	cmd = initialiseEventFormat(cmd, &opts)
 	return initialiseCmd(cmd, &opts)
 }
 
-func showCmd(registry grizzly.Registry) *cli.Command {
+func showCmd() *cli.Command {
 	cmd := &cli.Command{
-		Use:   "show <resource-path>",
+		Use:   "show <resource-path>...",
 		Short: "show list of resource types and UIDs",
-		Args:  cli.ArgsExact(1),
+		Args:  cli.ArgsMin(1),
 	}
 	var opts Opts
 
@@ -143,20 +241,30 @@ func showCmd(registry grizzly.Registry) *cli.Command {
 			return err
 		}
 
-		currentContext, err := config.CurrentContext()
+		currentContext, err := runContext(opts)
 		if err != nil {
 			return err
 		}
+		registry := createRegistry(currentContext)
 		targets := currentContext.GetTargets(opts.Targets)
 
-		resources, err := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths).Parse(args[0], grizzly.ParserOptions{
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
+		resources, err := grizzly.ParsePaths(registry, parser, args, grizzly.ParserOptions{
 			DefaultResourceKind: resourceKind,
 			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
+			Overlays:            opts.Overlays,
+			Transforms:          currentContext.GetTransforms(opts.Transforms),
 		})
 		if err != nil {
 			return err
 		}
 
+		resources, err = registry.FilterKinds(resources, opts.OnlyKinds)
+		if err != nil {
+			return err
+		}
+
 		format, _, err := getOutputFormat(opts)
 		if err != nil {
 			return err
@@ -164,34 +272,197 @@ func showCmd(registry grizzly.Registry) *cli.Command {
 		return grizzly.Show(registry, resources, format)
 	}
 	cmd = initialiseOnlySpec(cmd, &opts)
+	cmd = initialiseKindFilter(cmd, &opts)
+	cmd = initialiseOverlays(cmd, &opts)
 	return initialiseCmd(cmd, &opts)
 }
 
-func diffCmd(registry grizzly.Registry) *cli.Command {
+func diffCmd() *cli.Command {
 	cmd := &cli.Command{
-		Use:   "diff <resource-path>",
+		Use:   "diff <resource-path>...",
 		Short: "compare local and remote resources",
-		Args:  cli.ArgsExact(1),
+		Args:  cli.ArgsMin(1),
 	}
 	var opts Opts
+	var diffFormat string
+	var contextLines int
+	var fullDiff bool
+	var maxDiffSize int
+	var cacheRemote bool
+	var remoteDir string
+	var selectors []string
+	var onlyRefs []string
+	var tags []string
+
+	cmd.Flags().StringVar(&diffFormat, "format", "default", fmt.Sprintf("format for reporting differences, one of %s", strings.Join(grizzly.DiffFormats, ", ")))
+	cmd.Flags().IntVar(&contextLines, "context-lines", grizzly.DefaultDiffContextLines, "number of unchanged lines to show around each change")
+	cmd.Flags().BoolVar(&fullDiff, "full", false, "show changed resources in full, instead of only the lines around each change")
+	cmd.Flags().IntVar(&maxDiffSize, "max-diff-size", 0, "maximum number of lines of changes shown for each resource, 0 for unlimited")
+	cmd.Flags().StringVar(&remoteDir, "remote-dir", "", "compare resources to those of a directory written by export, instead of the remote endpoints")
+	cmd.Flags().BoolVar(&cacheRemote, "cache-remote", false, "fetch each remote resource at most once during the command")
+	cmd.Flags().StringArrayVar(&onlyRefs, "only", nil, "only process the resource with this UID, optionally prefixed with its kind (ex: Dashboard.abc123), can be repeated")
+	cmd.Flags().StringArrayVar(&selectors, "selector", nil, "only process resources matching key=value or key!=value, comma-separated (ex: folder=team-a,tag=infra), can be repeated")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "only process resources with this tag (ex: dashboard tags), can be repeated")
+	cmd.Flags().StringVar(&opts.FolderOverride, "folder-override", "", "move all dashboards to this folder (UID or title), whatever the folder they specify")
+	cmd.Flags().BoolVar(&opts.OnlyChanges, "only-changes", false, "only report the resources with changes, leaving out unchanged ones")
 
 	cmd.Run = func(cmd *cli.Command, args []string) error {
+		if !slices.Contains(grizzly.DiffFormats, diffFormat) {
+			return fmt.Errorf("unknown diff format %q, expected one of: %s", diffFormat, strings.Join(grizzly.DiffFormats, ", "))
+		}
+		if contextLines < 1 {
+			return fmt.Errorf("--context-lines must be at least 1, use --full to show changed resources in full")
+		}
+		if fullDiff {
+			contextLines = -1
+		}
+		if maxDiffSize < 0 {
+			return fmt.Errorf("--max-diff-size must be positive, or 0 for unlimited")
+		}
+		selector, err := parseSelector(selectors, tags)
+		if err != nil {
+			return err
+		}
 		resourceKind, folderUID, err := getOnlySpec(opts)
 		if err != nil {
 			return err
 		}
 
-		currentContext, err := config.CurrentContext()
+		currentContext, err := runContext(opts)
 		if err != nil {
 			return err
 		}
+		registry := createRegistry(currentContext)
+		if cacheRemote {
+			registry = registry.WithRemoteCache()
+		}
 
 		targets := currentContext.GetTargets(opts.Targets)
 
-		resources, err := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths).Parse(args[0], grizzly.ParserOptions{
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
+		resources, err := grizzly.ParsePaths(registry, parser, args, grizzly.ParserOptions{
 			DefaultResourceKind: resourceKind,
 			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
+			Overlays:            opts.Overlays,
+			Transforms:          currentContext.GetTransforms(opts.Transforms),
+		})
+		if err != nil {
+			return err
+		}
+
+		resources, err = registry.FilterKinds(resources, opts.OnlyKinds)
+		if err != nil {
+			return err
+		}
+		resources = filterRefs(registry, resources, onlyRefs)
+
+		if remoteDir != "" {
+			registry, err = useOfflineRemote(registry, parser, remoteDir)
+			if err != nil {
+				return err
+			}
+		}
+
+		format, onlySpec, err := getOutputFormat(opts)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signalContext()
+		defer stop()
+		return grizzly.Diff(ctx, registry, resources, grizzly.DiffOptions{
+			OnlySpec:     onlySpec,
+			OutputFormat: format,
+			DiffFormat:   diffFormat,
+			ContextLines: contextLines,
+			MaxDiffLines: maxDiffSize,
+			Selector:     selector,
+			Folder:       currentContext.GetFolderOverride(opts.FolderOverride),
+			OnlyChanges:  opts.OnlyChanges,
 		})
+	}
+	cmd = initialiseKindFilter(cmd, &opts)
+	cmd = initialiseOverlays(cmd, &opts)
+	return initialiseCmd(cmd, &opts)
+}
+
+func diffLocalCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:   "diff-local <before-path> <after-path>",
+		Short: "compare two sets of local resources, without remote endpoints",
+		Args:  cli.ArgsExact(2),
+	}
+	var opts Opts
+	var diffFormat string
+	var contextLines int
+	var fullDiff bool
+	var maxDiffSize int
+	var selectors []string
+	var onlyRefs []string
+	var tags []string
+
+	cmd.Flags().StringVar(&diffFormat, "format", "default", fmt.Sprintf("format for reporting differences, one of %s", strings.Join(grizzly.DiffFormats, ", ")))
+	cmd.Flags().IntVar(&contextLines, "context-lines", grizzly.DefaultDiffContextLines, "number of unchanged lines to show around each change")
+	cmd.Flags().BoolVar(&fullDiff, "full", false, "show changed resources in full, instead of only the lines around each change")
+	cmd.Flags().IntVar(&maxDiffSize, "max-diff-size", 0, "maximum number of lines of changes shown for each resource, 0 for unlimited")
+	cmd.Flags().StringArrayVar(&onlyRefs, "only", nil, "only process the resource with this UID, optionally prefixed with its kind (ex: Dashboard.abc123), can be repeated")
+	cmd.Flags().StringArrayVar(&selectors, "selector", nil, "only process resources matching key=value or key!=value, comma-separated (ex: folder=team-a,tag=infra), can be repeated")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "only process resources with this tag (ex: dashboard tags), can be repeated")
+	cmd.Flags().BoolVar(&opts.OnlyChanges, "only-changes", false, "only report the resources with changes, leaving out unchanged ones")
+
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		if !slices.Contains(grizzly.DiffFormats, diffFormat) {
+			return fmt.Errorf("unknown diff format %q, expected one of: %s", diffFormat, strings.Join(grizzly.DiffFormats, ", "))
+		}
+		if contextLines < 1 {
+			return fmt.Errorf("--context-lines must be at least 1, use --full to show changed resources in full")
+		}
+		if fullDiff {
+			contextLines = -1
+		}
+		if maxDiffSize < 0 {
+			return fmt.Errorf("--max-diff-size must be positive, or 0 for unlimited")
+		}
+		selector, err := parseSelector(selectors, tags)
+		if err != nil {
+			return err
+		}
+		resourceKind, folderUID, err := getOnlySpec(opts)
+		if err != nil {
+			return err
+		}
+
+		currentContext, err := runContext(opts)
+		if err != nil {
+			return err
+		}
+		registry := createRegistry(currentContext)
+
+		targets := currentContext.GetTargets(opts.Targets)
+
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
+		parse := func(path string) (grizzly.Resources, error) {
+			resources, err := grizzly.ParsePaths(registry, parser, []string{path}, grizzly.ParserOptions{
+				DefaultResourceKind: resourceKind,
+				DefaultFolderUID:    folderUID,
+				DeriveUIDs:          opts.DeriveUIDs,
+			})
+			if err != nil {
+				return resources, err
+			}
+			resources, err = registry.FilterKinds(resources, opts.OnlyKinds)
+			if err != nil {
+				return resources, err
+			}
+			return filterRefs(registry, resources, onlyRefs), nil
+		}
+
+		before, err := parse(args[0])
+		if err != nil {
+			return err
+		}
+		after, err := parse(args[1])
 		if err != nil {
 			return err
 		}
@@ -201,41 +472,292 @@ func diffCmd(registry grizzly.Registry) *cli.Command {
 			return err
 		}
 
-		return grizzly.Diff(registry, resources, onlySpec, format)
+		ctx, stop := signalContext()
+		defer stop()
+		return grizzly.DiffLocal(ctx, registry, before, after, grizzly.DiffOptions{
+			OnlySpec:     onlySpec,
+			OutputFormat: format,
+			DiffFormat:   diffFormat,
+			ContextLines: contextLines,
+			MaxDiffLines: maxDiffSize,
+			Selector:     selector,
+			OnlyChanges:  opts.OnlyChanges,
+		})
 	}
+	cmd = initialiseKindFilter(cmd, &opts)
 	return initialiseCmd(cmd, &opts)
 }
 
-func applyCmd(registry grizzly.Registry) *cli.Command {
+func validateCmd() *cli.Command {
 	cmd := &cli.Command{
-		Use:     "apply <resource-path>",
+		Use:   "validate <resource-path>...",
+		Short: "validate local resources, optionally against remote endpoints",
+		Args:  cli.ArgsMin(1),
+	}
+	var opts Opts
+	var remote bool
+
+	cmd.Flags().BoolVarP(&remote, "remote", "r", false, "also check resources against remote endpoints, without changing them")
+
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		resourceKind, folderUID, err := getOnlySpec(opts)
+		if err != nil {
+			return err
+		}
+
+		currentContext, err := runContext(opts)
+		if err != nil {
+			return err
+		}
+		registry := createRegistry(currentContext)
+
+		targets := currentContext.GetTargets(opts.Targets)
+
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
+		resources, err := grizzly.ParsePaths(registry, parser, args, grizzly.ParserOptions{
+			DefaultResourceKind: resourceKind,
+			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
+			Overlays:            opts.Overlays,
+			Transforms:          currentContext.GetTransforms(opts.Transforms),
+		})
+		if err != nil {
+			return err
+		}
+
+		resources, err = registry.FilterKinds(resources, opts.OnlyKinds)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signalContext()
+		defer stop()
+		// invalid resources are already reported one by one, so we return a
+		// "silent" error to ensure that the exit code will be non-zero
+		if err := grizzly.Validate(ctx, registry, resources, remote); err != nil {
+			return silentError{Err: err}
+		}
+		return nil
+	}
+	cmd = initialiseKindFilter(cmd, &opts)
+	cmd = initialiseOverlays(cmd, &opts)
+	return initialiseCmd(cmd, &opts)
+}
+
+func conflictsCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:   "conflicts <resource-path>...",
+		Short: "find the remote resources local ones could collide with, without applying them",
+		Args:  cli.ArgsMin(1),
+	}
+	var opts Opts
+
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		resourceKind, folderUID, err := getOnlySpec(opts)
+		if err != nil {
+			return err
+		}
+
+		currentContext, err := runContext(opts)
+		if err != nil {
+			return err
+		}
+		registry := createRegistry(currentContext)
+
+		targets := currentContext.GetTargets(opts.Targets)
+
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
+		resources, err := grizzly.ParsePaths(registry, parser, args, grizzly.ParserOptions{
+			DefaultResourceKind: resourceKind,
+			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
+			Overlays:            opts.Overlays,
+			Transforms:          currentContext.GetTransforms(opts.Transforms),
+		})
+		if err != nil {
+			return err
+		}
+
+		resources, err = registry.FilterKinds(resources, opts.OnlyKinds)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signalContext()
+		defer stop()
+		// potential conflicts are already reported one by one, so we return a
+		// "silent" error to ensure that the exit code will be non-zero
+		if err := grizzly.CheckConflicts(ctx, registry, resources); err != nil {
+			return silentError{Err: err}
+		}
+		return nil
+	}
+	cmd = initialiseKindFilter(cmd, &opts)
+	cmd = initialiseOverlays(cmd, &opts)
+	return initialiseCmd(cmd, &opts)
+}
+
+func checkDatasourcesCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:   "check-datasources <resource-path>...",
+		Short: "check that the remote versions of local datasources can connect to their backend",
+		Args:  cli.ArgsMin(1),
+	}
+	var opts Opts
+
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		resourceKind, folderUID, err := getOnlySpec(opts)
+		if err != nil {
+			return err
+		}
+
+		currentContext, err := runContext(opts)
+		if err != nil {
+			return err
+		}
+		registry := createRegistry(currentContext)
+
+		targets := currentContext.GetTargets(opts.Targets)
+
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
+		resources, err := grizzly.ParsePaths(registry, parser, args, grizzly.ParserOptions{
+			DefaultResourceKind: resourceKind,
+			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
+			Overlays:            opts.Overlays,
+			Transforms:          currentContext.GetTransforms(opts.Transforms),
+		})
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signalContext()
+		defer stop()
+		// failing checks are already reported one by one, so we return a
+		// "silent" error to ensure that the exit code will be non-zero
+		if err := grizzly.CheckHealth(ctx, registry, resources.OfKind(grafana.DatasourceKind)); err != nil {
+			return silentError{Err: err}
+		}
+		return nil
+	}
+	cmd = initialiseOverlays(cmd, &opts)
+	return initialiseCmd(cmd, &opts)
+}
+
+func applyCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:     "apply <resource-path>...",
 		Aliases: []string{"push"},
 		Short:   "apply local resources to remote endpoints",
-		Args:    cli.ArgsExact(1),
+		Args:    cli.ArgsMin(1),
 	}
 	var opts Opts
 	var continueOnError bool
+	var dryRun bool
+	var interactive bool
+	var contextLines int
+	var fullDiff bool
+	var maxDiffSize int
+	var force bool
+	var dereference bool
+	var cacheRemote bool
+	var useState bool
+	var stateFile string
+	var readOnlyDashboards bool
+	var dashboardMessage string
+	var messageVersion string
+	var remoteDir string
+	var maxResources int
+	var timeout time.Duration
+	var resourceTimeout time.Duration
+	var selectors []string
+	var onlyRefs []string
+	var tags []string
 
 	cmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "e", false, "don't stop apply on first error")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report what would be applied, without changing remote resources")
+	cmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "show the changes to each resource and ask for confirmation before applying them")
+	cmd.Flags().IntVar(&contextLines, "context-lines", grizzly.DefaultDiffContextLines, "with --interactive, number of unchanged lines to show around each change")
+	cmd.Flags().BoolVar(&fullDiff, "full", false, "with --interactive, show changed resources in full, instead of only the lines around each change")
+	cmd.Flags().IntVar(&maxDiffSize, "max-diff-size", 0, "with --interactive, maximum number of lines of changes shown for each resource, 0 for unlimited")
+	cmd.Flags().BoolVar(&force, "force", false, "update resources even when they are identical to their remote equivalent")
+	cmd.Flags().BoolVar(&dereference, "dereference", false, "rewrite references by name (ex: datasources of dashboards) to the UIDs they have remotely")
+	cmd.Flags().StringVar(&remoteDir, "remote-dir", "", "with --dry-run, compare resources to those of a directory written by export, instead of the remote endpoints")
+	cmd.Flags().BoolVar(&cacheRemote, "cache-remote", false, "fetch each remote resource at most once during the command")
+	cmd.Flags().BoolVar(&useState, "state", false, "skip the resources unchanged since they were last applied with the current context, without fetching them")
+	cmd.Flags().StringVar(&stateFile, "state-file", grizzly.StateFile, "file recording the resources applied, when using --state")
+	cmd.Flags().IntVar(&maxResources, "max-resources", 0, "refuse to apply more than this number of resources, 0 for no limit")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "give up on the resources not applied after this duration (ex: 10m), 0 for no deadline")
+	cmd.Flags().DurationVar(&resourceTimeout, "timeout-per-resource", 0, "report a resource as failed when applying it takes longer than this duration (ex: 30s), 0 for no deadline")
+	cmd.Flags().BoolVar(&readOnlyDashboards, "read-only-dashboards", false, "mark the dashboards applied as not editable in the Grafana UI")
+	cmd.Flags().StringVar(&dashboardMessage, "dashboard-message", "", "message attached to the dashboard versions saved, where {file} and {version} are replaced (ex: \"applied from {file} @ {version}\")")
+	cmd.Flags().StringVar(&messageVersion, "message-version", "", "version replacing {version} in the dashboard message (ex: a commit SHA)")
+	cmd.Flags().StringArrayVar(&onlyRefs, "only", nil, "only process the resource with this UID, optionally prefixed with its kind (ex: Dashboard.abc123), can be repeated")
+	cmd.Flags().StringArrayVar(&selectors, "selector", nil, "only process resources matching key=value or key!=value, comma-separated (ex: folder=team-a,tag=infra), can be repeated")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "only process resources with this tag (ex: dashboard tags), can be repeated")
+	cmd.Flags().StringVar(&opts.FolderOverride, "folder-override", "", "move all dashboards to this folder (UID or title), whatever the folder they specify")
+	cmd.Flags().BoolVar(&opts.OnlyChanges, "only-changes", false, "only report the resources with changes, leaving out unchanged and skipped ones")
 
 	cmd.Run = func(cmd *cli.Command, args []string) error {
+		selector, err := parseSelector(selectors, tags)
+		if err != nil {
+			return err
+		}
+		if remoteDir != "" && !dryRun {
+			return fmt.Errorf("--remote-dir requires --dry-run, as resources can't be applied to an export directory")
+		}
+		if contextLines < 1 {
+			return fmt.Errorf("--context-lines must be at least 1, use --full to show changed resources in full")
+		}
+		if fullDiff {
+			contextLines = -1
+		}
+		if maxDiffSize < 0 {
+			return fmt.Errorf("--max-diff-size must be positive, or 0 for unlimited")
+		}
+		if maxResources < 0 {
+			return fmt.Errorf("--max-resources must be positive, or 0 for no limit")
+		}
+		if timeout < 0 || resourceTimeout < 0 {
+			return fmt.Errorf("--timeout and --timeout-per-resource must be positive, or 0 for no deadline")
+		}
 		eventsRecorder := getEventsRecorder(opts)
 		resourceKind, folderUID, err := getOnlySpec(opts)
 		if err != nil {
 			return err
 		}
 
-		currentContext, err := config.CurrentContext()
+		// the dashboard options only apply to this run, through the context
+		// its registry is made from
+		currentContext, err := runContext(opts)
 		if err != nil {
 			return err
 		}
+		if readOnlyDashboards {
+			currentContext.Grafana.ReadOnlyDashboards = true
+		}
+		if currentContext.Grafana.ReadOnlyDashboards {
+			notifier.Warn(nil, "Dashboards will be read-only: they can't be edited in the Grafana UI")
+		}
+		if dashboardMessage != "" {
+			currentContext.Grafana.DashboardMessage = dashboardMessage
+		}
+		currentContext.Grafana.DashboardMessageVersion = messageVersion
+
+		registry := createRegistry(currentContext)
+		if cacheRemote {
+			registry = registry.WithRemoteCache()
+		}
 
 		targets := currentContext.GetTargets(opts.Targets)
-		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(continueOnError))
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(continueOnError), grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
 
-		resources, parseErr := parser.Parse(args[0], grizzly.ParserOptions{
+		resources, parseErr := grizzly.ParsePaths(registry, parser, args, grizzly.ParserOptions{
 			DefaultResourceKind: resourceKind,
 			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
+			Overlays:            opts.Overlays,
+			Transforms:          currentContext.GetTransforms(opts.Transforms),
 		})
 
 		if parseErr != nil {
@@ -255,9 +777,67 @@ func applyCmd(registry grizzly.Registry) *cli.Command {
 			return silentError{Err: parseErr}
 		}
 
-		notifier.Info(nil, fmt.Sprintf("Applying %s", grizzly.Pluraliser(resources.Len(), "resource")))
+		resources, err = registry.FilterKinds(resources, opts.OnlyKinds)
+		if err != nil {
+			return err
+		}
+		resources = filterRefs(registry, resources, onlyRefs)
+
+		if remoteDir != "" {
+			registry, err = useOfflineRemote(registry, parser, remoteDir)
+			if err != nil {
+				return err
+			}
+		}
+
+		if dryRun {
+			notifier.Info(nil, fmt.Sprintf("Applying %s (dry run)", grizzly.Pluraliser(selector.Filter(resources).Len(), "resource")))
+		} else {
+			notifier.Info(nil, fmt.Sprintf("Applying %s", grizzly.Pluraliser(selector.Filter(resources).Len(), "resource")))
+		}
+
+		applyOpts := grizzly.ApplyOptions{
+			ContinueOnError: continueOnError,
+			DryRun:          dryRun,
+			Interactive:     interactive,
+			ContextLines:    contextLines,
+			MaxDiffLines:    maxDiffSize,
+			Selector:        selector,
+			Folder:          currentContext.GetFolderOverride(opts.FolderOverride),
+			ResourceTimeout: resourceTimeout,
+			MaxResources:    maxResources,
+			Force:           force,
+			Dereference:     dereference,
+		}
+		if useState {
+			applyOpts.State, err = grizzly.LoadApplyState(stateFile, currentContext.Name)
+			if err != nil {
+				return fmt.Errorf("reading state file: %w", err)
+			}
+		}
+
+		ctx, stop := signalContext()
+		defer stop()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		applyErr := grizzly.Apply(ctx, registry, resources, applyOpts, eventsRecorder)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			notifier.Error(nil, fmt.Sprintf("Timed out after %s, the remaining resources weren't applied", timeout))
+		}
+		if errors.Is(applyErr, grizzly.ErrTooManyResources) {
+			notifier.Error(nil, applyErr.Error())
+		}
 
-		applyErr := grizzly.Apply(registry, resources, continueOnError, eventsRecorder)
+		// resources applied before an error are kept in the state
+		if applyOpts.State != nil && !dryRun {
+			if err := applyOpts.State.Save(); err != nil {
+				applyErr = errors.Join(applyErr, fmt.Errorf("writing state file: %w", err))
+				notifier.Error(nil, fmt.Sprintf("writing state file: %s", err))
+			}
+		}
 
 		notifier.Info(nil, eventsRecorder.Summary().AsString("resource"))
 
@@ -271,16 +851,21 @@ func applyCmd(registry grizzly.Registry) *cli.Command {
 	}
 
 	cmd = initialiseOnlySpec(cmd, &opts)
+	cmd = initialiseEventFormat(cmd, &opts)
+	cmd = initialiseKindFilter(cmd, &opts)
+	cmd = initialiseOverlays(cmd, &opts)
 	return initialiseCmd(cmd, &opts)
 }
 
-func watchCmd(registry grizzly.Registry) *cli.Command {
+func watchCmd() *cli.Command {
 	cmd := &cli.Command{
 		Use:   "watch <dir-to-watch> <resource-path>",
 		Short: "watch dir recursively for file changes and apply selected resource path",
 		Args:  cli.ArgsExact(2),
 	}
 	var opts Opts
+	var mode string
+	cmd.Flags().StringVar(&mode, "mode", grizzly.WatchModeApply, "what to do when changes are detected: apply, diff or validate")
 
 	cmd.Run = func(cmd *cli.Command, args []string) error {
 		resourceKind, folderUID, err := getOnlySpec(opts)
@@ -288,52 +873,69 @@ func watchCmd(registry grizzly.Registry) *cli.Command {
 			return err
 		}
 
-		currentContext, err := config.CurrentContext()
+		currentContext, err := runContext(opts)
 		if err != nil {
 			return err
 		}
+		registry := createRegistry(currentContext)
 		targets := currentContext.GetTargets(opts.Targets)
 
 		watchDir, resourcePath := args[0], args[1]
 
 		trailRecorder := grizzly.NewWriterRecorder(os.Stdout, grizzly.EventToPlainText)
 
-		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(true))
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(true), grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
 		parserOpts := grizzly.ParserOptions{
 			DefaultResourceKind: resourceKind,
 			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
 		}
-		return grizzly.Watch(registry, watchDir, resourcePath, parser, parserOpts, trailRecorder)
+		ctx, stop := signalContext()
+		defer stop()
+		return grizzly.Watch(ctx, registry, watchDir, resourcePath, mode, parser, parserOpts, trailRecorder)
 	}
 	cmd = initialiseOnlySpec(cmd, &opts)
 	return initialiseCmd(cmd, &opts)
 }
 
-func snapshotCmd(registry grizzly.Registry) *cli.Command {
+func snapshotCmd() *cli.Command {
 	cmd := &cli.Command{
-		Use:   "snapshot <resource-path>",
+		Use:   "snapshot <resource-path>...",
 		Short: "upload a snapshot to preview resources",
-		Args:  cli.ArgsExact(1),
+		Args:  cli.ArgsAny(),
 	}
 	var opts Opts
+	var files []string
+	var name string
+	cmd.Flags().StringArrayVarP(&files, "file", "f", nil, "resources to upload as snapshots, can be repeated, in addition to the resource paths")
+	cmd.Flags().StringVar(&name, "name", "", "name of the snapshots")
 	expires := cmd.Flags().IntP("expires", "e", 0, "when the snapshot should expire. Default 0 (never)")
 
 	cmd.Run = func(cmd *cli.Command, args []string) error {
+		paths := append(args, files...)
+		if len(paths) == 0 {
+			return fmt.Errorf("at least one resource path is required")
+		}
+
 		resourceKind, folderUID, err := getOnlySpec(opts)
 		if err != nil {
 			return err
 		}
 
-		currentContext, err := config.CurrentContext()
+		currentContext, err := runContext(opts)
 		if err != nil {
 			return err
 		}
+		registry := createRegistry(currentContext)
 		targets := currentContext.GetTargets(opts.Targets)
-		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(false))
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(false), grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
 
-		resources, parseErr := parser.Parse(args[0], grizzly.ParserOptions{
+		resources, parseErr := grizzly.ParsePaths(registry, parser, paths, grizzly.ParserOptions{
 			DefaultResourceKind: resourceKind,
 			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
+			Overlays:            opts.Overlays,
+			Transforms:          currentContext.GetTransforms(opts.Transforms),
 		})
 
 		if parseErr != nil {
@@ -349,12 +951,144 @@ func snapshotCmd(registry grizzly.Registry) *cli.Command {
 			}
 			return silentError{Err: parseErr}
 		}
-		return grizzly.Snapshot(registry, resources, *expires)
+		ctx, stop := signalContext()
+		defer stop()
+		return grizzly.Snapshot(ctx, registry, resources, grizzly.SnapshotOptions{
+			Name:           name,
+			ExpiresSeconds: *expires,
+		})
+	}
+
+	cmd = initialiseOverlays(cmd, &opts)
+	return initialiseCmd(cmd, &opts)
+}
+
+// snapshotsCmd groups the commands managing remote snapshots. They can't be
+// subcommands of snapshot, which takes resource paths as arguments.
+func snapshotsCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:   "snapshots",
+		Short: "manage remote snapshots",
+		Args:  cli.ArgsNone(),
+	}
+	cmd.AddCommand(snapshotListCmd())
+	cmd.AddCommand(snapshotDeleteCmd())
+	return cmd
+}
+
+func snapshotListCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:   "list",
+		Short: "list remote snapshots",
+		Args:  cli.ArgsNone(),
+	}
+	var opts Opts
+	var format string
+	cmd.Flags().StringVar(&format, "format", "default", "format for listing, one of default, json, yaml")
+
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		registry, err := newRegistry(opts)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signalContext()
+		defer stop()
+		return grizzly.ListSnapshots(ctx, registry, format)
+	}
+	return initialiseCmd(cmd, &opts)
+}
+
+func snapshotDeleteCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:   "delete <key>",
+		Short: "delete a remote snapshot",
+		Args:  cli.ArgsExact(1),
+	}
+	var opts Opts
+
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		registry, err := newRegistry(opts)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signalContext()
+		defer stop()
+		return grizzly.DeleteSnapshot(ctx, registry, args[0])
 	}
 	return initialiseCmd(cmd, &opts)
 }
 
-func serveCmd(registry grizzly.Registry) *cli.Command {
+func renderCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:   "render -f <resource-path>... <output-dir>",
+		Short: "render remote resources as PNG images",
+		Args:  cli.ArgsExact(1),
+	}
+	var opts Opts
+	var paths []string
+	var renderOpts grizzly.RenderOptions
+	cmd.Flags().StringArrayVarP(&paths, "file", "f", nil, "resources to render, can be repeated")
+	cmd.Flags().StringVar(&renderOpts.From, "from", "", "start of the time range (ex: now-6h). Defaults to the range of the resource")
+	cmd.Flags().StringVar(&renderOpts.To, "to", "", "end of the time range (ex: now). Defaults to the range of the resource")
+	cmd.Flags().StringVar(&renderOpts.Theme, "theme", "", "theme of the images, one of light, dark")
+	cmd.Flags().IntVar(&renderOpts.Width, "width", 1000, "width of the images, in pixels")
+	cmd.Flags().IntVar(&renderOpts.Height, "height", 500, "height of the images, in pixels")
+	cmd.Flags().IntVar(&renderOpts.PanelID, "panel-id", 0, "ID of a single panel to render")
+
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		if len(paths) == 0 {
+			return fmt.Errorf("at least one resource path is required, using -f")
+		}
+		switch renderOpts.Theme {
+		case "", "light", "dark":
+		default:
+			return fmt.Errorf("unknown theme '%s', expected one of light, dark", renderOpts.Theme)
+		}
+
+		resourceKind, folderUID, err := getOnlySpec(opts)
+		if err != nil {
+			return err
+		}
+
+		currentContext, err := runContext(opts)
+		if err != nil {
+			return err
+		}
+		registry := createRegistry(currentContext)
+		targets := currentContext.GetTargets(opts.Targets)
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(false), grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
+
+		resources, parseErr := grizzly.ParsePaths(registry, parser, paths, grizzly.ParserOptions{
+			DefaultResourceKind: resourceKind,
+			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
+			Overlays:            opts.Overlays,
+			Transforms:          currentContext.GetTransforms(opts.Transforms),
+		})
+
+		if parseErr != nil {
+			var parseErrors []error
+			if merr, ok := parseErr.(*multierror.Error); ok {
+				parseErrors = merr.Errors
+			} else {
+				parseErrors = []error{parseErr}
+			}
+
+			for _, e := range parseErrors {
+				notifier.Error(nil, e.Error())
+			}
+			return silentError{Err: parseErr}
+		}
+		ctx, stop := signalContext()
+		defer stop()
+		return grizzly.Render(ctx, registry, resources, args[0], renderOpts)
+	}
+
+	cmd = initialiseOverlays(cmd, &opts)
+	return initialiseCmd(cmd, &opts)
+}
+
+func serveCmd() *cli.Command {
 	cmd := &cli.Command{
 		Use:   "serve <resources>",
 		Short: "Run Grizzly server",
@@ -368,10 +1102,11 @@ func serveCmd(registry grizzly.Registry) *cli.Command {
 			return err
 		}
 
-		currentContext, err := config.CurrentContext()
+		currentContext, err := runContext(opts)
 		if err != nil {
 			return err
 		}
+		registry := createRegistry(currentContext)
 
 		resourcesPath := ""
 		watchPaths := args
@@ -388,10 +1123,11 @@ func serveCmd(registry grizzly.Registry) *cli.Command {
 		}
 
 		targets := currentContext.GetTargets(opts.Targets)
-		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(true))
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(true), grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
 		parserOpts := grizzly.ParserOptions{
 			DefaultResourceKind: resourceKind,
 			DefaultFolderUID:    folderUID,
+			DeriveUIDs:          opts.DeriveUIDs,
 		}
 
 		format, onlySpec, err := getOutputFormat(opts)
@@ -426,48 +1162,134 @@ func serveCmd(registry grizzly.Registry) *cli.Command {
 	return initialiseCmd(cmd, &opts)
 }
 
-func exportCmd(registry grizzly.Registry) *cli.Command {
+func exportCmd() *cli.Command {
 	cmd := &cli.Command{
-		Use:   "export <resource-path> <export-dir>",
+		Use:   "export [<resource-path>] <export-dir>",
 		Short: "render resources and save to a directory",
-		Args:  cli.ArgsExact(2),
+		Args:  cli.ArgsRange(1, 2),
 	}
 	var opts Opts
 	var continueOnError bool
+	var layout string
+	var remote bool
+	var provisioning bool
+	var raw bool
+	var skipExisting bool
+	var workers int
+	var selectors []string
+	var excludeFolders []string
+	var tags []string
 
 	cmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "e", false, "don't stop exporting on error")
+	cmd.Flags().StringVar(&layout, "layout", grizzly.ExportLayoutDirectory, "how to lay out exported resources: directory (one file per resource) or stream (a single file, <export-dir> being its path)")
+	cmd.Flags().BoolVar(&remote, "remote", false, "export all remote resources matching the targets instead of local ones, no <resource-path> is expected")
+	cmd.Flags().BoolVar(&skipExisting, "skip-existing", false, "skip resources already exported to <export-dir>, without fetching nor comparing them, to resume an interrupted export")
+	cmd.Flags().IntVar(&workers, "workers", 1, "number of resources written concurrently, with --layout directory")
+	cmd.Flags().BoolVar(&provisioning, "provisioning", false, "also write a provisioning.yaml manifest for Grafana's file-based dashboard provisioning (requires -o json --only-spec)")
+	cmd.Flags().BoolVar(&raw, "raw", false, "with --remote, write resources exactly as the remote endpoints return them, for faithful backups (requires -o json --only-spec)")
+	cmd.Flags().StringArrayVar(&excludeFolders, "exclude-folder", nil, "with --remote, skip the resources stored in this folder (UID or title), can be repeated")
+	cmd.Flags().StringArrayVar(&selectors, "selector", nil, "only process resources matching key=value or key!=value, comma-separated (ex: folder=team-a,tag=infra), can be repeated")
+	cmd.Flags().StringArrayVar(&tags, "tag", nil, "only process resources with this tag (ex: dashboard tags), can be repeated")
+	cmd.Flags().StringVar(&opts.FolderOverride, "folder-override", "", "move all dashboards to this folder (UID or title), whatever the folder they specify")
 
 	cmd.Run = func(cmd *cli.Command, args []string) error {
-		resourcePath := args[0]
-		exportDir := args[1]
-		resourceKind, folderUID, err := getOnlySpec(opts)
+		if remote && len(args) != 1 {
+			return fmt.Errorf("export --remote expects exactly one argument: <export-dir>")
+		}
+		if !remote && len(args) != 2 {
+			return fmt.Errorf("export expects exactly two arguments: <resource-path> <export-dir>")
+		}
+		exportDir := args[len(args)-1]
+		if layout != grizzly.ExportLayoutDirectory && layout != grizzly.ExportLayoutStream {
+			return fmt.Errorf("unknown layout %q, expected one of: %s, %s", layout, grizzly.ExportLayoutDirectory, grizzly.ExportLayoutStream)
+		}
+		if skipExisting && layout != grizzly.ExportLayoutDirectory {
+			return fmt.Errorf("--skip-existing requires --layout %s", grizzly.ExportLayoutDirectory)
+		}
+		if len(excludeFolders) > 0 && !remote {
+			return fmt.Errorf("--exclude-folder requires --remote, use --selector folder!=<uid> for local resources")
+		}
+		if raw && !remote {
+			return fmt.Errorf("--raw requires --remote, local resources have no raw remote representation")
+		}
+		if workers < 1 {
+			return fmt.Errorf("--workers must be at least 1")
+		}
+		selector, err := parseSelector(selectors, tags)
 		if err != nil {
 			return err
 		}
-
-		currentContext, err := config.CurrentContext()
+		resourceKind, folderUID, err := getOnlySpec(opts)
 		if err != nil {
 			return err
 		}
 
-		targets := currentContext.GetTargets(opts.Targets)
-
-		resources, err := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(continueOnError)).Parse(resourcePath, grizzly.ParserOptions{
-			DefaultResourceKind: resourceKind,
-			DefaultFolderUID:    folderUID,
-		})
+		currentContext, err := runContext(opts)
 		if err != nil {
 			return err
 		}
+		registry := createRegistry(currentContext)
+
+		targets := currentContext.GetTargets(opts.Targets)
 
 		format, onlySpec, err := getOutputFormat(opts)
 		if err != nil {
 			return err
 		}
+		if provisioning && (layout != grizzly.ExportLayoutDirectory || format != "json" || !onlySpec) {
+			return fmt.Errorf("--provisioning requires dashboards to be exported as JSON, without envelope, to a directory (-o json --only-spec --layout directory)")
+		}
+		if raw && (layout != grizzly.ExportLayoutDirectory || format != "json" || !onlySpec) {
+			return fmt.Errorf("--raw requires resources to be exported as JSON, without envelope, to a directory (-o json --only-spec --layout directory)")
+		}
 
 		eventsRecorder := getEventsRecorder(opts)
+		exportOpts := grizzly.ExportOptions{
+			OnlySpec:        onlySpec,
+			OutputFormat:    format,
+			ContinueOnError: continueOnError,
+			Layout:          layout,
+			Selector:        selector,
+			Folder:          currentContext.GetFolderOverride(opts.FolderOverride),
+			Provisioning:    provisioning,
+			SkipExisting:    skipExisting,
+			Workers:         workers,
+			ExcludeFolders:  excludeFolders,
+			Raw:             raw,
+		}
 
-		err = grizzly.Export(eventsRecorder, registry, exportDir, resources, onlySpec, format, continueOnError)
+		ctx, stop := signalContext()
+		defer stop()
+
+		if remote {
+			err = grizzly.ExportRemote(ctx, eventsRecorder, registry, exportDir, targets, exportOpts)
+		} else {
+			var resources grizzly.Resources
+			parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(continueOnError), grizzly.ParserStrict(opts.Strict), grizzly.ParserFormat(opts.InputFormat), grizzly.ParserJsonnetDashboardKeys(currentContext.JsonnetDashboardKeys))
+			parserOpts := grizzly.ParserOptions{
+				DefaultResourceKind: resourceKind,
+				DefaultFolderUID:    folderUID,
+				DeriveUIDs:          opts.DeriveUIDs,
+			}
+			resources, err = parser.Parse(args[0], parserOpts)
+			if err != nil {
+				return err
+			}
+			if len(opts.Overlays) > 0 {
+				resources, err = grizzly.ApplyOverlays(parser, resources, opts.Overlays, parserOpts)
+				if err != nil {
+					return err
+				}
+			}
+			if transforms := currentContext.GetTransforms(opts.Transforms); len(transforms) > 0 {
+				resources, err = grizzly.ApplyTransforms(resources, transforms)
+				if err != nil {
+					return err
+				}
+			}
+
+			err = grizzly.Export(ctx, eventsRecorder, registry, exportDir, resources, exportOpts)
+		}
 
 		notifier.Info(nil, eventsRecorder.Summary().AsString("resource"))
 
@@ -480,10 +1302,89 @@ func exportCmd(registry grizzly.Registry) *cli.Command {
 		return nil
 	}
 	cmd = initialiseOnlySpec(cmd, &opts)
+	cmd = initialiseEventFormat(cmd, &opts)
+	cmd = initialiseOverlays(cmd, &opts)
 	return initialiseCmd(cmd, &opts)
 }
 
-func providersCmd(registry grizzly.Registry) *cli.Command {
+func restoreCmd() *cli.Command {
+	cmd := &cli.Command{
+		Use:   "restore <export-dir>",
+		Short: "apply the resources of a directory written by export, keeping its folder layout",
+		Args:  cli.ArgsExact(1),
+	}
+	var opts Opts
+	var continueOnError bool
+	var dryRun bool
+
+	cmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "e", false, "don't stop restore on first error")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report what would be restored, without changing remote resources")
+
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		eventsRecorder := getEventsRecorder(opts)
+
+		currentContext, err := runContext(opts)
+		if err != nil {
+			return err
+		}
+		registry := createRegistry(currentContext)
+
+		targets := currentContext.GetTargets(opts.Targets)
+		parser := grizzly.DefaultParser(registry, targets, opts.JsonnetPaths, grizzly.ParserContinueOnError(continueOnError), grizzly.ParserStrict(opts.Strict))
+
+		resources, parseErr := grizzly.ParseExport(registry, parser, args[0], grizzly.ParserOptions{})
+		if parseErr != nil {
+			var parseErrors []error
+			if merr, ok := parseErr.(*multierror.Error); ok {
+				parseErrors = merr.Errors
+			} else {
+				parseErrors = []error{parseErr}
+			}
+
+			for _, e := range parseErrors {
+				notifier.Error(nil, e.Error())
+			}
+		}
+
+		if parseErr != nil && !continueOnError {
+			return silentError{Err: parseErr}
+		}
+
+		resources, err = registry.FilterKinds(resources, opts.OnlyKinds)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			notifier.Info(nil, fmt.Sprintf("Restoring %s (dry run)", grizzly.Pluraliser(resources.Len(), "resource")))
+		} else {
+			notifier.Info(nil, fmt.Sprintf("Restoring %s", grizzly.Pluraliser(resources.Len(), "resource")))
+		}
+
+		ctx, stop := signalContext()
+		defer stop()
+		applyErr := grizzly.Apply(ctx, registry, resources, grizzly.ApplyOptions{
+			ContinueOnError: continueOnError,
+			DryRun:          dryRun,
+		}, eventsRecorder)
+
+		notifier.Info(nil, eventsRecorder.Summary().AsString("resource"))
+
+		// errors are already displayed by the `eventsRecorder`, so we return a
+		// "silent" one to ensure that the exit code will be non-zero
+		if parseErr != nil || applyErr != nil {
+			return silentError{Err: errors.Join(parseErr, applyErr)}
+		}
+
+		return nil
+	}
+
+	cmd = initialiseEventFormat(cmd, &opts)
+	cmd = initialiseKindFilter(cmd, &opts)
+	return initialiseCmd(cmd, &opts)
+}
+
+func providersCmd() *cli.Command {
 	cmd := &cli.Command{
 		Use:   "providers",
 		Short: "Lists all providers registered with Grizzly",
@@ -492,6 +1393,10 @@ func providersCmd(registry grizzly.Registry) *cli.Command {
 	var opts LoggingOpts
 
 	cmd.Run = func(cmd *cli.Command, args []string) error {
+		registry, err := defaultRegistry()
+		if err != nil {
+			return err
+		}
 		f := "%s\t%s\n"
 		w := tabwriter.NewWriter(os.Stdout, 0, 0, 4, ' ', 0)
 
@@ -507,7 +1412,7 @@ func providersCmd(registry grizzly.Registry) *cli.Command {
 	return initialiseLogging(cmd, &opts)
 }
 
-func configCmd(registry grizzly.Registry) *cli.Command {
+func configCmd() *cli.Command {
 	cmd := &cli.Command{
 		Use:   "config <sub-command>",
 		Short: "Show, select or configure configuration",
@@ -522,7 +1427,7 @@ func configCmd(registry grizzly.Registry) *cli.Command {
 	cmd.AddCommand(setCmd())
 	cmd.AddCommand(unsetCmd())
 	cmd.AddCommand(createContextCmd())
-	cmd.AddCommand(checkCmd(registry))
+	cmd.AddCommand(checkCmd())
 	return cmd
 }
 
@@ -536,8 +1441,29 @@ func initialiseCmd(cmd *cli.Command, opts *Opts) *cli.Command {
 	cmd.Flags().StringSliceVarP(&opts.Targets, "target", "t", nil, "resources to target")
 	cmd.Flags().StringSliceVarP(&opts.JsonnetPaths, "jpath", "J", getDefaultJsonnetFolders(), "Specify an additional library search dir (right-most wins)")
 	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "", "Output format")
+	cmd.Flags().BoolVar(&opts.Strict, "strict", false, "fail on YAML documents of unknown kinds, instead of skipping them")
+	cmd.Flags().StringVar(&opts.InputFormat, "input-format", "", fmt.Sprintf("parse all files with this format (one of %s), instead of inferring it from their extension", strings.Join(grizzly.InputFormats, ", ")))
 
 	cmd.Flags().BoolVar(&opts.DisableStats, "disable-reporting", false, "disable sending of anonymous usage stats to Grafana Labs")
+	cmd.Flags().StringVar(&opts.Context, "context", "", "context to use for this command, instead of the current context")
+	cmd.Flags().Int64Var(&opts.OrgID, "org", 0, "ID of the Grafana organization to work with, instead of the current organization of the user or token")
+	cmd.Flags().BoolVar(&opts.NoColor, "no-color", false, "disable colored output")
+
+	cmdRun := cmd.Run
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		if opts.NoColor {
+			color.NoColor = true
+		}
+		if opts.InputFormat != "" && !slices.Contains(grizzly.InputFormats, opts.InputFormat) {
+			return fmt.Errorf("unknown input format %q, expected one of: %s", opts.InputFormat, strings.Join(grizzly.InputFormats, ", "))
+		}
+		if opts.Context != "" {
+			if err := config.OverrideContext(opts.Context); err != nil {
+				return err
+			}
+		}
+		return cmdRun(cmd, args)
+	}
 
 	return initialiseLogging(cmd, &opts.LoggingOpts)
 }
@@ -546,6 +1472,7 @@ func initialiseOnlySpec(cmd *cli.Command, opts *Opts) *cli.Command {
 	cmd.Flags().BoolVarP(&opts.OnlySpec, "only-spec", "s", false, "this flag is only used for dashboards to output the spec")
 	cmd.Flags().StringVarP(&opts.FolderUID, "folder", "f", generalFolderUID, "folder to push dashboards to")
 	cmd.Flags().StringVarP(&opts.ResourceKind, "kind", "k", "", "Kind to use for resources. Required by --only-spec")
+	cmd.Flags().BoolVar(&opts.DeriveUIDs, "derive-uids", false, "give dashboards without UID one derived from their title, instead of failing")
 
 	cmdRun := cmd.Run
 	cmd.Run = func(cmd *cli.Command, args []string) error {
@@ -556,11 +1483,66 @@ func initialiseOnlySpec(cmd *cli.Command, opts *Opts) *cli.Command {
 	return cmd
 }
 
+// initialiseOverlays adds the flags patching the resources parsed with
+// overlays and transforms
+func initialiseOverlays(cmd *cli.Command, opts *Opts) *cli.Command {
+	cmd.Flags().StringArrayVar(&opts.Overlays, "overlay", nil, "patch the resources parsed with the resources of this file, merged onto the resources of the same kind and name, can be repeated")
+	cmd.Flags().StringArrayVar(&opts.Transforms, "transform", nil, "run the resources parsed through the Jsonnet function of this file, after those of the context, can be repeated")
+	return cmd
+}
+
+// initialiseKindFilter adds the flag narrowing the resources parsed down to
+// some kinds (ex: only datasources)
+func initialiseKindFilter(cmd *cli.Command, opts *Opts) *cli.Command {
+	cmd.Flags().StringArrayVar(&opts.OnlyKinds, "only-kind", nil, "only process resources of this kind (ex: Datasource), can be repeated")
+	return cmd
+}
+
+const (
+	eventFormatText = "text"
+	eventFormatJSON = "json"
+)
+
+// initialiseEventFormat adds the flag selecting how the events of commands
+// reporting on each resource (pull, apply, export) are written
+func initialiseEventFormat(cmd *cli.Command, opts *Opts) *cli.Command {
+	cmd.Flags().StringVar(&opts.EventFormat, "event-format", eventFormatText, "format of the events reported for each resource, one of text, json")
+
+	cmdRun := cmd.Run
+	cmd.Run = func(cmd *cli.Command, args []string) error {
+		switch opts.EventFormat {
+		case eventFormatText:
+		case eventFormatJSON:
+			// keep stdout for events only, so that it can be consumed line by line
+			grizzly.SetOutput(os.Stderr)
+		default:
+			return fmt.Errorf("unknown event format %q, expected one of: %s, %s", opts.EventFormat, eventFormatText, eventFormatJSON)
+		}
+		return cmdRun(cmd, args)
+	}
+
+	return cmd
+}
+
 func initialiseLogging(cmd *cli.Command, loggingOpts *LoggingOpts) *cli.Command {
 	cmd.Flags().StringVarP(&loggingOpts.LogLevel, "log-level", "l", log.InfoLevel.String(), "info, debug, warning, error")
+	cmd.Flags().BoolVar(&loggingOpts.Verbose, "verbose", false, "log debug messages, same as --log-level debug")
+	cmd.Flags().BoolVar(&loggingOpts.Quiet, "quiet", false, "only log errors, same as --log-level error")
 	cmdRun := cmd.Run
 	cmd.Run = func(cmd *cli.Command, args []string) error {
-		logLevel, err := log.ParseLevel(loggingOpts.LogLevel)
+		level := loggingOpts.LogLevel
+		switch {
+		case loggingOpts.Verbose && loggingOpts.Quiet:
+			return fmt.Errorf("--verbose and --quiet can't be used together")
+		case (loggingOpts.Verbose || loggingOpts.Quiet) && cmd.Flags().Changed("log-level"):
+			return fmt.Errorf("--verbose and --quiet can't be used with --log-level")
+		case loggingOpts.Verbose:
+			level = log.DebugLevel.String()
+		case loggingOpts.Quiet:
+			level = log.ErrorLevel.String()
+		}
+
+		logLevel, err := log.ParseLevel(level)
 		if err != nil {
 			return err
 		}
@@ -576,13 +1558,44 @@ func getDefaultJsonnetFolders() []string {
 }
 
 func getEventsRecorder(opts Opts) grizzly.EventsRecorder {
-	wr := grizzly.NewWriterRecorder(os.Stdout, getEventFormatter())
+	wr := grizzly.NewWriterRecorder(os.Stdout, getEventFormatter(opts))
 	if opts.DisableStats || config.UsageStatsDisabled() {
 		return wr
 	}
 	return grizzly.NewUsageRecorder(wr)
 }
 
+// parseSelector parses the --selector expressions, adding a requirement for
+// each --tag
+func parseSelector(selectors []string, tags []string) (grizzly.Selector, error) {
+	selector, err := grizzly.ParseSelector(selectors)
+	if err != nil {
+		return nil, err
+	}
+	return selector.WithTags(tags), nil
+}
+
+// filterRefs narrows resources down to the referenced ones, warning about the
+// references matching no resource
+func filterRefs(registry grizzly.Registry, resources grizzly.Resources, refs []string) grizzly.Resources {
+	filtered, unmatched := registry.FilterRefs(resources, refs)
+	for _, ref := range unmatched {
+		notifier.Warn(nil, fmt.Sprintf("No resource matches --only %s", ref))
+	}
+	return filtered
+}
+
+// useOfflineRemote reads the resources of an export directory, and returns a
+// registry comparing local resources to them instead of the remote endpoints
+func useOfflineRemote(registry grizzly.Registry, parser grizzly.Parser, exportDir string) (grizzly.Registry, error) {
+	remote, err := grizzly.ParseExport(registry, parser, exportDir, grizzly.ParserOptions{})
+	if err != nil {
+		return registry, fmt.Errorf("reading remote directory: %w", err)
+	}
+	notifier.Info(nil, fmt.Sprintf("Comparing to the %s exported in %s", grizzly.Pluraliser(remote.Len(), "resource"), exportDir))
+	return registry.WithOfflineRemote(remote), nil
+}
+
 func getOutputFormat(opts Opts) (string, bool, error) {
 	var onlySpec bool
 	context, err := config.CurrentContext()
@@ -618,10 +1631,16 @@ func getOnlySpec(opts Opts) (string, string, error) {
 	return kind, folderUID, nil
 }
 
-func getEventFormatter() grizzly.EventFormatter {
-	if terminal.IsTerminal(int(os.Stdout.Fd())) {
-		return grizzly.EventToColoredText
+func getEventFormatter(opts Opts) grizzly.EventFormatter {
+	formatter := grizzly.EventToPlainText
+	if opts.EventFormat == eventFormatJSON {
+		formatter = grizzly.EventToJSON
+	} else if !color.NoColor && terminal.IsTerminal(int(os.Stdout.Fd())) {
+		formatter = grizzly.EventToColoredText
 	}
 
-	return grizzly.EventToPlainText
+	if opts.OnlyChanges {
+		return grizzly.OnlyChanges(formatter)
+	}
+	return formatter
 }
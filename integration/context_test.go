@@ -61,6 +61,27 @@ func TestContexts(t *testing.T) {
 		})
 	})
 
+	t.Run("Override context for a single command", func(t *testing.T) {
+		runTest(t, GrizzlyTest{
+			TestDir: dir,
+			Commands: []Command{
+				{
+					Command:             "list -r --context unknown",
+					ExpectedLogsContain: "context unknown not found",
+					ExpectedCode:        1,
+				},
+				{
+					Command:      "list -r -t Dashboard --context subpath",
+					ExpectedCode: 0,
+				},
+				{
+					Command:        "config current-context",
+					ExpectedOutput: "default",
+				},
+			},
+		})
+	})
+
 	t.Run("Unset value", func(t *testing.T) {
 		runTest(t, GrizzlyTest{
 			TestDir: dir,
@@ -172,7 +172,7 @@ func TestDashboard(t *testing.T) {
 				{
 					Command:                "apply continue-on-error/workflow",
 					ExpectedCode:           1,
-					ExpectedOutputContains: "Dashboard.dashboard-2 failed: cannot upload dashboard dashboard-2 as folder non-existent-folder not found",
+					ExpectedOutputContains: "Dashboard.dashboard-2 failed: could not find folder 'non-existent-folder' for dashboard 'dashboard-2'",
 				},
 			},
 		})
@@ -186,8 +186,8 @@ func TestDashboard(t *testing.T) {
 					Command:      "apply -e continue-on-error/workflow",
 					ExpectedCode: 1,
 					ExpectedOutputContainsAll: []string{
-						"Dashboard.dashboard-2 failed: cannot upload dashboard dashboard-2 as folder non-existent-folder not found",
-						"Dashboard.dashboard-3 failed: cannot upload dashboard dashboard-3 as folder non-existent-folder not found",
+						"Dashboard.dashboard-2 failed: could not find folder 'non-existent-folder' for dashboard 'dashboard-2'",
+						"Dashboard.dashboard-3 failed: could not find folder 'non-existent-folder' for dashboard 'dashboard-3'",
 					},
 				},
 			},
@@ -252,7 +252,8 @@ func TestDashboardHandler(t *testing.T) {
 		}
 
 		err := handler.Add(resource)
-		require.EqualError(t, err, "cannot upload dashboard dummy as folder dummy not found")
+		require.ErrorContains(t, err, "could not find folder 'dummy' for dashboard 'dummy'")
+		require.ErrorIs(t, err, grizzly.ErrNotFound)
 	})
 
 	t.Run("Check getUID is functioning correctly", func(t *testing.T) {
@@ -1,6 +1,7 @@
 package httputils
 
 import (
+	"crypto/tls"
 	"net/http"
 	"os"
 	"strconv"
@@ -27,3 +28,15 @@ func NewHTTPClient() (*http.Client, error) {
 		Transport: &LoggedHTTPRoundTripper{},
 	}, nil
 }
+
+// NewTransport returns an HTTP transport using the given TLS configuration,
+// honoring the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables, and
+// accepting compressed responses.
+func NewTransport(tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.TLSClientConfig = tlsConfig
+	// responses are requested gzipped, and transparently decompressed
+	transport.DisableCompression = false
+	return transport
+}
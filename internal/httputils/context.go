@@ -0,0 +1,58 @@
+package httputils
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// ContextRoundTripper aborts the requests it sends once its context is done,
+// while keeping the context (and deadline) of each request.
+type ContextRoundTripper struct {
+	Context            context.Context
+	DecoratedTransport http.RoundTripper
+}
+
+func (rt ContextRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := http.DefaultTransport
+	if rt.DecoratedTransport != nil {
+		transport = rt.DecoratedTransport
+	}
+	if rt.Context == nil {
+		return transport.RoundTrip(req)
+	}
+	if err := rt.Context.Err(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancelCause(req.Context())
+	stop := context.AfterFunc(rt.Context, func() {
+		cancel(context.Cause(rt.Context))
+	})
+	release := func() {
+		stop()
+		cancel(nil)
+	}
+
+	resp, err := transport.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		release()
+		return nil, err
+	}
+	// the response body is read after RoundTrip returns: the link between
+	// both contexts is only released once it is closed
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody is a response body calling release once closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,80 @@
+package httputils
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestContextRoundTripper(t *testing.T) {
+	t.Run("releases the request context once the body is closed", func(t *testing.T) {
+		var sent *http.Request
+		rt := ContextRoundTripper{
+			Context: context.Background(),
+			DecoratedTransport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				sent = req
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+			}),
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/api/health", nil)
+		require.NoError(t, err)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, sent.Context().Err())
+
+		require.NoError(t, resp.Body.Close())
+		require.ErrorIs(t, sent.Context().Err(), context.Canceled)
+	})
+
+	t.Run("releases the request context when the request fails", func(t *testing.T) {
+		var sent *http.Request
+		rt := ContextRoundTripper{
+			Context: context.Background(),
+			DecoratedTransport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				sent = req
+				return nil, errors.New("connection refused")
+			}),
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/api/health", nil)
+		require.NoError(t, err)
+		_, err = rt.RoundTrip(req)
+		require.Error(t, err)
+		require.ErrorIs(t, sent.Context().Err(), context.Canceled)
+	})
+
+	t.Run("aborts the request once its context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		var sent *http.Request
+		rt := ContextRoundTripper{
+			Context: ctx,
+			DecoratedTransport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				sent = req
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+			}),
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "http://localhost/api/health", nil)
+		require.NoError(t, err)
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		stopped := errors.New("stopped")
+		cancel(stopped)
+		<-sent.Context().Done()
+		require.ErrorIs(t, context.Cause(sent.Context()), stopped)
+	})
+}
@@ -17,16 +17,23 @@ func (rt LoggedHTTPRoundTripper) RoundTrip(req *http.Request) (*http.Response, e
 		transport = rt.DecoratedTransport
 	}
 
-	reqStr, _ := httputil.DumpRequest(req, true)
-	log.Traceln(string(reqStr))
+	// dumping bodies reads them in memory: only do it when they get logged
+	tracing := log.IsLevelEnabled(log.TraceLevel)
+
+	if tracing {
+		reqStr, _ := httputil.DumpRequest(req, true)
+		log.Traceln(string(reqStr))
+	}
 
 	resp, err := transport.RoundTrip(req)
 	if err != nil {
 		return resp, err
 	}
 
-	respStr, _ := httputil.DumpResponse(resp, true)
-	log.Traceln(string(respStr))
+	if tracing {
+		respStr, _ := httputil.DumpResponse(resp, true)
+		log.Traceln(string(respStr))
+	}
 
 	return resp, err
 }
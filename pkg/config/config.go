@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
@@ -22,23 +24,36 @@ const (
 	DisableReportingSetting = "disable-reporting"
 )
 
+// ConfigFileEnv is the environment variable holding the path to a
+// configuration file to use instead of the default ones
+const ConfigFileEnv = "GRIZZLY_CONFIG"
+
 // Version is the current version of the grr command.
 // To be overwritten at build time
 var Version = "dev"
 
+// contextOverride holds the name of a context selected for a single
+// invocation. When set, it takes precedence over the current context.
+var contextOverride string
+
 func Initialise() {
-	viper.SetConfigName("settings")
+	if path := os.Getenv(ConfigFileEnv); path != "" {
+		viper.SetConfigFile(path)
+	} else {
+		viper.SetConfigName("settings")
+		viper.AddConfigPath(".")
+		viper.AddConfigPath(configdir.LocalConfig("grizzly"))
+	}
 	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath(configdir.LocalConfig("grizzly"))
 	viper.SetConfigPermissions(0600)
 }
 
 func override(v *viper.Viper) {
 	bindings := map[string]string{
-		"grafana.url":   "GRAFANA_URL",
-		"grafana.user":  "GRAFANA_USER",
-		"grafana.token": "GRAFANA_TOKEN",
+		"grafana.url":    "GRAFANA_URL",
+		"grafana.user":   "GRAFANA_USER",
+		"grafana.token":  "GRAFANA_TOKEN",
+		"grafana.org-id": "GRAFANA_ORG_ID",
 
 		"synthetic-monitoring.access-token": "GRAFANA_SM_ACCESS_TOKEN",
 		"synthetic-monitoring.token":        "GRAFANA_SM_TOKEN",
@@ -53,11 +68,13 @@ func override(v *viper.Viper) {
 		"mimir.auth-token": "MIMIR_AUTH_TOKEN",
 	}
 
-	// To keep retro compatibility
+	// To keep retro compatibility, and to read the variables set for other
+	// tools (ex: basic auth credentials as GRAFANA_USER/GRAFANA_PASSWORD)
 	legacyBindings := map[string]string{
 		"MIMIR_ADDRESS":   "CORTEX_ADDRESS",
 		"MIMIR_TENANT_ID": "CORTEX_TENANT_ID",
 		"MIMIR_API_KEY":   "CORTEX_API_KEY",
+		"GRAFANA_TOKEN":   "GRAFANA_PASSWORD",
 	}
 
 	for key, env := range bindings {
@@ -82,7 +99,9 @@ func getVal(env string, alternativeMap map[string]string) string {
 func Read() error {
 	err := viper.ReadInConfig()
 	if err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+		// an explicit configuration file that doesn't exist yet is created
+		// on the first write, like the default one
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok || errors.Is(err, fs.ErrNotExist) {
 			NewConfig()
 		} else {
 			return err
@@ -149,16 +168,45 @@ func UseContext(context string) error {
 	return fmt.Errorf("context %s not found", context)
 }
 
+// OverrideContext selects the context to use for the current invocation only.
+// Unlike UseContext, the selection is not persisted to the configuration file.
+func OverrideContext(context string) error {
+	contexts, err := GetContexts()
+	if err != nil {
+		return err
+	}
+	for _, k := range contexts {
+		if k == context {
+			contextOverride = context
+			return nil
+		}
+	}
+	return fmt.Errorf("context %s not found", context)
+}
+
+func currentContextName() string {
+	if contextOverride != "" {
+		return contextOverride
+	}
+	return viper.GetString(CurrentContextSetting)
+}
+
 func UsageStatsDisabled() bool {
 	return viper.GetBool(DisableReportingSetting)
 }
 
 func CurrentContext() (*Context, error) {
-	name := viper.GetString(CurrentContextSetting)
+	name := currentContextName()
 	if name == "" {
 		NewConfig()
 		return CurrentContext()
 	}
+	return GetContext(name)
+}
+
+// GetContext returns the context of the given name, with the overrides of the
+// environment applied
+func GetContext(name string) (*Context, error) {
 	contextPath := fmt.Sprintf("contexts.%s", name)
 	ctx := viper.Sub(contextPath)
 	if ctx == nil {
@@ -179,6 +227,16 @@ var acceptableKeys = map[string]string{
 	"grafana.user":                      "string",
 	"grafana.insecure-skip-verify":      "bool",
 	"grafana.tls-host":                  "string",
+	"grafana.ca-path":                   "string",
+	"grafana.org-id":                    "int",
+	"grafana.ignore-variable-values":    "bool",
+	"grafana.diff-ignore":               "[]string",
+	"grafana.keep-panel-order":          "bool",
+	"grafana.prevent-overwrite":         "bool",
+	"grafana.read-only-dashboards":      "bool",
+	"grafana.keep-dashboard-ids":        "bool",
+	"grafana.dashboard-message":         "string",
+	"grafana.reports":                   "bool",
 	"mimir.address":                     "string",
 	"mimir.tenant-id":                   "string",
 	"mimir.api-key":                     "string",
@@ -192,6 +250,9 @@ var acceptableKeys = map[string]string{
 	"targets":                           "[]string",
 	"output-format":                     "string",
 	"only-spec":                         "bool",
+	"folder-override":                   "string",
+	"jsonnet-dashboard-keys":            "[]string",
+	"transforms":                        "[]string",
 }
 
 func Hash() (string, error) {
@@ -360,3 +421,17 @@ func (c *Context) GetTargets(overrides []string) []string {
 	}
 	return c.Targets
 }
+
+// GetFolderOverride returns the folder to move resources to, if any
+func (c *Context) GetFolderOverride(override string) string {
+	if override != "" {
+		return override
+	}
+	return c.FolderOverride
+}
+
+// GetTransforms returns the transforms to run over the resources parsed:
+// those of the context, then the additional ones
+func (c *Context) GetTransforms(additional []string) []string {
+	return append(append([]string{}, c.Transforms...), additional...)
+}
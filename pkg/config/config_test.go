@@ -0,0 +1,81 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFileEnv(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	t.Run("contexts are read from the given file", func(t *testing.T) {
+		req := require.New(t)
+		viper.Reset()
+
+		path := filepath.Join(t.TempDir(), "grizzly.yaml")
+		req.NoError(os.WriteFile(path, []byte(`apiVersion: v1alpha1
+current-context: staging
+contexts:
+  staging:
+    grafana:
+      url: https://staging.example.com
+      org-id: 2
+`), 0600))
+		t.Setenv(ConfigFileEnv, path)
+		// environment variables take precedence over the file
+		t.Setenv("GRAFANA_URL", "")
+		t.Setenv("GRAFANA_ORG_ID", "")
+
+		Initialise()
+		req.NoError(Read())
+
+		context, err := CurrentContext()
+		req.NoError(err)
+		req.Equal("staging", context.Name)
+		req.Equal("https://staging.example.com", context.Grafana.URL)
+		req.Equal(int64(2), context.Grafana.OrgID)
+	})
+
+	t.Run("basic auth credentials are read from GRAFANA_USER and GRAFANA_PASSWORD", func(t *testing.T) {
+		req := require.New(t)
+		viper.Reset()
+
+		t.Setenv(ConfigFileEnv, filepath.Join(t.TempDir(), "grizzly.yaml"))
+		t.Setenv("GRAFANA_URL", "https://grafana.example.com")
+		t.Setenv("GRAFANA_USER", "admin")
+		t.Setenv("GRAFANA_TOKEN", "")
+		t.Setenv("GRAFANA_PASSWORD", "secret")
+
+		Initialise()
+		req.NoError(Read())
+
+		context, err := CurrentContext()
+		req.NoError(err)
+		req.Equal("https://grafana.example.com", context.Grafana.URL)
+		req.Equal("admin", context.Grafana.User)
+		req.Equal("secret", context.Grafana.Token)
+
+		t.Setenv("GRAFANA_TOKEN", "token")
+		context, err = CurrentContext()
+		req.NoError(err)
+		req.Equal("token", context.Grafana.Token, "GRAFANA_TOKEN takes precedence")
+	})
+
+	t.Run("missing files are created on write", func(t *testing.T) {
+		req := require.New(t)
+		viper.Reset()
+
+		path := filepath.Join(t.TempDir(), "grizzly.yaml")
+		t.Setenv(ConfigFileEnv, path)
+
+		Initialise()
+		req.NoError(Read())
+		req.NoError(Set("grafana.url", "http://localhost:3000"))
+
+		req.FileExists(path)
+	})
+}
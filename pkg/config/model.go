@@ -6,6 +6,37 @@ type GrafanaConfig struct {
 	Token              string `yaml:"token" mapstructure:"token"`
 	InsecureSkipVerify bool   `yaml:"insecure-skip-verify" mapstructure:"insecure-skip-verify"`
 	TLSHost            string `yaml:"tls-host" mapstructure:"tls-host"`
+	CAPath             string `yaml:"ca-path,omitempty" mapstructure:"ca-path"`
+	// OrgID is the organization to work with. When 0, the current
+	// organization of the user or token is used.
+	OrgID int64 `yaml:"org-id,omitempty" mapstructure:"org-id"`
+	// IgnoreVariableValues ignores the values of dashboard template variables
+	// when comparing dashboards
+	IgnoreVariableValues bool `yaml:"ignore-variable-values,omitempty" mapstructure:"ignore-variable-values"`
+	// DiffIgnore lists the dashboard fields to ignore when comparing
+	// dashboards, replacing the default ones (id, version, iteration)
+	DiffIgnore []string `yaml:"diff-ignore,omitempty" mapstructure:"diff-ignore"`
+	// KeepPanelOrder compares the panels of dashboards in the order they are
+	// given, instead of sorting them by ID
+	KeepPanelOrder bool `yaml:"keep-panel-order,omitempty" mapstructure:"keep-panel-order"`
+	// PreventOverwrite refuses to update dashboards changed remotely since
+	// the version they specify, instead of overwriting them
+	PreventOverwrite bool `yaml:"prevent-overwrite,omitempty" mapstructure:"prevent-overwrite"`
+	// ReadOnlyDashboards marks the dashboards applied as not editable in the
+	// Grafana UI
+	ReadOnlyDashboards bool `yaml:"read-only-dashboards,omitempty" mapstructure:"read-only-dashboards"`
+	// KeepDashboardIDs saves dashboards with the numeric ID of the remote
+	// dashboard they update, so that references by ID keep working
+	KeepDashboardIDs bool `yaml:"keep-dashboard-ids,omitempty" mapstructure:"keep-dashboard-ids"`
+	// DashboardMessage is the message attached to the versions of the
+	// dashboards applied, where {file} is replaced by the file describing the
+	// dashboard and {version} by DashboardMessageVersion
+	DashboardMessage string `yaml:"dashboard-message,omitempty" mapstructure:"dashboard-message"`
+	// DashboardMessageVersion is an externally supplied version (ex: a commit
+	// SHA) given for each run, which is never stored
+	DashboardMessageVersion string `yaml:"-" mapstructure:"-"`
+	// Reports enables the Report kind, managing Grafana Enterprise reports
+	Reports bool `yaml:"reports,omitempty" mapstructure:"reports"`
 }
 
 type MimirConfig struct {
@@ -42,6 +73,13 @@ type Context struct {
 	OnlySpec            bool                      `yaml:"only-spec" mapstructure:"only-spec"`
 	ResourceKind        string                    `yaml:"resource-kind" mapstructure:"resource-kind"`
 	FolderUID           string                    `yaml:"folder-uid" mapstructure:"folder-uid"`
+	FolderOverride      string                    `yaml:"folder-override" mapstructure:"folder-override"`
+	// JsonnetDashboardKeys lists the keys of the Jsonnet output holding
+	// dashboards, in the style of monitoring mixins
+	JsonnetDashboardKeys []string `yaml:"jsonnet-dashboard-keys,omitempty" mapstructure:"jsonnet-dashboard-keys"`
+	// Transforms lists paths of Jsonnet transforms run over the resources
+	// parsed, such as org-wide policies
+	Transforms []string `yaml:"transforms,omitempty" mapstructure:"transforms"`
 }
 
 // Secrets returns all the secrets contained in the current context.
@@ -74,12 +74,12 @@ func (h *AlertRuleGroupHandler) GetSpecUID(resource grizzly.Resource) (string, e
 
 // GetByUID retrieves JSON for a resource from an endpoint, by UID
 func (h *AlertRuleGroupHandler) GetByUID(uid string) (*grizzly.Resource, error) {
-	return h.getRemoteAlertRuleGroup(uid)
+	return wrapRemoteAPIError(h.getRemoteAlertRuleGroup(uid))
 }
 
 // GetRemote retrieves a alertRuleGroup as a Resource
 func (h *AlertRuleGroupHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
-	return h.getRemoteAlertRuleGroup(resource.Name())
+	return wrapRemoteAPIError(h.getRemoteAlertRuleGroup(resource.Name()))
 }
 
 // ListRemote retrieves as list of UIDs of all remote resources
@@ -89,12 +89,12 @@ func (h *AlertRuleGroupHandler) ListRemote() ([]string, error) {
 
 // Add pushes a alertRuleGroup to Grafana via the API
 func (h *AlertRuleGroupHandler) Add(resource grizzly.Resource) error {
-	return h.createAlertRuleGroup(resource)
+	return wrapAPIError(h.createAlertRuleGroup(resource))
 }
 
 // Update pushes a alertRuleGroup to Grafana via the API
 func (h *AlertRuleGroupHandler) Update(existing, resource grizzly.Resource) error {
-	return h.putAlertRuleGroup(existing, resource)
+	return wrapAPIError(h.putAlertRuleGroup(existing, resource))
 }
 
 // getRemoteAlertRuleGroup retrieves a alertRuleGroup object from Grafana
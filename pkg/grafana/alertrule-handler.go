@@ -0,0 +1,192 @@
+package grafana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana-openapi-client-go/client/provisioning"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/grafana/grizzly/pkg/grizzly"
+)
+
+const AlertRuleKind = "AlertRule"
+
+var _ grizzly.Handler = &AlertRuleHandler{}
+
+// AlertRuleHandler is a Grizzly Handler for individual Grafana alert rules
+type AlertRuleHandler struct {
+	grizzly.BaseHandler
+}
+
+// NewAlertRuleHandler returns a new Grizzly Handler for individual Grafana alert rules
+func NewAlertRuleHandler(provider grizzly.Provider) *AlertRuleHandler {
+	return &AlertRuleHandler{
+		BaseHandler: grizzly.NewBaseHandler(provider, AlertRuleKind, false),
+	}
+}
+
+const (
+	alertRulePattern = "alert-rules/alertRule-%s.%s"
+)
+
+// ResourceFilePath returns the location on disk where a resource should be updated
+func (h *AlertRuleHandler) ResourceFilePath(resource grizzly.Resource, filetype string) string {
+	return fmt.Sprintf(alertRulePattern, resource.Name(), filetype)
+}
+
+// Prepare gets a resource ready for dispatch to the remote endpoint
+func (h *AlertRuleHandler) Prepare(existing *grizzly.Resource, resource grizzly.Resource) *grizzly.Resource {
+	if !resource.HasSpecString("uid") {
+		resource.SetSpecString("uid", resource.Name())
+	}
+	return &resource
+}
+
+// Unprepare removes server-managed elements from a remote resource ready for presentation/comparison
+func (h *AlertRuleHandler) Unprepare(resource grizzly.Resource) *grizzly.Resource {
+	for _, key := range []string{"id", "updated", "provenance"} {
+		resource.DeleteSpecKey(key)
+	}
+	return &resource
+}
+
+// Validate returns the uid of resource
+func (h *AlertRuleHandler) Validate(resource grizzly.Resource) error {
+	uid, exist := resource.GetSpecString("uid")
+	if exist && uid != resource.Name() {
+		return ErrUIDNameMismatch{UID: uid, Name: resource.Name()}
+	}
+	return nil
+}
+
+func (h *AlertRuleHandler) GetSpecUID(resource grizzly.Resource) (string, error) {
+	uid, ok := resource.GetSpecString("uid")
+	if !ok {
+		return "", fmt.Errorf("UID not specified")
+	}
+	return uid, nil
+}
+
+// GetByUID retrieves JSON for a resource from an endpoint, by UID
+func (h *AlertRuleHandler) GetByUID(uid string) (*grizzly.Resource, error) {
+	return wrapRemoteAPIError(h.getRemoteAlertRule(uid))
+}
+
+// GetRemote retrieves an alert rule as a Resource
+func (h *AlertRuleHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
+	return wrapRemoteAPIError(h.getRemoteAlertRule(resource.Name()))
+}
+
+// ListRemote retrieves as list of UIDs of all remote resources
+func (h *AlertRuleHandler) ListRemote() ([]string, error) {
+	return h.getRemoteAlertRuleList()
+}
+
+// Add pushes an alert rule to Grafana via the API
+func (h *AlertRuleHandler) Add(resource grizzly.Resource) error {
+	return wrapAPIError(h.postAlertRule(resource))
+}
+
+// Update pushes an alert rule to Grafana via the API
+func (h *AlertRuleHandler) Update(existing, resource grizzly.Resource) error {
+	return wrapAPIError(h.putAlertRule(resource))
+}
+
+// getRemoteAlertRule retrieves an alert rule object from Grafana
+func (h *AlertRuleHandler) getRemoteAlertRule(uid string) (*grizzly.Resource, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	alertRuleOk, err := client.Provisioning.GetAlertRule(uid)
+	if err != nil {
+		var gErr *provisioning.GetAlertRuleNotFound
+		if errors.As(err, &gErr) {
+			return nil, grizzly.ErrNotFound
+		}
+		return nil, err
+	}
+
+	// TODO: Turn spec into a real models.ProvisionedAlertRule object
+	spec, err := structToMap(alertRuleOk.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := grizzly.NewResource(h.APIVersion(), h.Kind(), uid, spec)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (h *AlertRuleHandler) getRemoteAlertRuleList() ([]string, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	alertRulesOk, err := client.Provisioning.GetAlertRules()
+	if err != nil {
+		return nil, err
+	}
+	alertRules := alertRulesOk.GetPayload()
+	uids := make([]string, len(alertRules))
+	for i, alertRule := range alertRules {
+		uids[i] = alertRule.UID
+	}
+	return uids, nil
+}
+
+func unmarshalAlertRule(resource grizzly.Resource) (*models.ProvisionedAlertRule, error) {
+	data, err := json.Marshal(resource.Spec())
+	if err != nil {
+		return nil, err
+	}
+	var rule models.ProvisionedAlertRule
+	err = json.Unmarshal(data, &rule)
+	if err != nil {
+		return nil, err
+	}
+	rule.ID = 0 // ids are instance-local, and must never be sent
+	return &rule, nil
+}
+
+func (h *AlertRuleHandler) postAlertRule(resource grizzly.Resource) error {
+	rule, err := unmarshalAlertRule(resource)
+	if err != nil {
+		return err
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	params := provisioning.NewPostAlertRuleParams().
+		WithBody(rule).
+		WithXDisableProvenance(&stringtrue)
+	_, err = client.Provisioning.PostAlertRule(params, nil)
+	return err
+}
+
+func (h *AlertRuleHandler) putAlertRule(resource grizzly.Resource) error {
+	rule, err := unmarshalAlertRule(resource)
+	if err != nil {
+		return err
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	params := provisioning.NewPutAlertRuleParams().
+		WithUID(resource.Name()).
+		WithBody(rule).
+		WithXDisableProvenance(&stringtrue)
+	_, err = client.Provisioning.PutAlertRule(params)
+	return err
+}
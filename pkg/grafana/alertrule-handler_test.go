@@ -0,0 +1,27 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertRuleHandler_Unprepare(t *testing.T) {
+	req := require.New(t)
+	handler := NewAlertRuleHandler(&Provider{})
+
+	resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "rule-uid", map[string]any{
+		"uid":        "rule-uid",
+		"id":         42,
+		"title":      "High CPU",
+		"updated":    "2024-01-01T00:00:00Z",
+		"provenance": "api",
+	})
+	req.NoError(err)
+
+	unprepared := handler.Unprepare(resource)
+
+	req.Equal(map[string]any{"uid": "rule-uid", "title": "High CPU"}, unprepared.Spec())
+	req.Equal("alert-rules/alertRule-rule-uid.yaml", handler.ResourceFilePath(*unprepared, "yaml"))
+}
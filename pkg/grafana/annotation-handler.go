@@ -0,0 +1,256 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana-openapi-client-go/client/annotations"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/grafana/grizzly/pkg/grizzly"
+)
+
+const AnnotationKind = "Annotation"
+
+// annotationIDTagPrefix prefixes the tag holding the name of the resource an
+// annotation was created from. Annotations having server-assigned IDs, this
+// tag is what allows finding them back, and updating them on later applies.
+const annotationIDTagPrefix = "grizzly-id:"
+
+// annotationListLimit is the maximum number of annotations retrieved when
+// listing remote annotations
+const annotationListLimit = 5000
+
+var _ grizzly.Handler = &AnnotationHandler{}
+
+// AnnotationHandler is a Grizzly Handler for Grafana annotations and regions
+type AnnotationHandler struct {
+	grizzly.BaseHandler
+}
+
+// NewAnnotationHandler returns a new Grizzly Handler for Grafana annotations
+func NewAnnotationHandler(provider grizzly.Provider) *AnnotationHandler {
+	return &AnnotationHandler{
+		BaseHandler: grizzly.NewBaseHandler(provider, AnnotationKind, false),
+	}
+}
+
+const (
+	annotationPattern = "annotations/annotation-%s.%s"
+)
+
+// annotation describes the managed fields of an annotation. A region is an
+// annotation with an end time.
+type annotation struct {
+	DashboardUID string   `json:"dashboardUID,omitempty"`
+	PanelID      int64    `json:"panelId,omitempty"`
+	Time         int64    `json:"time,omitempty"`
+	TimeEnd      int64    `json:"timeEnd,omitempty"`
+	Text         string   `json:"text"`
+	Tags         []string `json:"tags,omitempty"`
+}
+
+// ResourceFilePath returns the location on disk where a resource should be updated
+func (h *AnnotationHandler) ResourceFilePath(resource grizzly.Resource, filetype string) string {
+	return fmt.Sprintf(annotationPattern, resource.Name(), filetype)
+}
+
+// Validate checks that the resource describes an annotation
+func (h *AnnotationHandler) Validate(resource grizzly.Resource) error {
+	a, err := unmarshalAnnotation(resource)
+	if err != nil {
+		return err
+	}
+	if a.Text == "" {
+		return fmt.Errorf("annotation %s has no text", resource.Name())
+	}
+	for _, tag := range a.Tags {
+		if strings.HasPrefix(tag, annotationIDTagPrefix) {
+			return fmt.Errorf("annotation %s: tags prefixed by '%s' are reserved", resource.Name(), annotationIDTagPrefix)
+		}
+	}
+	return nil
+}
+
+// GetSpecUID returns the UID of an annotation, which is the name of the resource
+func (h *AnnotationHandler) GetSpecUID(resource grizzly.Resource) (string, error) {
+	return resource.Name(), nil
+}
+
+// GetByUID retrieves JSON for a resource from an endpoint, by UID
+func (h *AnnotationHandler) GetByUID(uid string) (*grizzly.Resource, error) {
+	remote, err := h.findAnnotation(uid)
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	a := annotation{
+		DashboardUID: remote.DashboardUID,
+		PanelID:      remote.PanelID,
+		Time:         remote.Time,
+		Text:         remote.Text,
+		Tags:         withoutAnnotationIDTag(remote.Tags),
+	}
+	// Grafana sets the end time of point annotations to their start time
+	if remote.TimeEnd != remote.Time {
+		a.TimeEnd = remote.TimeEnd
+	}
+
+	spec, err := structToMap(a)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := grizzly.NewResource(h.APIVersion(), h.Kind(), uid, spec)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// GetRemote retrieves an annotation as a Resource
+func (h *AnnotationHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
+	return h.GetByUID(resource.Name())
+}
+
+// ListRemote retrieves as list of UIDs of all remote annotations managed by Grizzly
+func (h *AnnotationHandler) ListRemote() ([]string, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	annotationType := "annotation"
+	limit := int64(annotationListLimit)
+	params := annotations.NewGetAnnotationsParams().
+		WithType(&annotationType).
+		WithLimit(&limit)
+	annotationsOk, err := client.Annotations.GetAnnotations(params)
+	if err != nil {
+		return nil, err
+	}
+
+	uids := []string{}
+	for _, remote := range annotationsOk.GetPayload() {
+		if uid, ok := annotationIDFromTags(remote.Tags); ok {
+			uids = append(uids, uid)
+		}
+	}
+	sort.Strings(uids)
+	return uids, nil
+}
+
+// Add pushes an annotation to Grafana via the API
+func (h *AnnotationHandler) Add(resource grizzly.Resource) error {
+	a, err := unmarshalAnnotation(resource)
+	if err != nil {
+		return err
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Annotations.PostAnnotation(&models.PostAnnotationsCmd{
+		DashboardUID: a.DashboardUID,
+		PanelID:      a.PanelID,
+		Time:         a.Time,
+		TimeEnd:      a.TimeEnd,
+		Text:         &a.Text,
+		Tags:         append(a.Tags, annotationIDTag(resource.Name())),
+	})
+	return wrapAPIError(err)
+}
+
+// Update pushes an annotation to Grafana via the API
+func (h *AnnotationHandler) Update(existing, resource grizzly.Resource) error {
+	a, err := unmarshalAnnotation(resource)
+	if err != nil {
+		return err
+	}
+
+	remote, err := h.findAnnotation(resource.Name())
+	if err != nil {
+		return wrapAPIError(err)
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Annotations.UpdateAnnotation(strconv.FormatInt(remote.ID, 10), &models.UpdateAnnotationsCmd{
+		ID:      remote.ID,
+		Time:    a.Time,
+		TimeEnd: a.TimeEnd,
+		Text:    a.Text,
+		Tags:    append(a.Tags, annotationIDTag(resource.Name())),
+	})
+	return wrapAPIError(err)
+}
+
+// findAnnotation retrieves the annotation created from the resource with the given name
+func (h *AnnotationHandler) findAnnotation(uid string) (*models.Annotation, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	annotationType := "annotation"
+	params := annotations.NewGetAnnotationsParams().
+		WithType(&annotationType).
+		WithTags([]string{annotationIDTag(uid)})
+	annotationsOk, err := client.Annotations.GetAnnotations(params)
+	if err != nil {
+		return nil, err
+	}
+
+	found := annotationsOk.GetPayload()
+	switch len(found) {
+	case 0:
+		return nil, grizzly.ErrNotFound
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("%d annotations are tagged '%s', expected at most one", len(found), annotationIDTag(uid))
+	}
+}
+
+func annotationIDTag(uid string) string {
+	return annotationIDTagPrefix + uid
+}
+
+func annotationIDFromTags(tags []string) (string, bool) {
+	for _, tag := range tags {
+		if uid, found := strings.CutPrefix(tag, annotationIDTagPrefix); found {
+			return uid, true
+		}
+	}
+	return "", false
+}
+
+func withoutAnnotationIDTag(tags []string) []string {
+	filtered := []string{}
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, annotationIDTagPrefix) {
+			filtered = append(filtered, tag)
+		}
+	}
+	return filtered
+}
+
+func unmarshalAnnotation(resource grizzly.Resource) (*annotation, error) {
+	data, err := json.Marshal(resource.Spec())
+	if err != nil {
+		return nil, err
+	}
+	var a annotation
+	err = json.Unmarshal(data, &a)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
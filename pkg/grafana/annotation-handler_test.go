@@ -0,0 +1,51 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationHandler_Validate(t *testing.T) {
+	handler := NewAnnotationHandler(&Provider{})
+
+	t.Run("text is required", func(t *testing.T) {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "release-1.2.0", map[string]any{"time": 1700000000000})
+		require.NoError(t, err)
+		require.ErrorContains(t, handler.Validate(resource), "has no text")
+	})
+
+	t.Run("id tags are reserved", func(t *testing.T) {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "release-1.2.0", map[string]any{
+			"text": "Release 1.2.0",
+			"tags": []any{"release", "grizzly-id:other"},
+		})
+		require.NoError(t, err)
+		require.ErrorContains(t, handler.Validate(resource), "reserved")
+	})
+
+	t.Run("valid annotation", func(t *testing.T) {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "release-1.2.0", map[string]any{
+			"text": "Release 1.2.0",
+			"time": 1700000000000,
+			"tags": []any{"release"},
+		})
+		require.NoError(t, err)
+		require.NoError(t, handler.Validate(resource))
+	})
+}
+
+func TestAnnotationIDTag(t *testing.T) {
+	req := require.New(t)
+
+	tags := []string{"release", annotationIDTag("release-1.2.0")}
+
+	uid, found := annotationIDFromTags(tags)
+	req.True(found)
+	req.Equal("release-1.2.0", uid)
+	req.Equal([]string{"release"}, withoutAnnotationIDTag(tags))
+
+	_, found = annotationIDFromTags([]string{"release"})
+	req.False(found)
+}
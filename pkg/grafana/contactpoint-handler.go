@@ -65,12 +65,12 @@ func (h *AlertContactPointHandler) GetSpecUID(resource grizzly.Resource) (string
 
 // GetByUID retrieves JSON for a resource from an endpoint, by UID
 func (h *AlertContactPointHandler) GetByUID(uid string) (*grizzly.Resource, error) {
-	return h.getRemoteContactPoint(uid)
+	return wrapRemoteAPIError(h.getRemoteContactPoint(uid))
 }
 
 // GetRemote retrieves a contactPoint as a Resource
 func (h *AlertContactPointHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
-	return h.getRemoteContactPoint(resource.Name())
+	return wrapRemoteAPIError(h.getRemoteContactPoint(resource.Name()))
 }
 
 // ListRemote retrieves as list of UIDs of all remote resources
@@ -80,12 +80,12 @@ func (h *AlertContactPointHandler) ListRemote() ([]string, error) {
 
 // Add pushes a contactPoint to Grafana via the API
 func (h *AlertContactPointHandler) Add(resource grizzly.Resource) error {
-	return h.postContactPoint(resource)
+	return wrapAPIError(h.postContactPoint(resource))
 }
 
 // Update pushes a contactPoint to Grafana via the API
 func (h *AlertContactPointHandler) Update(existing, resource grizzly.Resource) error {
-	return h.putContactPoint(resource)
+	return wrapAPIError(h.putContactPoint(resource))
 }
 
 // getRemoteContactPoint retrieves a contactPoint object from Grafana
@@ -0,0 +1,119 @@
+package grafana
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-openapi-client-go/models"
+)
+
+// datasourceIndex finds the datasources of a Grafana instance by name and UID
+type datasourceIndex struct {
+	byName map[string]*models.DataSourceListItemDTO
+	byUID  map[string]bool
+}
+
+func newDatasourceIndex(datasources []*models.DataSourceListItemDTO) datasourceIndex {
+	index := datasourceIndex{
+		byName: map[string]*models.DataSourceListItemDTO{},
+		byUID:  map[string]bool{},
+	}
+	for _, datasource := range datasources {
+		index.byName[datasource.Name] = datasource
+		index.byUID[datasource.UID] = true
+	}
+	return index
+}
+
+// dereferenceDatasources rewrites the references a dashboard makes to
+// datasources by name, which only hold on the instance the dashboard was
+// authored against, to references by UID ({type, uid}). References are
+// found on panels, their queries, template variables and annotations.
+//
+// It returns a description of each rewrite, along with the names of the
+// datasources that couldn't be found, whose references are left as is.
+func dereferenceDatasources(spec map[string]any, index datasourceIndex) ([]string, []string) {
+	var rewrites, unresolved []string
+	missingNames := map[string]bool{}
+	dereference := func(path string, rawContainer any) {
+		container, ok := rawContainer.(map[string]any)
+		if !ok {
+			return
+		}
+		rewrite, missing := index.dereference(container)
+		if rewrite != "" {
+			rewrites = append(rewrites, fmt.Sprintf("%s.datasource: %s", path, rewrite))
+		}
+		if missing != "" && !missingNames[missing] {
+			missingNames[missing] = true
+			unresolved = append(unresolved, missing)
+		}
+	}
+
+	var dereferencePanels func(path string, panels []any)
+	dereferencePanels = func(path string, panels []any) {
+		for i, rawPanel := range panels {
+			panelPath := fmt.Sprintf("%s[%d]", path, i)
+			dereference(panelPath, rawPanel)
+			panel, ok := rawPanel.(map[string]any)
+			if !ok {
+				continue
+			}
+			targets, _ := panel["targets"].([]any)
+			for j, target := range targets {
+				dereference(fmt.Sprintf("%s.targets[%d]", panelPath, j), target)
+			}
+			// collapsed rows hold their own panels
+			if rowPanels, ok := panel["panels"].([]any); ok {
+				dereferencePanels(panelPath+".panels", rowPanels)
+			}
+		}
+	}
+
+	panels, _ := spec["panels"].([]any)
+	dereferencePanels("panels", panels)
+	for _, section := range []string{"templating", "annotations"} {
+		container, _ := spec[section].(map[string]any)
+		list, _ := container["list"].([]any)
+		for i, item := range list {
+			dereference(fmt.Sprintf("%s.list[%d]", section, i), item)
+		}
+	}
+
+	return rewrites, unresolved
+}
+
+// dereference rewrites the datasource of a panel, query, variable or
+// annotation when it is referenced by name. It returns a description of the
+// rewrite, or the name of the datasource if it couldn't be found.
+func (index datasourceIndex) dereference(container map[string]any) (string, string) {
+	var name string
+	switch datasource := container["datasource"].(type) {
+	case string:
+		// legacy reference, by name
+		name = datasource
+	case map[string]any:
+		// reference whose UID was set to the name of the datasource
+		name, _ = datasource["uid"].(string)
+	}
+	if !isDatasourceName(name) || index.byUID[name] {
+		return "", ""
+	}
+
+	datasource, ok := index.byName[name]
+	if !ok {
+		return "", name
+	}
+	container["datasource"] = map[string]any{
+		"type": datasource.Type,
+		"uid":  datasource.UID,
+	}
+	return fmt.Sprintf("%s -> %s", name, datasource.UID), ""
+}
+
+// isDatasourceName checks whether a datasource reference may be the name of
+// a datasource, rather than a template variable (ex: ${datasource}) or a
+// built-in datasource (ex: -- Grafana --)
+func isDatasourceName(ref string) bool {
+	return ref != "" && ref != "grafana" && !strings.HasPrefix(ref, "$") && !strings.HasPrefix(ref, "-- ")
+}
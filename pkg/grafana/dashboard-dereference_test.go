@@ -0,0 +1,91 @@
+package grafana
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/grafana/grizzly/pkg/config"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDereferenceDatasources(t *testing.T) {
+	index := newDatasourceIndex([]*models.DataSourceListItemDTO{
+		{Name: "Prometheus", UID: "prom-uid", Type: "prometheus"},
+		{Name: "Loki", UID: "loki-uid", Type: "loki"},
+	})
+	spec := map[string]any{
+		"panels": []any{
+			map[string]any{
+				"datasource": "Prometheus",
+				"targets": []any{
+					map[string]any{"datasource": map[string]any{"type": "prometheus", "uid": "Prometheus"}},
+					map[string]any{"datasource": map[string]any{"type": "prometheus", "uid": "prom-uid"}},
+				},
+			},
+			map[string]any{
+				"type": "row",
+				"panels": []any{
+					map[string]any{"datasource": "${datasource}"},
+					map[string]any{"datasource": "-- Grafana --"},
+					map[string]any{"datasource": "Elasticsearch"},
+				},
+			},
+		},
+		"templating": map[string]any{
+			"list": []any{
+				map[string]any{"name": "job", "datasource": "Loki"},
+			},
+		},
+		"annotations": map[string]any{
+			"list": []any{
+				map[string]any{"datasource": map[string]any{"type": "datasource", "uid": "grafana"}},
+				map[string]any{"datasource": "Elasticsearch"},
+			},
+		},
+	}
+
+	rewrites, unresolved := dereferenceDatasources(spec, index)
+	require.Equal(t, []string{
+		"panels[0].datasource: Prometheus -> prom-uid",
+		"panels[0].targets[0].datasource: Prometheus -> prom-uid",
+		"templating.list[0].datasource: Loki -> loki-uid",
+	}, rewrites)
+	require.Equal(t, []string{"Elasticsearch"}, unresolved, "missing datasources are reported once")
+
+	panel := spec["panels"].([]any)[0].(map[string]any)
+	require.Equal(t, map[string]any{"type": "prometheus", "uid": "prom-uid"}, panel["datasource"])
+	rowPanels := spec["panels"].([]any)[1].(map[string]any)["panels"].([]any)
+	require.Equal(t, "${datasource}", rowPanels[0].(map[string]any)["datasource"], "variables are left as is")
+	require.Equal(t, "-- Grafana --", rowPanels[1].(map[string]any)["datasource"], "built-in datasources are left as is")
+	require.Equal(t, "Elasticsearch", rowPanels[2].(map[string]any)["datasource"])
+}
+
+func TestDashboardHandler_Dereference(t *testing.T) {
+	req := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/datasources" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]map[string]any{{"name": "Prometheus", "uid": "prom-uid", "type": "prometheus"}})
+	}))
+	t.Cleanup(server.Close)
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+
+	resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "abc", map[string]any{
+		"uid":    "abc",
+		"panels": []any{map[string]any{"datasource": "Prometheus"}},
+	})
+	req.NoError(err)
+
+	dereferenced, rewrites, err := handler.Dereference(resource)
+	req.NoError(err)
+	req.Equal([]string{"panels[0].datasource: Prometheus -> prom-uid"}, rewrites)
+	req.Equal(map[string]any{"type": "prometheus", "uid": "prom-uid"}, dereferenced.Spec()["panels"].([]any)[0].(map[string]any)["datasource"])
+	req.Equal("Prometheus", resource.Spec()["panels"].([]any)[0].(map[string]any)["datasource"], "the given resource is left untouched")
+}
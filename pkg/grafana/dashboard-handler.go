@@ -1,16 +1,25 @@
 package grafana
 
 import (
+	"crypto/sha256"
 	_ "embed"
 	"errors"
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/grafana/grafana-openapi-client-go/client/dashboard_versions"
 	"github.com/grafana/grafana-openapi-client-go/client/dashboards"
 	"github.com/grafana/grafana-openapi-client-go/client/search"
 	"github.com/grafana/grafana-openapi-client-go/models"
 	"github.com/grafana/grizzly/pkg/grizzly"
 	"github.com/grafana/grizzly/pkg/grizzly/notifier"
+	"golang.org/x/sync/errgroup"
 )
 
 // Moved from utils.go
@@ -21,16 +30,97 @@ const DashboardKind = "Dashboard"
 
 var _ grizzly.Handler = &DashboardHandler{}
 var _ grizzly.ProxyConfiguratorProvider = &DashboardHandler{}
+var _ grizzly.DiffIgnoreHandler = &DashboardHandler{}
+var _ grizzly.CanonicalizeHandler = &DashboardHandler{}
+var _ grizzly.SnapshotHandler = &DashboardHandler{}
+var _ grizzly.RenderHandler = &DashboardHandler{}
+var _ grizzly.SnapshotManager = &DashboardHandler{}
+var _ grizzly.RollbackHandler = &DashboardHandler{}
+var _ grizzly.VersionsHandler = &DashboardHandler{}
+var _ grizzly.UIDGeneratorHandler = &DashboardHandler{}
+var _ grizzly.AppliedDetailsHandler = &DashboardHandler{}
+var _ grizzly.BatchRemoteHandler = &DashboardHandler{}
+var _ grizzly.DereferenceHandler = &DashboardHandler{}
+var _ grizzly.RawRemoteHandler = &DashboardHandler{}
+var _ grizzly.BindableHandler = &DashboardHandler{}
+
+// searchUIDsBatchSize is the number of dashboards looked up per search
+const searchUIDsBatchSize = 100
+
+// dashboardFetchWorkers is the number of dashboards retrieved concurrently
+// by GetRemotes
+const dashboardFetchWorkers = 8
+
+// maxUIDLength is the maximum length of the UIDs accepted by Grafana
+const maxUIDLength = 40
 
 // DashboardHandler is a Grizzly Handler for Grafana dashboards
 type DashboardHandler struct {
 	grizzly.BaseHandler
+
+	// DiffIgnore lists the dashboard fields to ignore when comparing local and
+	// remote dashboards
+	DiffIgnore []string
+
+	// SortPanels sorts panels by ID when comparing local and remote
+	// dashboards, as Grafana doesn't necessarily keep them in the same order
+	SortPanels bool
+
+	// IgnoreVariableValues strips the current value and options of template
+	// variables when comparing local and remote dashboards, as Grafana saves
+	// them back whenever a variable is changed in the UI
+	IgnoreVariableValues bool
+
+	// PreventOverwrite refuses to update dashboards changed remotely since
+	// the version they specify, instead of overwriting them. Conflicts are
+	// reported as ErrConflict. Remote dashboards keep their version, so that
+	// pulled dashboards record the version they were pulled at.
+	PreventOverwrite bool
+
+	// ReadOnly marks the dashboards applied as not editable in the Grafana
+	// UI. Their editable field is then ignored when comparing dashboards.
+	ReadOnly bool
+
+	// KeepIDs saves dashboards with the numeric ID of the remote dashboard
+	// they update, fetched right before saving them. Grafana would otherwise
+	// be free to assign another ID, breaking references by ID (ex: legacy
+	// alerts on dashboard panels).
+	KeepIDs bool
+
+	// Message, when set, is attached to the dashboard versions saved (ex:
+	// "applied from {file} @ {version}"). {file} is replaced by the file
+	// describing the dashboard, and {version} by MessageVersion.
+	Message        string
+	MessageVersion string
+
+	// saved records the folder of the dashboards saved, along with the URL
+	// and version given by Grafana, by UID
+	saved sync.Map
 }
 
 // NewDashboardHandler returns configuration defining a new Grafana Dashboard Handler
 func NewDashboardHandler(provider grizzly.Provider) *DashboardHandler {
 	return &DashboardHandler{
 		BaseHandler: grizzly.NewBaseHandler(provider, DashboardKind, true),
+		// Grafana updates these fields every time a dashboard is saved
+		DiffIgnore: []string{"id", "version", "iteration"},
+		SortPanels: true,
+	}
+}
+
+// WithProvider returns a copy of the handler, with the same settings, using
+// the given provider. Dashboards saved by the handler aren't carried over.
+func (h *DashboardHandler) WithProvider(provider grizzly.Provider) grizzly.Handler {
+	return &DashboardHandler{
+		BaseHandler:          grizzly.NewBaseHandler(provider, h.Kind(), h.UsesFolders()),
+		DiffIgnore:           h.DiffIgnore,
+		SortPanels:           h.SortPanels,
+		IgnoreVariableValues: h.IgnoreVariableValues,
+		PreventOverwrite:     h.PreventOverwrite,
+		ReadOnly:             h.ReadOnly,
+		KeepIDs:              h.KeepIDs,
+		Message:              h.Message,
+		MessageVersion:       h.MessageVersion,
 	}
 }
 
@@ -53,10 +143,109 @@ func (h *DashboardHandler) ResourceFilePath(resource grizzly.Resource, filetype
 // Unprepare removes unnecessary elements from a remote resource ready for presentation/comparison
 func (h *DashboardHandler) Unprepare(resource grizzly.Resource) *grizzly.Resource {
 	resource.DeleteSpecKey("id")
-	resource.DeleteSpecKey("version")
+	if !h.PreventOverwrite {
+		resource.DeleteSpecKey("version")
+	}
 	return &resource
 }
 
+// DiffIgnorePaths lists the fields to ignore when comparing dashboards
+func (h *DashboardHandler) DiffIgnorePaths() []string {
+	return h.DiffIgnore
+}
+
+// Canonicalize returns a copy of a dashboard with its tags sorted, as their
+// order carries no meaning, and its panels, including the panels of collapsed
+// rows, sorted by ID. Panels without ID are kept last.
+//
+// With IgnoreVariableValues, the current value and options of template
+// variables are removed too.
+func (h *DashboardHandler) Canonicalize(resource grizzly.Resource) grizzly.Resource {
+	resource = resource.Clone()
+	sortTags(resource.Spec())
+	if h.SortPanels {
+		sortPanels(resource.Spec())
+	}
+	if h.IgnoreVariableValues {
+		stripVariableValues(resource.Spec())
+	}
+	if h.ReadOnly {
+		delete(resource.Spec(), "editable")
+	}
+	return resource
+}
+
+// sortTags sorts the tags of a dashboard
+func sortTags(dashboard map[string]any) {
+	tags, ok := dashboard["tags"].([]any)
+	if !ok {
+		return
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return fmt.Sprint(tags[i]) < fmt.Sprint(tags[j])
+	})
+}
+
+// stripVariableValues removes the values Grafana saves for template
+// variables: the selected value, and the options it was selected from
+func stripVariableValues(dashboard map[string]any) {
+	templating, ok := dashboard["templating"].(map[string]any)
+	if !ok {
+		return
+	}
+	variables, ok := templating["list"].([]any)
+	if !ok {
+		return
+	}
+
+	for _, variable := range variables {
+		if variable, ok := variable.(map[string]any); ok {
+			delete(variable, "current")
+			delete(variable, "options")
+		}
+	}
+}
+
+func sortPanels(container map[string]any) {
+	panels, ok := container["panels"].([]any)
+	if !ok {
+		return
+	}
+
+	sort.SliceStable(panels, func(i, j int) bool {
+		first, firstOk := panelID(panels[i])
+		second, secondOk := panelID(panels[j])
+		if firstOk && secondOk {
+			return first < second
+		}
+		return firstOk && !secondOk
+	})
+
+	for _, panel := range panels {
+		if panel, ok := panel.(map[string]any); ok {
+			sortPanels(panel)
+		}
+	}
+}
+
+func panelID(panel any) (float64, bool) {
+	panelMap, ok := panel.(map[string]any)
+	if !ok {
+		return 0, false
+	}
+
+	switch id := panelMap["id"].(type) {
+	case int:
+		return float64(id), true
+	case int64:
+		return float64(id), true
+	case float64:
+		return id, true
+	default:
+		return 0, false
+	}
+}
+
 // Prepare gets a resource ready for dispatch to the remote endpoint
 func (h *DashboardHandler) Prepare(existing *grizzly.Resource, resource grizzly.Resource) *grizzly.Resource {
 	if !resource.HasSpecString("uid") {
@@ -68,13 +257,23 @@ func (h *DashboardHandler) Prepare(existing *grizzly.Resource, resource grizzly.
 	return &resource
 }
 
-// Validate returns the uid of resource
+// Validate checks the uid of resource, and the structure of the dashboard
 func (h *DashboardHandler) Validate(resource grizzly.Resource) error {
 	uid, exist := resource.GetSpecString("uid")
 	if resource.Name() != uid && exist {
 		return fmt.Errorf("uid '%s' and name '%s', don't match", uid, resource.Name())
 	}
-	return nil
+	return h.validateSchema(resource)
+}
+
+// validateSchema checks the structure of a dashboard, reporting the fields
+// Grafana does without as warnings
+func (h *DashboardHandler) validateSchema(resource grizzly.Resource) error {
+	warnings, err := validateDashboardSchema(resource.Name(), resource.Spec())
+	for _, warning := range warnings {
+		notifier.Warn(resource, warning)
+	}
+	return err
 }
 
 func (h *DashboardHandler) GetSpecUID(resource grizzly.Resource) (string, error) {
@@ -85,11 +284,54 @@ func (h *DashboardHandler) GetSpecUID(resource grizzly.Resource) (string, error)
 	return uid, nil
 }
 
+// GenerateUID derives the UID of a dashboard from its title, so that the same
+// title always yields the same UID
+func (h *DashboardHandler) GenerateUID(resource *grizzly.Resource) (string, error) {
+	title, _ := resource.Spec()["title"].(string)
+	if title == "" {
+		return "", fmt.Errorf("UID not specified, and no title to derive it from")
+	}
+
+	uid := uidFromTitle(title)
+	resource.SetSpecString("uid", uid)
+	return uid, nil
+}
+
+// uidFromTitle turns a title into a slug (ex: "Team A: Overview" gives
+// "team-a-overview"). Titles giving slugs that are too long, or empty, are
+// shortened with a hash of the title.
+func uidFromTitle(title string) string {
+	var slug strings.Builder
+	separate := false
+	for _, r := range strings.ToLower(title) {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			separate = slug.Len() > 0
+			continue
+		}
+		if separate {
+			slug.WriteByte('-')
+			separate = false
+		}
+		slug.WriteRune(r)
+	}
+
+	uid := slug.String()
+	if uid != "" && len(uid) <= maxUIDLength {
+		return uid
+	}
+
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(title)))[:8]
+	if uid == "" {
+		return "dashboard-" + hash
+	}
+	return strings.TrimSuffix(uid[:maxUIDLength-len(hash)-1], "-") + "-" + hash
+}
+
 // GetByUID retrieves JSON for a resource from an endpoint, by UID
 func (h *DashboardHandler) GetByUID(uid string) (*grizzly.Resource, error) {
 	resource, err := h.getRemoteDashboard(uid)
 	if err != nil {
-		return nil, fmt.Errorf("Error retrieving dashboard %s: %w", uid, err)
+		return nil, fmt.Errorf("Error retrieving dashboard %s: %w", uid, wrapAPIError(err))
 	}
 	return resource, nil
 }
@@ -100,7 +342,71 @@ func (h *DashboardHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resour
 	if uid != resource.Name() {
 		return nil, ErrUIDNameMismatch{UID: uid, Name: resource.Name()}
 	}
-	return h.getRemoteDashboard(resource.Name())
+	return wrapRemoteAPIError(h.getRemoteDashboard(resource.Name()))
+}
+
+// GetRemotes searches for many dashboards at once, then retrieves the ones
+// existing in Grafana concurrently, as the search only returns summaries.
+// Dashboards whose UID doesn't match their name are left to GetRemote, which
+// reports the mismatch.
+func (h *DashboardHandler) GetRemotes(resources []grizzly.Resource) ([]grizzly.Resource, []string, error) {
+	uids := []string{}
+	for _, resource := range resources {
+		if uid, _ := resource.GetSpecString("uid"); uid == resource.Name() {
+			uids = append(uids, uid)
+		}
+	}
+	if len(uids) == 0 {
+		return nil, nil, nil
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	found := map[string]bool{}
+	searchType := "dash-db"
+	// UIDs are sent as query parameters: the URL is kept within the usual limits
+	for start := 0; start < len(uids); start += searchUIDsBatchSize {
+		batch := uids[start:min(start+searchUIDsBatchSize, len(uids))]
+		hits, err := searchAll(client, search.NewSearchParams().WithType(&searchType), withDashboardUIDs(batch))
+		if err != nil {
+			return nil, nil, wrapAPIError(err)
+		}
+		for _, hit := range hits {
+			found[hit.UID] = true
+		}
+	}
+
+	missing := []string{}
+	existingUIDs := []string{}
+	for _, uid := range uids {
+		if found[uid] {
+			existingUIDs = append(existingUIDs, uid)
+		} else {
+			missing = append(missing, uid)
+		}
+	}
+
+	existing := make([]grizzly.Resource, len(existingUIDs))
+	group := errgroup.Group{}
+	group.SetLimit(dashboardFetchWorkers)
+	for i, uid := range existingUIDs {
+		group.Go(func() error {
+			resource, err := h.getRemoteDashboard(uid)
+			if err != nil {
+				return fmt.Errorf("retrieving dashboard %s: %w", uid, wrapAPIError(err))
+			}
+			existing[i] = *resource
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	return existing, missing, nil
 }
 
 // ListRemote retrieves as list of UIDs of all remote resources
@@ -111,30 +417,183 @@ func (h *DashboardHandler) ListRemote() ([]string, error) {
 // Add pushes a new dashboard to Grafana via the API
 func (h *DashboardHandler) Add(resource grizzly.Resource) error {
 	resource = *h.Unprepare(resource)
-	return h.postDashboard(resource)
+	resource.DeleteSpecKey("version")
+	return wrapAPIError(h.postDashboard(resource, !h.PreventOverwrite))
 }
 
-// Update pushes a dashboard to Grafana via the API
+// Update pushes a dashboard to Grafana via the API. With PreventOverwrite,
+// the dashboard is only saved if the remote one is still at the version it
+// specifies, or at the version just retrieved if it specifies none. With
+// KeepIDs, the dashboard is saved with the ID of the remote one.
 func (h *DashboardHandler) Update(existing, resource grizzly.Resource) error {
 	resource = *h.Unprepare(resource)
-	return h.postDashboard(resource)
+	if h.KeepIDs {
+		var err error
+		resource, err = h.withRemoteID(resource)
+		if err != nil {
+			return err
+		}
+	}
+	if !h.PreventOverwrite {
+		return wrapAPIError(h.postDashboard(resource, true))
+	}
+
+	if resource.GetSpecValue("version") == nil {
+		resource.SetSpecValue("version", existing.GetSpecValue("version"))
+	}
+	err := wrapAPIError(h.postDashboard(resource, false))
+	if errors.Is(err, ErrConflict) {
+		return fmt.Errorf("dashboard %s was changed remotely since version %v: %w", resource.Name(), resource.GetSpecValue("version"), err)
+	}
+	return err
+}
+
+// withRemoteID returns a copy of a dashboard holding the numeric ID of the
+// remote dashboard of the same UID
+func (h *DashboardHandler) withRemoteID(resource grizzly.Resource) (grizzly.Resource, error) {
+	remote, err := h.getRemoteDashboard(resource.Name())
+	if err != nil {
+		return resource, wrapAPIError(err)
+	}
+	resource = resource.Clone()
+	if id := remote.GetSpecValue("id"); id != nil {
+		resource.SetSpecValue("id", id)
+	}
+	return resource, nil
+}
+
+// Dereference rewrites the datasources a dashboard references by name to
+// the UIDs they have in Grafana. Datasources are listed for each dashboard,
+// as they may have been applied along with it.
+func (h *DashboardHandler) Dereference(resource grizzly.Resource) (grizzly.Resource, []string, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return resource, nil, err
+	}
+	response, err := client.Datasources.GetDataSources()
+	if err != nil {
+		return resource, nil, err
+	}
+
+	dereferenced := resource.Clone()
+	rewrites, unresolved := dereferenceDatasources(dereferenced.Spec(), newDatasourceIndex(response.GetPayload()))
+	for _, name := range unresolved {
+		notifier.Warn(resource, fmt.Sprintf("datasource %s not found, its references are left as is", name))
+	}
+	return dereferenced, rewrites, nil
 }
 
 // Snapshot pushes dashboards as snapshots
-func (h *DashboardHandler) Snapshot(resource grizzly.Resource, expiresSeconds int) error {
-	s, err := h.postSnapshot(resource, expiresSeconds)
+func (h *DashboardHandler) Snapshot(resource grizzly.Resource, opts grizzly.SnapshotOptions) error {
+	client, err := h.Provider.(ClientProvider).Client()
 	if err != nil {
 		return err
 	}
+
+	s, err := postSnapshot(client, resource.Spec(), opts)
+	if err != nil {
+		return err
+	}
+	notifier.Info(resource, "key: "+s.Key)
 	notifier.Info(resource, "view: "+s.URL)
-	if expiresSeconds > 0 {
-		notifier.Warn(resource, fmt.Sprintf("Snapshots will expire and be deleted automatically in %d seconds\n", expiresSeconds))
+	if opts.ExpiresSeconds > 0 {
+		notifier.Warn(resource, fmt.Sprintf("Snapshots will expire and be deleted automatically in %d seconds\n", opts.ExpiresSeconds))
 	} else {
 		notifier.Error(resource, "delete: "+s.DeleteURL)
 	}
 	return nil
 }
 
+// Render renders a remote dashboard, or one of its panels, as a PNG image
+func (h *DashboardHandler) Render(resource grizzly.Resource, opts grizzly.RenderOptions) ([]byte, error) {
+	return renderDashboard(h.Provider.(ClientProvider), resource.Name(), opts)
+}
+
+// GetRemoteRaw returns the JSON model of a remote dashboard, as Grafana
+// stores it
+func (h *DashboardHandler) GetRemoteRaw(uid string) ([]byte, error) {
+	return getRawDashboard(h.Provider.(ClientProvider), uid)
+}
+
+// ListSnapshots retrieves the dashboard snapshots stored by Grafana
+func (h *DashboardHandler) ListSnapshots() ([]grizzly.SnapshotInfo, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+	return listSnapshots(client)
+}
+
+// DeleteSnapshot deletes a dashboard snapshot, by key
+func (h *DashboardHandler) DeleteSnapshot(key string) error {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+	return deleteSnapshot(client, key)
+}
+
+// Versions lists the versions of a dashboard kept by Grafana, latest first
+func (h *DashboardHandler) Versions(uid string) ([]grizzly.Version, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	params := dashboard_versions.NewGetDashboardVersionsByUIDParams().WithUID(uid)
+	versionsOk, err := client.DashboardVersions.GetDashboardVersionsByUID(params)
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	versions := make([]grizzly.Version, 0, len(versionsOk.GetPayload()))
+	for _, version := range versionsOk.GetPayload() {
+		versions = append(versions, grizzly.Version{
+			Version: version.Version,
+			Created: time.Time(version.Created).Format(time.RFC3339),
+			Author:  version.CreatedBy,
+			Message: version.Message,
+		})
+	}
+	return versions, nil
+}
+
+// Rollback restores a previous version of a dashboard, from the history kept
+// by Grafana
+func (h *DashboardHandler) Rollback(uid string, version int64) (*grizzly.Resource, int64, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Grafana doesn't report unknown versions clearly when restoring them
+	_, err = client.DashboardVersions.GetDashboardVersionByUID(uid, version)
+	if err != nil {
+		var gErr *dashboard_versions.GetDashboardVersionByUIDNotFound
+		if errors.As(err, &gErr) {
+			return nil, 0, fmt.Errorf("version %d of dashboard %s: %w", version, uid, grizzly.ErrNotFound)
+		}
+		return nil, 0, wrapAPIError(err)
+	}
+
+	restoreOk, err := client.DashboardVersions.RestoreDashboardVersionByUID(uid, &models.RestoreDashboardVersionCommand{
+		Version: version,
+	})
+	if err != nil {
+		return nil, 0, wrapAPIError(err)
+	}
+	var newVersion int64
+	if restored := restoreOk.GetPayload(); restored != nil && restored.Version != nil {
+		newVersion = *restored.Version
+	}
+
+	resource, err := h.getRemoteDashboard(uid)
+	if err != nil {
+		return nil, 0, wrapAPIError(err)
+	}
+	return resource, newVersion, nil
+}
+
 // getRemoteDashboard retrieves a dashboard object from Grafana
 func (h *DashboardHandler) getRemoteDashboard(uid string) (*grizzly.Resource, error) {
 	client, err := h.Provider.(ClientProvider).Client()
@@ -167,86 +626,141 @@ func (h *DashboardHandler) getRemoteDashboard(uid string) (*grizzly.Resource, er
 }
 
 func (h *DashboardHandler) getRemoteDashboardList() ([]string, error) {
-	var (
-		limit            = int64(1000)
-		searchType       = "dash-db"
-		page       int64 = 0
-		uids       []string
-	)
-
 	client, err := h.Provider.(ClientProvider).Client()
 	if err != nil {
 		return nil, err
 	}
 
-	params := search.NewSearchParams().WithLimit(&limit).WithType(&searchType)
-	for {
-		page++
-		params.SetPage(&page)
+	searchType := "dash-db"
+	hits, err := searchAll(client, search.NewSearchParams().WithType(&searchType))
+	if err != nil {
+		return nil, err
+	}
 
-		searchOk, err := client.Search.Search(params, nil)
-		if err != nil {
-			return nil, err
-		}
+	uids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		uids = append(uids, hit.UID)
+	}
+	return uids, nil
+}
 
-		for _, hit := range searchOk.GetPayload() {
-			uids = append(uids, hit.UID)
-		}
-		if int64(len(searchOk.GetPayload())) < *params.Limit {
-			return uids, nil
+// versionMessage returns the message attached to the version of a dashboard
+// being saved. Placeholders without a value are replaced by "unknown".
+func (h *DashboardHandler) versionMessage(resource grizzly.Resource) string {
+	if h.Message == "" {
+		return ""
+	}
+	valueOrUnknown := func(value string) string {
+		if value == "" {
+			return "unknown"
 		}
+		return value
 	}
+	return strings.NewReplacer(
+		"{file}", valueOrUnknown(resource.Source.Path),
+		"{version}", valueOrUnknown(h.MessageVersion),
+	).Replace(h.Message)
 }
 
-func (h *DashboardHandler) postDashboard(resource grizzly.Resource) error {
+func (h *DashboardHandler) postDashboard(resource grizzly.Resource, overwrite bool) error {
 	folderUID := resource.GetMetadata("folder")
 	var folderID int64
+	folderTitle := DefaultFolder
 	if !(folderUID == DefaultFolder || folderUID == strings.ToLower(DefaultFolder)) {
 		folderHandler := NewFolderHandler(h.Provider)
-		folder, err := folderHandler.getRemoteFolder(folderUID)
+		folder, err := cachedRemote(h.Provider, DashboardFolderKind, folderUID, func() (*grizzly.Resource, error) {
+			return folderHandler.resolveRemoteFolder(folderUID)
+		})
 		if err != nil {
-			if errors.Is(err, grizzly.ErrNotFound) {
-				return fmt.Errorf("cannot upload dashboard %s as folder %s not found", resource.Name(), folderUID)
-			} else {
-				return fmt.Errorf("cannot upload dashboard %s: %w", resource.Name(), err)
-			}
+			return FolderError{Folder: folderUID, Dashboard: resource.Name(), Err: err}
 		}
 		folderID = int64(folder.GetSpecValue("id").(float64))
+		folderTitle, _ = folder.GetSpecString("title")
+		if resolvedUID, _ := folder.GetSpecString("uid"); resolvedUID != "" {
+			folderTitle = fmt.Sprintf("%s (%s)", folderTitle, resolvedUID)
+		}
 	} else {
 		folderID = generalFolderID
 	}
 
+	if err := h.validateSchema(resource); err != nil {
+		return err
+	}
+
+	if h.ReadOnly {
+		resource = resource.Clone()
+		resource.SetSpecValue("editable", false)
+	}
+
 	body := models.SaveDashboardCommand{
 		Dashboard: resource.Spec(),
 		FolderID:  folderID,
-		Overwrite: true,
+		Overwrite: overwrite,
+		Message:   h.versionMessage(resource),
 	}
 	client, err := h.Provider.(ClientProvider).Client()
 	if err != nil {
 		return err
 	}
 
-	_, err = client.Dashboards.PostDashboard(&body)
-	return err
+	// the body is streamed, instead of being buffered by the client
+	dashboardOk, err := client.Dashboards.PostDashboard(nil, func(op *runtime.ClientOperation) {
+		op.Params = runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+			return r.SetBodyParam(streamJSON(body))
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	h.saved.Store(resource.Name(), savedDashboard{
+		payload: dashboardOk.GetPayload(),
+		folder:  folderTitle,
+	})
+	return nil
 }
 
-func (h *DashboardHandler) postSnapshot(resource grizzly.Resource, expiresSeconds int) (*models.CreateDashboardSnapshotOKBody, error) {
-	body := models.CreateDashboardSnapshotCommand{
-		Dashboard: resource.Spec(),
+// savedDashboard describes where a dashboard was saved
+type savedDashboard struct {
+	payload *models.PostDashboardOKBody
+	// folder is the title of the folder of the dashboard, along with its UID
+	folder string
+}
+
+// AppliedDetails returns the folder and the URL of the dashboard last saved
+// with the given UID, along with the version Grafana gave it
+func (h *DashboardHandler) AppliedDetails(resource grizzly.Resource) string {
+	value, ok := h.saved.Load(resource.Name())
+	if !ok {
+		return ""
 	}
-	if expiresSeconds > 0 {
-		body.Expires = int64(expiresSeconds)
+	saved, ok := value.(savedDashboard)
+	if !ok {
+		return ""
 	}
-	client, err := h.Provider.(ClientProvider).Client()
-	if err != nil {
-		return nil, err
+
+	details := []string{}
+	if saved.folder != "" {
+		details = append(details, "in folder "+saved.folder)
+	}
+	if payload := saved.payload; payload != nil && payload.URL != nil && *payload.URL != "" {
+		available := "available at " + h.dashboardURL(*payload.URL)
+		if payload.Version != nil {
+			available += fmt.Sprintf(" (version %d)", *payload.Version)
+		}
+		details = append(details, available)
 	}
+	return strings.Join(details, ", ")
+}
 
-	response, err := client.Snapshots.CreateDashboardSnapshot(&body, nil)
-	if err != nil {
-		return nil, err
+// dashboardURL turns the URL returned by Grafana, which is relative to its
+// host and already holds its sub-path, into an absolute one
+func (h *DashboardHandler) dashboardURL(relativeURL string) string {
+	base, err := url.Parse(h.Provider.(ClientProvider).Config().URL)
+	if err != nil || base.Host == "" {
+		return relativeURL
 	}
-	return response.GetPayload(), nil
+	return base.Scheme + "://" + base.Host + relativeURL
 }
 
 func (h *DashboardHandler) Detect(data map[string]any) bool {
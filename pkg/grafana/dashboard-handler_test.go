@@ -0,0 +1,565 @@
+package grafana
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/config"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardHandler_Canonicalize(t *testing.T) {
+	handler := NewDashboardHandler(&Provider{})
+
+	newDashboard := func(panels ...any) grizzly.Resource {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dash", map[string]any{
+			"uid":    "dash",
+			"panels": panels,
+		})
+		require.NoError(t, err)
+		return resource
+	}
+
+	local := newDashboard(
+		map[string]any{"id": 2, "title": "second"},
+		map[string]any{"title": "no id"},
+		map[string]any{"id": 1, "type": "row", "panels": []any{
+			map[string]any{"id": 4, "title": "fourth"},
+			map[string]any{"id": 3, "title": "third"},
+		}},
+	)
+	remote := newDashboard(
+		map[string]any{"id": 1.0, "type": "row", "panels": []any{
+			map[string]any{"id": 3.0, "title": "third"},
+			map[string]any{"id": 4.0, "title": "fourth"},
+		}},
+		map[string]any{"id": 2.0, "title": "second"},
+		map[string]any{"title": "no id"},
+	)
+
+	t.Run("panels are sorted by id", func(t *testing.T) {
+		req := require.New(t)
+
+		canonicalLocal := handler.Canonicalize(local)
+		canonicalRemote := handler.Canonicalize(remote)
+		localYAML, err := canonicalLocal.YAML()
+		req.NoError(err)
+		remoteYAML, err := canonicalRemote.YAML()
+		req.NoError(err)
+		req.Equal(remoteYAML, localYAML)
+
+		// the original resource is left untouched
+		panels := local.Spec()["panels"].([]any)
+		req.Equal("second", panels[0].(map[string]any)["title"])
+	})
+
+	t.Run("sorting can be disabled", func(t *testing.T) {
+		handler := NewDashboardHandler(&Provider{})
+		handler.SortPanels = false
+
+		canonical := handler.Canonicalize(local)
+		panels := canonical.Spec()["panels"].([]any)
+		require.Equal(t, "second", panels[0].(map[string]any)["title"])
+	})
+
+	t.Run("tags are sorted", func(t *testing.T) {
+		req := require.New(t)
+
+		newDashboard := func(tags ...any) grizzly.Resource {
+			resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dash", map[string]any{
+				"uid":  "dash",
+				"tags": tags,
+			})
+			req.NoError(err)
+			return resource
+		}
+		local := newDashboard("team-a", "infra")
+
+		canonicalLocal := handler.Canonicalize(local)
+		canonicalRemote := handler.Canonicalize(newDashboard("infra", "team-a"))
+		req.Equal(canonicalRemote.Spec()["tags"], canonicalLocal.Spec()["tags"])
+		req.Equal([]any{"team-a", "infra"}, local.Spec()["tags"], "the original resource is left untouched")
+	})
+
+	t.Run("template variable values can be ignored", func(t *testing.T) {
+		req := require.New(t)
+
+		newDashboard := func(current string) grizzly.Resource {
+			resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dash", map[string]any{
+				"uid": "dash",
+				"templating": map[string]any{"list": []any{
+					map[string]any{
+						"name":    "env",
+						"query":   "prod,dev",
+						"current": map[string]any{"text": current, "value": current},
+						"options": []any{
+							map[string]any{"text": "prod", "value": "prod", "selected": current == "prod"},
+							map[string]any{"text": "dev", "value": "dev", "selected": current == "dev"},
+						},
+					},
+				}},
+			})
+			req.NoError(err)
+			return resource
+		}
+		local, remote := newDashboard("prod"), newDashboard("dev")
+
+		differ := func(handler *DashboardHandler) bool {
+			canonicalLocal := handler.Canonicalize(local)
+			canonicalRemote := handler.Canonicalize(remote)
+			localYAML, err := canonicalLocal.YAML()
+			req.NoError(err)
+			remoteYAML, err := canonicalRemote.YAML()
+			req.NoError(err)
+			return localYAML != remoteYAML
+		}
+		req.True(differ(handler), "values are compared by default")
+
+		handler := NewDashboardHandler(&Provider{})
+		handler.IgnoreVariableValues = true
+		req.False(differ(handler))
+
+		canonical := handler.Canonicalize(local)
+		variable := canonical.Spec()["templating"].(map[string]any)["list"].([]any)[0].(map[string]any)
+		req.Equal("prod,dev", variable["query"])
+		req.NotContains(variable, "current")
+		req.NotContains(variable, "options")
+	})
+}
+
+func TestProviderDashboardHandlerOptions(t *testing.T) {
+	provider := NewProvider(&config.GrafanaConfig{IgnoreVariableValues: true, PreventOverwrite: true, ReadOnlyDashboards: true, KeepDashboardIDs: true, DashboardMessage: "from {file}", DashboardMessageVersion: "v1"})
+
+	for _, handler := range provider.GetHandlers() {
+		if dashboardHandler, ok := handler.(*DashboardHandler); ok {
+			require.True(t, dashboardHandler.IgnoreVariableValues)
+			require.True(t, dashboardHandler.PreventOverwrite)
+			require.True(t, dashboardHandler.ReadOnly)
+			require.True(t, dashboardHandler.KeepIDs)
+			require.Equal(t, "from {file}", dashboardHandler.Message)
+			require.Equal(t, "v1", dashboardHandler.MessageVersion)
+			return
+		}
+	}
+	t.Fatal("no dashboard handler found")
+}
+
+func TestDashboardHandler_Add(t *testing.T) {
+	req := require.New(t)
+
+	var received map[string]any
+	var acceptEncoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write([]byte(`{"status": "success", "uid": "dash"}`))
+		_ = gz.Close()
+	}))
+	t.Cleanup(server.Close)
+
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+	resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dash", map[string]any{
+		"uid":           "dash",
+		"title":         "Dashboard",
+		"schemaVersion": 39,
+	})
+	req.NoError(err)
+	resource.SetMetadata("folder", generalFolderUID)
+
+	req.NoError(handler.Add(resource))
+	req.Contains(acceptEncoding, "gzip")
+	req.Equal(true, received["overwrite"])
+	req.Equal("Dashboard", received["dashboard"].(map[string]any)["title"])
+}
+
+func TestDashboardHandler_GenerateUID(t *testing.T) {
+	handler := NewDashboardHandler(&Provider{})
+
+	t.Run("UIDs are derived from titles", func(t *testing.T) {
+		req := require.New(t)
+
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dummy", map[string]any{
+			"title": "Team A: Overview",
+		})
+		req.NoError(err)
+
+		uid, err := handler.GenerateUID(&resource)
+		req.NoError(err)
+		req.Equal("team-a-overview", uid)
+		req.Equal("team-a-overview", resource.Spec()["uid"])
+	})
+
+	t.Run("dashboards without title are rejected", func(t *testing.T) {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dummy", map[string]any{})
+		require.NoError(t, err)
+
+		_, err = handler.GenerateUID(&resource)
+		require.Error(t, err)
+	})
+
+	for _, tc := range []struct {
+		name  string
+		title string
+	}{
+		{"long titles are shortened", "A dashboard with a title far longer than what Grafana accepts as a UID"},
+		{"titles without letters nor digits are hashed", "🔥 — 🔥"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := require.New(t)
+
+			uid := uidFromTitle(tc.title)
+			req.LessOrEqual(len(uid), maxUIDLength)
+			req.Regexp("^[a-z0-9-]+$", uid)
+			req.Equal(uid, uidFromTitle(tc.title), "UIDs must be stable")
+			req.NotEqual(uid, uidFromTitle(tc.title+" (copy)"))
+		})
+	}
+}
+
+func TestDashboardHandler_ReadOnly(t *testing.T) {
+	req := require.New(t)
+
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "success", "uid": "dash"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+	handler.ReadOnly = true
+
+	resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dash", map[string]any{"uid": "dash", "title": "Dashboard", "schemaVersion": 39, "editable": true})
+	req.NoError(err)
+	resource.SetMetadata("folder", generalFolderUID)
+
+	req.NoError(handler.Add(resource))
+	req.Equal(false, received["dashboard"].(map[string]any)["editable"])
+	req.Equal(true, resource.Spec()["editable"], "the local dashboard is left untouched")
+
+	remote := resource.Clone()
+	remote.SetSpecValue("editable", false)
+	local, canonicalRemote := handler.Canonicalize(resource), handler.Canonicalize(remote)
+	req.Equal(local.Spec(), canonicalRemote.Spec(), "editable is ignored when comparing dashboards")
+}
+
+func TestDashboardHandler_Message(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status": "success", "uid": "dash"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+	resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dash", map[string]any{"uid": "dash", "title": "Dashboard", "schemaVersion": 39})
+	require.NoError(t, err)
+	resource.SetMetadata("folder", generalFolderUID)
+	resource.Source.Path = "dashboards/dash.json"
+
+	t.Run("no message is attached by default", func(t *testing.T) {
+		require.NoError(t, handler.Add(resource))
+		require.NotContains(t, received, "message")
+	})
+
+	t.Run("placeholders are replaced", func(t *testing.T) {
+		handler.Message = "applied by grizzly from {file} @ {version}"
+		handler.MessageVersion = "3f2a9c1"
+		require.NoError(t, handler.Add(resource))
+		require.Equal(t, "applied by grizzly from dashboards/dash.json @ 3f2a9c1", received["message"])
+	})
+
+	t.Run("placeholders without a value are unknown", func(t *testing.T) {
+		handler.MessageVersion = ""
+		require.NoError(t, handler.Add(resource))
+		require.Equal(t, "applied by grizzly from dashboards/dash.json @ unknown", received["message"])
+	})
+}
+
+func TestDashboardHandler_MissingFolder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/folders/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "folder not found"}`))
+		case "/api/folders/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message": "database is locked"}`))
+		case "/api/search":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+	dashboard := func(folder string) grizzly.Resource {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dash", map[string]any{"uid": "dash", "title": "Dashboard", "schemaVersion": 39})
+		require.NoError(t, err)
+		resource.SetMetadata("folder", folder)
+		return resource
+	}
+
+	t.Run("missing folders are reported with the dashboard", func(t *testing.T) {
+		err := handler.Add(dashboard("missing"))
+		require.EqualError(t, err, "could not find folder 'missing' for dashboard 'dash': no folder with this UID or title is visible: it may not exist, may have failed to be created, or may not be accessible with the credentials")
+		require.ErrorIs(t, err, grizzly.ErrNotFound)
+		var folderErr FolderError
+		require.ErrorAs(t, err, &folderErr)
+	})
+
+	t.Run("the reason the folder couldn't be found is given", func(t *testing.T) {
+		err := handler.Add(dashboard("broken"))
+		require.ErrorContains(t, err, "could not find folder 'broken' for dashboard 'dash': ")
+		require.ErrorContains(t, err, "database is locked")
+	})
+}
+
+func TestDashboardHandler_KeepIDs(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/dashboards/uid/dash":
+			_, _ = w.Write([]byte(`{"dashboard": {"id": 42, "uid": "dash", "title": "Dashboard", "version": 3}, "meta": {"folderUid": "general"}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/dashboards/db":
+			received = nil
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"status": "success", "uid": "dash"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+	newResource := func() grizzly.Resource {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dash", map[string]any{"uid": "dash", "title": "Dashboard", "schemaVersion": 39, "id": 7})
+		require.NoError(t, err)
+		resource.SetMetadata("folder", generalFolderUID)
+		return resource
+	}
+
+	t.Run("IDs are left to Grafana by default", func(t *testing.T) {
+		require.NoError(t, handler.Update(newResource(), newResource()))
+		require.NotContains(t, received["dashboard"], "id")
+	})
+
+	t.Run("IDs of remote dashboards are kept", func(t *testing.T) {
+		handler.KeepIDs = true
+		resource := newResource()
+		require.NoError(t, handler.Update(resource, resource))
+		require.Equal(t, float64(42), received["dashboard"].(map[string]any)["id"])
+	})
+}
+
+func TestDashboardHandler_Versions(t *testing.T) {
+	req := require.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/api/dashboards/uid/dash/versions" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Dashboard not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"version": 2, "created": "2024-05-02T10:00:00Z", "createdBy": "admin", "message": "applied by grizzly"},
+			{"version": 1, "created": "2024-05-01T10:00:00Z", "createdBy": "jdoe", "message": ""}
+		]`))
+	}))
+	t.Cleanup(server.Close)
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+
+	versions, err := handler.Versions("dash")
+	req.NoError(err)
+	req.Equal([]grizzly.Version{
+		{Version: 2, Created: "2024-05-02T10:00:00Z", Author: "admin", Message: "applied by grizzly"},
+		{Version: 1, Created: "2024-05-01T10:00:00Z", Author: "jdoe"},
+	}, versions)
+
+	_, err = handler.Versions("unknown")
+	req.ErrorIs(err, grizzly.ErrNotFound)
+}
+
+func TestDashboardHandler_AppliedDetails(t *testing.T) {
+	req := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/grafana/api/folders/team-a" {
+			_, _ = w.Write([]byte(`{"id": 7, "uid": "team-a", "title": "Team A"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status": "success", "uid": "dash", "url": "/grafana/d/dash/dashboard", "version": 3}`))
+	}))
+	t.Cleanup(server.Close)
+
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL + "/grafana/"}))
+	resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dash", map[string]any{"uid": "dash", "title": "Dashboard", "schemaVersion": 39})
+	req.NoError(err)
+	resource.SetMetadata("folder", generalFolderUID)
+
+	req.Empty(handler.AppliedDetails(resource), "nothing is reported before the dashboard is saved")
+
+	req.NoError(handler.Add(resource))
+	req.Equal("in folder General, available at "+server.URL+"/grafana/d/dash/dashboard (version 3)", handler.AppliedDetails(resource))
+
+	resource.SetMetadata("folder", "team-a")
+	req.NoError(handler.Add(resource))
+	req.Equal("in folder Team A (team-a), available at "+server.URL+"/grafana/d/dash/dashboard (version 3)", handler.AppliedDetails(resource))
+}
+
+func TestDashboardHandler_GetRemotes(t *testing.T) {
+	req := require.New(t)
+
+	var mu sync.Mutex
+	var searched [][]string
+	fetched := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+
+		if uid, ok := strings.CutPrefix(r.URL.Path, "/api/dashboards/uid/"); ok {
+			fetched = append(fetched, uid)
+			_, _ = fmt.Fprintf(w, `{"dashboard": {"uid": %q, "title": "Existing"}, "meta": {"folderUid": "team-a"}}`, uid)
+			return
+		}
+		req.Equal("/api/search", r.URL.Path)
+		uids := r.URL.Query()["dashboardUIDs"]
+		searched = append(searched, uids)
+
+		hits := []map[string]string{}
+		for _, uid := range uids {
+			if uid != "new" {
+				hits = append(hits, map[string]string{"uid": uid, "type": "dash-db"})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(hits)
+	}))
+	t.Cleanup(server.Close)
+
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+	dashboard := func(name, uid string) grizzly.Resource {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), name, map[string]any{"uid": uid})
+		req.NoError(err)
+		return resource
+	}
+
+	existing, missing, err := handler.GetRemotes([]grizzly.Resource{
+		dashboard("existing", "existing"),
+		dashboard("new", "new"),
+		dashboard("mismatch", "other"),
+	})
+	req.NoError(err)
+	req.Equal([]string{"new"}, missing)
+	req.Equal([][]string{{"existing", "new"}}, searched, "dashboards are looked up in a single search, leaving out UID mismatches")
+	req.Equal([]string{"existing"}, fetched, "only the dashboards found are retrieved")
+	req.Len(existing, 1)
+	req.Equal("existing", existing[0].Name())
+	req.Equal("team-a", existing[0].GetMetadata("folder"))
+}
+
+func TestDashboardHandler_PreventOverwrite(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		dashboard := received["dashboard"].(map[string]any)
+		// overwrite is omitted when false
+		if received["overwrite"] != true && dashboard["version"] != 3.0 {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			_, _ = w.Write([]byte(`{"status": "version-mismatch", "message": "The dashboard has been changed by someone else"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status": "success", "uid": "dash"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	newDashboard := func(handler *DashboardHandler, version any) grizzly.Resource {
+		spec := map[string]any{"uid": "dash", "title": "Dashboard", "schemaVersion": 39}
+		if version != nil {
+			spec["version"] = version
+		}
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dash", spec)
+		require.NoError(t, err)
+		resource.SetMetadata("folder", generalFolderUID)
+		return resource
+	}
+
+	t.Run("dashboards are overwritten by default", func(t *testing.T) {
+		req := require.New(t)
+		handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+
+		req.NoError(handler.Update(newDashboard(handler, 5.0), newDashboard(handler, 1.0)))
+		req.Equal(true, received["overwrite"])
+	})
+
+	t.Run("dashboards at the expected version are updated", func(t *testing.T) {
+		req := require.New(t)
+		handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+		handler.PreventOverwrite = true
+
+		req.NoError(handler.Update(newDashboard(handler, 3.0), newDashboard(handler, 3.0)))
+		req.NotContains(received, "overwrite")
+	})
+
+	t.Run("dashboards without version are checked against the remote one", func(t *testing.T) {
+		handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+		handler.PreventOverwrite = true
+
+		require.NoError(t, handler.Update(newDashboard(handler, 3.0), newDashboard(handler, nil)))
+	})
+
+	t.Run("dashboards changed remotely are a conflict", func(t *testing.T) {
+		req := require.New(t)
+		handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+		handler.PreventOverwrite = true
+
+		err := handler.Update(newDashboard(handler, 4.0), newDashboard(handler, 2.0))
+		req.ErrorIs(err, ErrConflict)
+		req.ErrorContains(err, "dashboard dash was changed remotely since version 2")
+	})
+
+	t.Run("remote dashboards keep their version", func(t *testing.T) {
+		handler := NewDashboardHandler(&Provider{})
+		handler.PreventOverwrite = true
+
+		remote := handler.Unprepare(newDashboard(handler, 4.0))
+		require.Equal(t, 4.0, remote.GetSpecValue("version"))
+	})
+}
@@ -0,0 +1,257 @@
+package grafana
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-openapi-client-go/client/search"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/hashicorp/go-multierror"
+)
+
+var _ grizzly.RemoteValidateHandler = &DashboardHandler{}
+var _ grizzly.ConflictHandler = &DashboardHandler{}
+
+// datasourceVariableRegex matches datasources referenced through a template
+// variable: $ds, ${ds} or [[ds]]
+var datasourceVariableRegex = regexp.MustCompile(`^(?:\$\{([^}:]+)(?::[^}]*)?\}|\$(\w+)|\[\[(\w+)\]\])$`)
+
+// ValidateRemote checks that Grafana would accept a dashboard, without saving
+// it: its folder must exist, and no other dashboard of that folder may have
+// the same title.
+func (h *DashboardHandler) ValidateRemote(resource grizzly.Resource) error {
+	folderUID := resource.GetMetadata("folder")
+	if folderUID == "" || folderUID == DefaultFolder || folderUID == strings.ToLower(DefaultFolder) {
+		folderUID = ""
+	} else {
+		folderHandler := NewFolderHandler(h.Provider)
+		folder, err := cachedRemote(h.Provider, DashboardFolderKind, folderUID, func() (*grizzly.Resource, error) {
+			return folderHandler.resolveRemoteFolder(folderUID)
+		})
+		if errors.Is(err, grizzly.ErrNotFound) {
+			return fmt.Errorf("folder %s not found", folderUID)
+		}
+		if err != nil {
+			return err
+		}
+		folderUID, _ = folder.GetSpecString("uid")
+	}
+
+	title, _ := resource.GetSpecString("title")
+	if title == "" {
+		return nil
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	searchType := "dash-db"
+	hits, err := searchAll(client, search.NewSearchParams().WithType(&searchType).WithQuery(&title))
+	if err != nil {
+		return err
+	}
+	for _, hit := range hits {
+		hitFolderUID := hit.FolderUID
+		if hitFolderUID == generalFolderUID {
+			hitFolderUID = ""
+		}
+		if hit.UID != resource.Name() && hitFolderUID == folderUID && strings.EqualFold(hit.Title, title) {
+			return fmt.Errorf("dashboard %s already has the title '%s' in this folder", hit.UID, hit.Title)
+		}
+	}
+	return nil
+}
+
+// Conflicts finds the remote dashboards a dashboard could collide with once
+// applied: the one with the same UID, which would be overwritten, and those
+// with the same title, in any folder.
+func (h *DashboardHandler) Conflicts(resource grizzly.Resource) ([]string, error) {
+	var conflicts []string
+
+	remote, err := h.getRemoteDashboard(resource.Name())
+	if err != nil && !errors.Is(err, grizzly.ErrNotFound) {
+		return nil, err
+	}
+	if remote != nil {
+		remoteTitle, _ := remote.GetSpecString("title")
+		conflicts = append(conflicts, fmt.Sprintf("dashboard %s already exists, titled '%s' in folder %s: applying would overwrite it", remote.Name(), remoteTitle, remote.GetMetadata("folder")))
+	}
+
+	title, _ := resource.GetSpecString("title")
+	if title == "" {
+		return conflicts, nil
+	}
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+	searchType := "dash-db"
+	hits, err := searchAll(client, search.NewSearchParams().WithType(&searchType).WithQuery(&title))
+	if err != nil {
+		return nil, err
+	}
+
+	folder := resource.GetMetadata("folder")
+	for _, hit := range hits {
+		if hit.UID == resource.Name() || !strings.EqualFold(hit.Title, title) {
+			continue
+		}
+		hitFolderUID := hit.FolderUID
+		if hitFolderUID == "" {
+			hitFolderUID = generalFolderUID
+		}
+		conflict := fmt.Sprintf("dashboard %s already has the title '%s' in folder %s", hit.UID, hit.Title, hitFolderUID)
+		// folders may be referenced by UID or title
+		if strings.EqualFold(folder, hitFolderUID) || (hit.FolderTitle != "" && strings.EqualFold(folder, hit.FolderTitle)) ||
+			(hitFolderUID == generalFolderUID && strings.EqualFold(folder, DefaultFolder)) {
+			conflict += ": Grafana would reject the dashboard, as titles are unique within a folder"
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	return conflicts, nil
+}
+
+// validateDashboardSchema checks the structure of a dashboard beyond what
+// Grafana enforces: dashboards accepted by Grafana may still render broken,
+// for example with panels lacking a type. Fields Grafana does without, such
+// as schemaVersion, are reported as warnings rather than errors.
+func validateDashboardSchema(uid string, spec map[string]any) ([]string, error) {
+	var warnings []string
+	var errs error
+
+	if title, _ := spec["title"].(string); title == "" {
+		errs = multierror.Append(errs, fmt.Errorf("dashboard %s: title is missing", uid))
+	}
+	// a missing UID is taken from the name of the resource
+	if rawUID, exists := spec["uid"]; exists {
+		if specUID, _ := rawUID.(string); specUID == "" {
+			errs = multierror.Append(errs, fmt.Errorf("dashboard %s: uid must be a non-empty string", uid))
+		}
+	}
+	switch spec["schemaVersion"].(type) {
+	case float64, int, int64:
+	case nil:
+		warnings = append(warnings, fmt.Sprintf("dashboard %s: schemaVersion is missing, Grafana will migrate the dashboard from its oldest schema", uid))
+	default:
+		errs = multierror.Append(errs, fmt.Errorf("dashboard %s: schemaVersion must be a number", uid))
+	}
+
+	rawPanels, exists := spec["panels"]
+	if !exists {
+		warnings = append(warnings, fmt.Sprintf("dashboard %s: panels are missing, the dashboard will be empty", uid))
+		return warnings, errs
+	}
+	panels, ok := rawPanels.([]any)
+	if !ok {
+		return warnings, multierror.Append(errs, fmt.Errorf("dashboard %s: panels must be a list", uid))
+	}
+
+	variables := dashboardVariables(spec)
+	for i, panel := range panels {
+		for _, err := range validatePanel(uid, fmt.Sprintf("panels[%d]", i), panel, variables) {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	return warnings, errs
+}
+
+func validatePanel(uid string, path string, rawPanel any, variables map[string]bool) []error {
+	panel, ok := rawPanel.(map[string]any)
+	if !ok {
+		return []error{fmt.Errorf("dashboard %s: %s is not an object", uid, path)}
+	}
+
+	var errs []error
+	if panelType, _ := panel["type"].(string); panelType == "" {
+		errs = append(errs, fmt.Errorf("dashboard %s: %s is missing a type", uid, path))
+	}
+	if name, ok := undefinedDatasourceVariable(panel["datasource"], variables); !ok {
+		errs = append(errs, fmt.Errorf("dashboard %s: %s references undefined datasource variable '%s'", uid, path, name))
+	}
+	if targets, ok := panel["targets"].([]any); ok {
+		for j, rawTarget := range targets {
+			target, ok := rawTarget.(map[string]any)
+			if !ok {
+				continue
+			}
+			if name, ok := undefinedDatasourceVariable(target["datasource"], variables); !ok {
+				errs = append(errs, fmt.Errorf("dashboard %s: %s.targets[%d] references undefined datasource variable '%s'", uid, path, j, name))
+			}
+		}
+	}
+
+	if rawGridPos, exists := panel["gridPos"]; exists {
+		if _, ok := rawGridPos.(map[string]any); !ok {
+			errs = append(errs, fmt.Errorf("dashboard %s: %s.gridPos must be an object", uid, path))
+		}
+	}
+
+	// collapsed rows hold their own panels
+	if rawRowPanels, exists := panel["panels"]; exists {
+		rowPanels, ok := rawRowPanels.([]any)
+		if !ok {
+			return append(errs, fmt.Errorf("dashboard %s: %s.panels must be a list", uid, path))
+		}
+		for j, rowPanel := range rowPanels {
+			errs = append(errs, validatePanel(uid, fmt.Sprintf("%s.panels[%d]", path, j), rowPanel, variables)...)
+		}
+	}
+
+	return errs
+}
+
+// dashboardVariables returns the names of the template variables defined in
+// a dashboard
+func dashboardVariables(spec map[string]any) map[string]bool {
+	variables := map[string]bool{}
+
+	templating, _ := spec["templating"].(map[string]any)
+	list, _ := templating["list"].([]any)
+	for _, rawVariable := range list {
+		variable, ok := rawVariable.(map[string]any)
+		if !ok {
+			continue
+		}
+		if name, ok := variable["name"].(string); ok {
+			variables[name] = true
+		}
+	}
+
+	return variables
+}
+
+// undefinedDatasourceVariable checks whether a datasource reference uses a
+// template variable that is not defined. It returns the name of the
+// variable, and false if it is undefined.
+func undefinedDatasourceVariable(datasource any, variables map[string]bool) (string, bool) {
+	var ref string
+	switch ds := datasource.(type) {
+	case string:
+		ref = ds
+	case map[string]any:
+		ref, _ = ds["uid"].(string)
+	}
+
+	matches := datasourceVariableRegex.FindStringSubmatch(ref)
+	if matches == nil {
+		return "", true
+	}
+
+	for _, name := range matches[1:] {
+		if name == "" {
+			continue
+		}
+		// built-in variables, such as ${__from}, are always defined
+		if variables[name] || name[0] == '_' {
+			return name, true
+		}
+		return name, false
+	}
+
+	return "", true
+}
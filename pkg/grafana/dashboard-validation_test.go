@@ -0,0 +1,198 @@
+package grafana
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/config"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDashboardSchema(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             map[string]any
+		expectedErrors   []string
+		expectedWarnings []string
+	}{
+		{
+			name: "valid dashboard",
+			spec: map[string]any{
+				"title":         "Valid",
+				"schemaVersion": 38,
+				"templating": map[string]any{
+					"list": []any{map[string]any{"name": "ds", "type": "datasource"}},
+				},
+				"panels": []any{
+					map[string]any{"type": "timeseries", "datasource": map[string]any{"uid": "${ds}"}},
+					map[string]any{"type": "text", "datasource": "-- Grafana --"},
+					map[string]any{"type": "row", "panels": []any{
+						map[string]any{"type": "stat", "targets": []any{map[string]any{"datasource": map[string]any{"uid": "$ds"}}}},
+					}},
+				},
+			},
+		},
+		{
+			name: "fields Grafana does without are warnings",
+			spec: map[string]any{"title": "Lenient"},
+			expectedWarnings: []string{
+				"dashboard some-uid: schemaVersion is missing, Grafana will migrate the dashboard from its oldest schema",
+				"dashboard some-uid: panels are missing, the dashboard will be empty",
+			},
+		},
+		{
+			name: "invalid top-level fields",
+			spec: map[string]any{"uid": "", "schemaVersion": "38", "panels": "nope"},
+			expectedErrors: []string{
+				"dashboard some-uid: title is missing",
+				"dashboard some-uid: uid must be a non-empty string",
+				"dashboard some-uid: schemaVersion must be a number",
+				"dashboard some-uid: panels must be a list",
+			},
+		},
+		{
+			name: "broken panels",
+			spec: map[string]any{
+				"title":         "Broken",
+				"schemaVersion": 38,
+				"panels": []any{
+					map[string]any{"type": "timeseries"},
+					map[string]any{"datasource": map[string]any{"uid": "${missing}"}},
+					map[string]any{"type": "row", "panels": []any{
+						map[string]any{"type": "stat", "targets": []any{map[string]any{"datasource": "[[other]]"}}},
+					}},
+					map[string]any{"type": "row", "gridPos": "top", "panels": map[string]any{}},
+				},
+			},
+			expectedErrors: []string{
+				"dashboard some-uid: panels[1] is missing a type",
+				"dashboard some-uid: panels[3].gridPos must be an object",
+				"dashboard some-uid: panels[3].panels must be a list",
+				"dashboard some-uid: panels[1] references undefined datasource variable 'missing'",
+				"dashboard some-uid: panels[2].panels[0].targets[0] references undefined datasource variable 'other'",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			warnings, err := validateDashboardSchema("some-uid", test.spec)
+			req.Equal(test.expectedWarnings, warnings)
+			if len(test.expectedErrors) == 0 {
+				req.NoError(err)
+				return
+			}
+
+			req.Error(err)
+			for _, expected := range test.expectedErrors {
+				req.ErrorContains(err, expected)
+			}
+		})
+	}
+}
+
+func TestDashboardHandler_ValidateRemote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/folders/team-a":
+			_, _ = w.Write([]byte(`{"id": 3, "uid": "team-a", "title": "Team A"}`))
+		case r.URL.Path == "/api/search" && r.URL.Query().Get("type") == "dash-db":
+			_, _ = w.Write([]byte(`[
+				{"uid": "taken", "title": "Overview", "folderUid": "team-a"},
+				{"uid": "elsewhere", "title": "Latency", "folderUid": "team-b"}
+			]`))
+		case r.URL.Path == "/api/search":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+	newDashboard := func(uid, folder, title string) grizzly.Resource {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), uid, map[string]any{
+			"uid":   uid,
+			"title": title,
+		})
+		require.NoError(t, err)
+		resource.SetMetadata("folder", folder)
+		return resource
+	}
+
+	t.Run("dashboard is valid", func(t *testing.T) {
+		require.NoError(t, handler.ValidateRemote(newDashboard("dash", "team-a", "Latency")))
+	})
+
+	t.Run("a dashboard can keep its own title", func(t *testing.T) {
+		require.NoError(t, handler.ValidateRemote(newDashboard("taken", "team-a", "Overview")))
+	})
+
+	t.Run("titles are unique within a folder", func(t *testing.T) {
+		err := handler.ValidateRemote(newDashboard("dash", "team-a", "overview"))
+		require.ErrorContains(t, err, "dashboard taken already has the title 'Overview' in this folder")
+	})
+
+	t.Run("folder must exist", func(t *testing.T) {
+		err := handler.ValidateRemote(newDashboard("dash", "missing", "Latency"))
+		require.ErrorContains(t, err, "folder missing not found")
+	})
+}
+
+func TestDashboardHandler_Conflicts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/dashboards/uid/taken":
+			_, _ = w.Write([]byte(`{"dashboard": {"uid": "taken", "title": "Overview"}, "meta": {"folderUid": "team-b"}}`))
+		case r.URL.Path == "/api/search":
+			_, _ = w.Write([]byte(`[
+				{"uid": "taken", "title": "Overview", "folderUid": "team-b", "folderTitle": "Team B"},
+				{"uid": "other", "title": "Overview", "folderUid": "team-a", "folderTitle": "Team A"},
+				{"uid": "similar", "title": "Overview (old)", "folderUid": "team-a", "folderTitle": "Team A"}
+			]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+	newDashboard := func(uid, folder, title string) grizzly.Resource {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), uid, map[string]any{
+			"uid":   uid,
+			"title": title,
+		})
+		require.NoError(t, err)
+		resource.SetMetadata("folder", folder)
+		return resource
+	}
+
+	t.Run("dashboards without conflicts", func(t *testing.T) {
+		conflicts, err := handler.Conflicts(newDashboard("dash", "team-a", "Latency"))
+		require.NoError(t, err)
+		require.Empty(t, conflicts)
+	})
+
+	t.Run("existing UIDs and titles are reported", func(t *testing.T) {
+		conflicts, err := handler.Conflicts(newDashboard("taken", "team-c", "overview"))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"dashboard taken already exists, titled 'Overview' in folder team-b: applying would overwrite it",
+			"dashboard other already has the title 'Overview' in folder team-a",
+		}, conflicts)
+	})
+
+	t.Run("titles within the same folder are rejected by Grafana", func(t *testing.T) {
+		conflicts, err := handler.Conflicts(newDashboard("dash", "Team A", "Overview"))
+		require.NoError(t, err)
+		require.Contains(t, conflicts, "dashboard other already has the title 'Overview' in folder team-a: Grafana would reject the dashboard, as titles are unique within a folder")
+	})
+}
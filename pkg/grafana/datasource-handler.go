@@ -19,6 +19,7 @@ const DatasourceKind = "Datasource"
 
 var _ grizzly.Handler = &DatasourceHandler{}
 var _ grizzly.ProxyConfiguratorProvider = &DatasourceHandler{}
+var _ grizzly.HealthCheckHandler = &DatasourceHandler{}
 
 // DatasourceHandler is a Grizzly Handler for Grafana datasources
 type DatasourceHandler struct {
@@ -69,7 +70,8 @@ func (h *DatasourceHandler) Prepare(existing *grizzly.Resource, resource grizzly
 	return &resource
 }
 
-// Validate returns the uid of resource
+// Validate checks the uid of resource, and the settings of the datasource
+// against its type
 func (h *DatasourceHandler) Validate(resource grizzly.Resource) error {
 	uid, exist := resource.GetSpecString("uid")
 	if exist {
@@ -77,7 +79,7 @@ func (h *DatasourceHandler) Validate(resource grizzly.Resource) error {
 			return ErrUIDNameMismatch{UID: uid, Name: resource.Name()}
 		}
 	}
-	return nil
+	return validateDatasourceSchema(resource.Name(), resource.Spec())
 }
 
 func (h *DatasourceHandler) GetSpecUID(resource grizzly.Resource) (string, error) {
@@ -91,12 +93,12 @@ func (h *DatasourceHandler) GetSpecUID(resource grizzly.Resource) (string, error
 
 // GetByUID retrieves JSON for a resource from an endpoint, by UID
 func (h *DatasourceHandler) GetByUID(uid string) (*grizzly.Resource, error) {
-	return h.getRemoteDatasource(uid)
+	return wrapRemoteAPIError(h.getRemoteDatasource(uid))
 }
 
 // GetRemote retrieves a datasource as a Resource
 func (h *DatasourceHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
-	return h.getRemoteDatasource(resource.Name())
+	return wrapRemoteAPIError(h.getRemoteDatasource(resource.Name()))
 }
 
 // ListRemote retrieves as list of UIDs of all remote resources
@@ -106,12 +108,45 @@ func (h *DatasourceHandler) ListRemote() ([]string, error) {
 
 // Add pushes a datasource to Grafana via the API
 func (h *DatasourceHandler) Add(resource grizzly.Resource) error {
-	return h.postDatasource(resource)
+	return wrapAPIError(h.postDatasource(resource))
 }
 
 // Update pushes a datasource to Grafana via the API
 func (h *DatasourceHandler) Update(existing, resource grizzly.Resource) error {
-	return h.putDatasource(resource)
+	return wrapAPIError(h.putDatasource(resource))
+}
+
+// CheckHealth asks Grafana to check whether a datasource can connect to its
+// backend, as the "Save & test" button of the UI does
+func (h *DatasourceHandler) CheckHealth(resource grizzly.Resource) (bool, string, error) {
+	uid, ok := resource.GetSpecString("uid")
+	if !ok {
+		uid = resource.Name()
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return false, "", err
+	}
+
+	healthOk, err := client.Datasources.CheckDatasourceHealthWithUID(uid)
+	if err != nil {
+		// failing checks are reported as bad requests
+		var badRequest *datasources.CheckDatasourceHealthWithUIDBadRequest
+		if errors.As(err, &badRequest) {
+			if payload := badRequest.GetPayload(); payload != nil && payload.Message != nil {
+				return false, *payload.Message, nil
+			}
+			return false, "health check failed", nil
+		}
+		// OpenAPI definition does not define 404 for CheckDatasourceHealthWithUID, so falls though to runtime.APIError.
+		var gErr *runtime.APIError
+		if errors.As(err, &gErr) && gErr.IsCode(http.StatusNotFound) {
+			return false, "", grizzly.ErrNotFound
+		}
+		return false, "", wrapAPIError(err)
+	}
+	return true, healthOk.GetPayload().Message, nil
 }
 
 // getRemoteDatasource retrieves a datasource object from Grafana
@@ -176,6 +211,10 @@ func (h *DatasourceHandler) getRemoteDatasourceList() ([]string, error) {
 }
 
 func (h *DatasourceHandler) postDatasource(resource grizzly.Resource) error {
+	if err := validateDatasourceSchema(resource.Name(), resource.Spec()); err != nil {
+		return err
+	}
+
 	// TODO: Turn spec into a real models.DataSource object
 	data, err := json.Marshal(resource.Spec())
 	if err != nil {
@@ -198,6 +237,10 @@ func (h *DatasourceHandler) postDatasource(resource grizzly.Resource) error {
 }
 
 func (h *DatasourceHandler) putDatasource(resource grizzly.Resource) error {
+	if err := validateDatasourceSchema(resource.Name(), resource.Spec()); err != nil {
+		return err
+	}
+
 	// TODO: Turn spec into a real models.DataSource object
 	data, err := json.Marshal(resource.Spec())
 	if err != nil {
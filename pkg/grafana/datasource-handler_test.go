@@ -1,8 +1,11 @@
 package grafana
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/grafana/grizzly/pkg/config"
 	"github.com/grafana/grizzly/pkg/grizzly"
 	"github.com/stretchr/testify/require"
 )
@@ -19,3 +22,55 @@ func TestDatasourceHandler_ResourceFilePath(t *testing.T) {
 		req.Equal("datasources/datasource-some-datasource.yaml", handler.ResourceFilePath(resource, "yaml"))
 	})
 }
+
+func TestDatasourceHandler_CheckHealth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/datasources/uid/working/health":
+			_, _ = w.Write([]byte(`{"status": "OK", "message": "Data source is working"}`))
+		case "/api/datasources/uid/broken/health":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status": "ERROR", "message": "Post \"http://prometheus:9090\": connection refused"}`))
+		case "/api/datasources/uid/failing/health":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message": "Plugin health check failed"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Data source not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	handler := NewDatasourceHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+
+	check := func(uid string) (bool, string, error) {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), uid, map[string]any{"uid": uid})
+		require.NoError(t, err)
+		return handler.CheckHealth(resource)
+	}
+
+	t.Run("working datasources are healthy", func(t *testing.T) {
+		req := require.New(t)
+		healthy, message, err := check("working")
+		req.NoError(err)
+		req.True(healthy)
+		req.Equal("Data source is working", message)
+	})
+
+	t.Run("failing checks aren't errors", func(t *testing.T) {
+		req := require.New(t)
+		healthy, message, err := check("broken")
+		req.NoError(err)
+		req.False(healthy)
+		req.Equal(`Post "http://prometheus:9090": connection refused`, message)
+	})
+
+	t.Run("checks that couldn't run are errors", func(t *testing.T) {
+		req := require.New(t)
+		_, _, err := check("failing")
+		req.Error(err)
+
+		_, _, err = check("missing")
+		req.ErrorIs(err, grizzly.ErrNotFound)
+	})
+}
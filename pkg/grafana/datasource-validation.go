@@ -0,0 +1,134 @@
+package grafana
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// datasourceSchema describes the settings expected from a type of datasource
+type datasourceSchema struct {
+	// required lists the jsonData fields the datasource can't work without
+	required []string
+	// specific lists the settings only used by this type of datasource,
+	// as <jsonData|secureJsonData>.<field>. They are mistakes on datasources
+	// of other types, for example copied from another datasource.
+	specific []string
+}
+
+// datasourceSchemas describes the datasources built into Grafana, by type
+var datasourceSchemas = map[string]datasourceSchema{
+	"alertmanager": {
+		specific: []string{"jsonData.implementation", "jsonData.handleGrafanaManagedAlerts"},
+	},
+	"cloudwatch": {
+		required: []string{"defaultRegion"},
+		specific: []string{"jsonData.customMetricsNamespaces", "jsonData.logsTimeout", "secureJsonData.accessKey", "secureJsonData.secretKey"},
+	},
+	"elasticsearch": {
+		required: []string{"timeField"},
+		specific: []string{"jsonData.logMessageField", "jsonData.logLevelField", "jsonData.maxConcurrentShardRequests"},
+	},
+	"grafana-azure-monitor-datasource": {
+		specific: []string{"jsonData.cloudName", "jsonData.subscriptionId"},
+	},
+	"grafana-postgresql-datasource": {
+		specific: []string{"jsonData.postgresVersion", "jsonData.timescaledb"},
+	},
+	"grafana-pyroscope-datasource": {},
+	"grafana-testdata-datasource":  {},
+	"graphite": {
+		specific: []string{"jsonData.graphiteVersion", "jsonData.graphiteType"},
+	},
+	"influxdb": {
+		specific: []string{"jsonData.defaultBucket"},
+	},
+	"jaeger": {},
+	"loki": {
+		specific: []string{"jsonData.derivedFields", "jsonData.maxLines"},
+	},
+	"mssql": {},
+	"mysql": {},
+	"opentsdb": {
+		specific: []string{"jsonData.tsdbVersion", "jsonData.tsdbResolution"},
+	},
+	"postgres": {
+		specific: []string{"jsonData.postgresVersion", "jsonData.timescaledb"},
+	},
+	"prometheus": {
+		specific: []string{"jsonData.prometheusType", "jsonData.prometheusVersion", "jsonData.exemplarTraceIdDestinations", "jsonData.incrementalQuerying"},
+	},
+	"stackdriver": {
+		specific: []string{"jsonData.authenticationType", "jsonData.defaultProject"},
+	},
+	"tempo": {
+		specific: []string{"jsonData.serviceMap", "jsonData.lokiSearch", "jsonData.traceQuery"},
+	},
+	"testdata": {},
+	"zipkin":   {},
+}
+
+// pluginDatasourceSuffix ends the IDs of datasource plugins
+// (ex: grafana-clickhouse-datasource), which aren't validated further
+const pluginDatasourceSuffix = "-datasource"
+
+// validateDatasourceSchema checks the settings of a datasource against its
+// type, before Grafana rejects them with an API error, or accepts a
+// datasource which can't work
+func validateDatasourceSchema(uid string, spec map[string]any) error {
+	dsType, _ := spec["type"].(string)
+	if dsType == "" {
+		return fmt.Errorf("datasource %s: type is missing", uid)
+	}
+
+	schema, known := datasourceSchemas[dsType]
+	if !known {
+		if strings.HasSuffix(dsType, pluginDatasourceSuffix) {
+			return nil
+		}
+		return fmt.Errorf("datasource %s: unknown type '%s', expected one of: %s, or the ID of a datasource plugin (ex: grafana-clickhouse-datasource)", uid, dsType, strings.Join(datasourceTypes(), ", "))
+	}
+
+	var errs error
+	jsonData, _ := spec["jsonData"].(map[string]any)
+	for _, field := range schema.required {
+		if value, ok := jsonData[field]; !ok || value == "" {
+			errs = multierror.Append(errs, fmt.Errorf("datasource %s: jsonData.%s is required by %s datasources", uid, field, dsType))
+		}
+	}
+
+	secureJSONData, _ := spec["secureJsonData"].(map[string]any)
+	settings := map[string]map[string]any{
+		"jsonData":       jsonData,
+		"secureJsonData": secureJSONData,
+	}
+	reported := map[string]bool{}
+	for _, otherType := range datasourceTypes() {
+		for _, setting := range datasourceSchemas[otherType].specific {
+			// settings may be shared by types (ex: postgres and its plugin ID)
+			if slices.Contains(schema.specific, setting) || reported[setting] {
+				continue
+			}
+			group, field, _ := strings.Cut(setting, ".")
+			if _, ok := settings[group][field]; ok {
+				reported[setting] = true
+				errs = multierror.Append(errs, fmt.Errorf("datasource %s: %s is a setting of %s datasources, not %s", uid, setting, otherType, dsType))
+			}
+		}
+	}
+
+	return errs
+}
+
+// datasourceTypes returns the sorted types of the datasources built into Grafana
+func datasourceTypes() []string {
+	types := make([]string, 0, len(datasourceSchemas))
+	for dsType := range datasourceSchemas {
+		types = append(types, dsType)
+	}
+	sort.Strings(types)
+	return types
+}
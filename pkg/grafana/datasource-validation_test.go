@@ -0,0 +1,85 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDatasourceSchema(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           map[string]any
+		expectedErrors []string
+	}{
+		{
+			name: "valid datasource",
+			spec: map[string]any{
+				"type":     "prometheus",
+				"jsonData": map[string]any{"prometheusType": "Mimir", "httpMethod": "POST"},
+			},
+		},
+		{
+			name: "datasource plugins aren't validated",
+			spec: map[string]any{
+				"type":     "grafana-clickhouse-datasource",
+				"jsonData": map[string]any{"derivedFields": []any{}},
+			},
+		},
+		{
+			name:           "missing type",
+			spec:           map[string]any{},
+			expectedErrors: []string{"datasource some-uid: type is missing"},
+		},
+		{
+			name:           "unknown type",
+			spec:           map[string]any{"type": "prometeus"},
+			expectedErrors: []string{"datasource some-uid: unknown type 'prometeus', expected one of: alertmanager, cloudwatch,"},
+		},
+		{
+			name: "missing required settings",
+			spec: map[string]any{
+				"type":     "elasticsearch",
+				"jsonData": map[string]any{"timeField": ""},
+			},
+			expectedErrors: []string{"datasource some-uid: jsonData.timeField is required by elasticsearch datasources"},
+		},
+		{
+			name: "settings of another type",
+			spec: map[string]any{
+				"type":           "prometheus",
+				"jsonData":       map[string]any{"derivedFields": []any{}, "timescaledb": true},
+				"secureJsonData": map[string]any{"secretKey": "secret"},
+			},
+			expectedErrors: []string{
+				"datasource some-uid: jsonData.derivedFields is a setting of loki datasources, not prometheus",
+				"datasource some-uid: jsonData.timescaledb is a setting of grafana-postgresql-datasource datasources, not prometheus",
+				"datasource some-uid: secureJsonData.secretKey is a setting of cloudwatch datasources, not prometheus",
+			},
+		},
+		{
+			name: "settings shared by types",
+			spec: map[string]any{
+				"type":     "postgres",
+				"jsonData": map[string]any{"timescaledb": true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			err := validateDatasourceSchema("some-uid", test.spec)
+			if len(test.expectedErrors) == 0 {
+				req.NoError(err)
+				return
+			}
+
+			req.Error(err)
+			for _, expected := range test.expectedErrors {
+				req.ErrorContains(err, expected)
+			}
+		})
+	}
+}
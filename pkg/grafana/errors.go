@@ -1,8 +1,28 @@
 package grafana
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+
+	"github.com/go-openapi/runtime"
+	"github.com/grafana/grizzly/pkg/grizzly"
+)
+
+var (
+	// ErrUnauthorized signals a request rejected by Grafana as unauthenticated
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden signals a request Grafana doesn't allow with the current credentials
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrConflict signals a request conflicting with the state of Grafana,
+	// such as a resource with the same name or an outdated version
+	ErrConflict = errors.New("conflict")
+
+	// ErrServerError signals a request that failed on the Grafana side
+	ErrServerError = errors.New("server error")
 )
 
 type ErrUIDNameMismatch struct {
@@ -21,8 +41,104 @@ func (e ErrUidsMissing) Error() string {
 	return fmt.Sprintf("One or more dashboards have no UID set. UIDs are required for Grizzly to operate properly:\n - %s", strings.Join(e, "\n - "))
 }
 
+// FolderError signals a dashboard that couldn't be saved, as its folder
+// couldn't be found (ex: its creation failed, or isn't allowed) or resolved
+type FolderError struct {
+	Folder    string
+	Dashboard string
+	Err       error
+}
+
+func (e FolderError) Error() string {
+	reason := e.Err.Error()
+	if errors.Is(e.Err, grizzly.ErrNotFound) {
+		// Grafana answers as if folders didn't exist when access is denied
+		reason = "no folder with this UID or title is visible: it may not exist, may have failed to be created, or may not be accessible with the credentials"
+	}
+	return fmt.Sprintf("could not find folder '%s' for dashboard '%s': %s", e.Folder, e.Dashboard, reason)
+}
+
+func (e FolderError) Unwrap() error {
+	return e.Err
+}
+
 type APIResponse interface {
 	Code() int
 	Error() string
 	String() string
 }
+
+// APIError describes a failed call to the Grafana API. It can be compared
+// with errors.Is to ErrUnauthorized, ErrForbidden, ErrConflict,
+// ErrServerError or grizzly.ErrNotFound, depending on its status code, and
+// unwrapped into the error returned by the Grafana client. Forbidden
+// responses reporting a read-only instance or resource also match
+// grizzly.ErrWriteRejected.
+type APIError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e APIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e APIError) Unwrap() error {
+	return e.Err
+}
+
+func (e APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case grizzly.ErrWriteRejected:
+		// read-only instances (ex: replicas) and resources reject writes as
+		// forbidden, like credentials not allowed to make changes: only the
+		// message of the response tells them apart
+		return e.StatusCode == http.StatusForbidden && reportsReadOnly(e.Err)
+	case grizzly.ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict || e.StatusCode == http.StatusPreconditionFailed
+	case ErrServerError:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// reportsReadOnly tells whether an error returned by the Grafana client
+// reports a read-only instance or resource, from the message of the response
+func reportsReadOnly(err error) bool {
+	message := strings.ToLower(err.Error())
+	return strings.Contains(message, "read-only") || strings.Contains(message, "read only") || strings.Contains(message, "readonly")
+}
+
+// wrapAPIError turns an error returned by the Grafana client into an
+// APIError, so that callers can tell failures apart. Other errors are
+// returned as-is.
+func wrapAPIError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr APIError
+	if errors.As(err, &apiErr) {
+		return err
+	}
+	var response APIResponse
+	if errors.As(err, &response) {
+		return APIError{StatusCode: response.Code(), Err: err}
+	}
+	var runtimeErr *runtime.APIError
+	if errors.As(err, &runtimeErr) {
+		return APIError{StatusCode: runtimeErr.Code, Err: err}
+	}
+	return err
+}
+
+// wrapRemoteAPIError is wrapAPIError for functions retrieving a resource
+func wrapRemoteAPIError(resource *grizzly.Resource, err error) (*grizzly.Resource, error) {
+	return resource, wrapAPIError(err)
+}
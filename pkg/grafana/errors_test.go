@@ -0,0 +1,69 @@
+package grafana
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/grafana/grafana-openapi-client-go/client/dashboards"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrapAPIError(t *testing.T) {
+	t.Run("typed client errors", func(t *testing.T) {
+		err := wrapAPIError(dashboards.NewGetDashboardByUIDUnauthorized())
+		require.ErrorIs(t, err, ErrUnauthorized)
+		require.NotErrorIs(t, err, ErrForbidden)
+
+		var clientErr *dashboards.GetDashboardByUIDUnauthorized
+		require.ErrorAs(t, err, &clientErr, "the client error is expected to be preserved")
+
+		require.ErrorIs(t, wrapAPIError(dashboards.NewGetDashboardByUIDForbidden()), ErrForbidden)
+		require.ErrorIs(t, wrapAPIError(dashboards.NewGetDashboardByUIDNotFound()), grizzly.ErrNotFound)
+		require.ErrorIs(t, wrapAPIError(dashboards.NewPostDashboardPreconditionFailed()), ErrConflict)
+		require.ErrorIs(t, wrapAPIError(dashboards.NewGetDashboardByUIDInternalServerError()), ErrServerError)
+	})
+
+	t.Run("forbidden writes", func(t *testing.T) {
+		forbidden := func(message string) error {
+			response := dashboards.NewPostDashboardForbidden()
+			response.Payload = &models.ErrorResponseBody{Message: &message}
+			return wrapAPIError(response)
+		}
+
+		err := forbidden("Permissions needed: dashboards:write")
+		require.ErrorIs(t, err, ErrForbidden)
+		require.NotErrorIs(t, err, grizzly.ErrWriteRejected, "missing permissions aren't reported as a read-only instance")
+
+		err = forbidden("Grafana is in read-only mode")
+		require.ErrorIs(t, err, ErrForbidden)
+		require.ErrorIs(t, err, grizzly.ErrWriteRejected)
+	})
+
+	t.Run("undocumented status codes", func(t *testing.T) {
+		err := wrapAPIError(runtime.NewAPIError("createFolder", nil, 409))
+		require.ErrorIs(t, err, ErrConflict)
+
+		var apiErr APIError
+		require.ErrorAs(t, err, &apiErr)
+		require.Equal(t, 409, apiErr.StatusCode)
+
+		require.ErrorIs(t, wrapAPIError(runtime.NewAPIError("getFolder", nil, 503)), ErrServerError)
+	})
+
+	t.Run("wrapped errors", func(t *testing.T) {
+		err := wrapAPIError(fmt.Errorf("posting dashboard: %w", dashboards.NewGetDashboardByUIDUnauthorized()))
+		require.ErrorIs(t, err, ErrUnauthorized)
+		require.ErrorContains(t, err, "posting dashboard")
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		original := errors.New("boom")
+		require.Equal(t, original, wrapAPIError(original))
+		require.Nil(t, wrapAPIError(nil))
+		require.Equal(t, grizzly.ErrNotFound, wrapAPIError(grizzly.ErrNotFound))
+	})
+}
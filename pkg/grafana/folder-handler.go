@@ -18,6 +18,7 @@ const DashboardFolderKind = "DashboardFolder"
 
 var _ grizzly.Handler = &FolderHandler{}
 var _ grizzly.ProxyConfiguratorProvider = &FolderHandler{}
+var _ grizzly.FolderResolverHandler = &FolderHandler{}
 
 // FolderHandler is a Grizzly Handler for Grafana dashboard folders
 type FolderHandler struct {
@@ -128,7 +129,7 @@ func (h *FolderHandler) Sort(resources grizzly.Resources) grizzly.Resources {
 func (h *FolderHandler) GetByUID(uid string) (*grizzly.Resource, error) {
 	resource, err := h.getRemoteFolder(uid)
 	if err != nil {
-		return nil, fmt.Errorf("Error retrieving dashboard folder %s: %w", uid, err)
+		return nil, fmt.Errorf("Error retrieving dashboard folder %s: %w", uid, wrapAPIError(err))
 	}
 
 	return resource, nil
@@ -136,7 +137,7 @@ func (h *FolderHandler) GetByUID(uid string) (*grizzly.Resource, error) {
 
 // GetRemote retrieves a folder as a resource
 func (h *FolderHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
-	return h.getRemoteFolder(resource.Name())
+	return wrapRemoteAPIError(h.getRemoteFolder(resource.Name()))
 }
 
 // ListRemote retrieves as list of UIDs of all remote resources
@@ -146,12 +147,58 @@ func (h *FolderHandler) ListRemote() ([]string, error) {
 
 // Add pushes a new folder to Grafana via the API
 func (h *FolderHandler) Add(resource grizzly.Resource) error {
-	return h.postFolder(resource)
+	return wrapAPIError(h.postFolder(resource))
 }
 
 // Update pushes a folder to Grafana via the API
 func (h *FolderHandler) Update(existing, resource grizzly.Resource) error {
-	return h.putFolder(resource)
+	return wrapAPIError(h.putFolder(resource))
+}
+
+// resolveRemoteFolder retrieves the folder a dashboard is stored in. Folders
+// are referenced by UID or, if no folder has this UID, by title. As titles
+// are only unique within a parent folder, a title shared by several folders
+// is rejected.
+func (h *FolderHandler) resolveRemoteFolder(ref string) (*grizzly.Resource, error) {
+	resource, err := h.getRemoteFolder(ref)
+	if !errors.Is(err, grizzly.ErrNotFound) {
+		return resource, err
+	}
+
+	client, clientErr := h.Provider.(ClientProvider).Client()
+	if clientErr != nil {
+		return nil, clientErr
+	}
+
+	hits, searchErr := searchFoldersByTitle(client, ref)
+	if searchErr != nil {
+		return nil, searchErr
+	}
+	switch len(hits) {
+	case 0:
+		return nil, err
+	case 1:
+		return h.getRemoteFolder(hits[0].UID)
+	default:
+		uids := make([]string, len(hits))
+		for i, hit := range hits {
+			uids[i] = hit.UID
+		}
+		return nil, fmt.Errorf("folder '%s' is ambiguous, several folders have this title: %s", ref, strings.Join(uids, ", "))
+	}
+}
+
+// ResolveFolderUID returns the UID of a folder referenced by UID or title.
+// The General folder is given the UID dashboards stored in it refer to.
+func (h *FolderHandler) ResolveFolderUID(ref string) (string, error) {
+	if strings.EqualFold(ref, DefaultFolder) {
+		return generalFolderUID, nil
+	}
+	folder, err := h.resolveRemoteFolder(ref)
+	if err != nil {
+		return "", err
+	}
+	return folder.Name(), nil
 }
 
 // getRemoteFolder retrieves a folder object from Grafana
@@ -173,16 +220,10 @@ func (h *FolderHandler) getRemoteFolder(uid string) (*grizzly.Resource, error) {
 			return nil, err
 		}
 
-		folderOk, err := client.Folders.GetFolderByUID(uid)
+		folder, err = getFolderByUID(client, uid)
 		if err != nil {
-			var gErrNotFound *folders.GetFolderByUIDNotFound
-			var gErrForbidden *folders.GetFolderByUIDForbidden
-			if errors.As(err, &gErrNotFound) || errors.As(err, &gErrForbidden) {
-				return nil, fmt.Errorf("couldn't fetch folder '%s' from remote: %w", uid, grizzly.ErrNotFound)
-			}
 			return nil, err
 		}
-		folder = folderOk.GetPayload()
 	}
 
 	// TODO: Turn spec into a real models.Folder object
@@ -199,36 +240,22 @@ func (h *FolderHandler) getRemoteFolder(uid string) (*grizzly.Resource, error) {
 }
 
 func (h *FolderHandler) getRemoteFolderList() ([]string, error) {
-	var (
-		limit            = int64(1000)
-		page       int64 = 0
-		uids       []string
-		folderType = "dash-folder"
-	)
-
-	params := search.NewSearchParams().WithLimit(&limit)
-	params.Type = &folderType
 	client, err := h.Provider.(ClientProvider).Client()
 	if err != nil {
 		return nil, err
 	}
 
-	for {
-		page++
-		params.SetPage(&page)
-
-		searchOk, err := client.Search.Search(params, nil)
-		if err != nil {
-			return nil, err
-		}
+	folderType := "dash-folder"
+	hits, err := searchAll(client, search.NewSearchParams().WithType(&folderType))
+	if err != nil {
+		return nil, err
+	}
 
-		for _, folder := range searchOk.GetPayload() {
-			uids = append(uids, folder.UID)
-		}
-		if int64(len(searchOk.GetPayload())) < *params.Limit {
-			return uids, nil
-		}
+	uids := make([]string, 0, len(hits))
+	for _, folder := range hits {
+		uids = append(uids, folder.UID)
 	}
+	return uids, nil
 }
 
 func (h *FolderHandler) postFolder(resource grizzly.Resource) error {
@@ -252,17 +279,31 @@ func (h *FolderHandler) postFolder(resource grizzly.Resource) error {
 		return fmt.Errorf("missing title in folder spec")
 	}
 
-	body := models.CreateFolderCommand{
-		Title:     folder.Title,
-		UID:       folder.UID,
-		ParentUID: folder.ParentUID,
-	}
 	client, err := h.Provider.(ClientProvider).Client()
 	if err != nil {
 		return err
 	}
 
+	// folders are created idempotently: one created meanwhile with the same
+	// UID is updated instead
+	_, err = getFolderByUID(client, folder.UID)
+	if err == nil {
+		return h.putFolder(resource)
+	}
+	if !errors.Is(err, grizzly.ErrNotFound) {
+		return err
+	}
+
+	body := models.CreateFolderCommand{
+		Title:     folder.Title,
+		UID:       folder.UID,
+		ParentUID: folder.ParentUID,
+	}
 	_, err = client.Folders.CreateFolder(&body, nil)
+	var gErrConflict *folders.CreateFolderConflict
+	if errors.As(err, &gErrConflict) {
+		return fmt.Errorf("cannot create folder %s: a folder titled '%s' already exists with another UID", folder.UID, folder.Title)
+	}
 	return err
 }
 
@@ -302,3 +343,34 @@ var getFolderByID = func(client *gclient.GrafanaHTTPAPI, folderId int64) (*model
 	}
 	return folderOk.GetPayload(), nil
 }
+
+var getFolderByUID = func(client *gclient.GrafanaHTTPAPI, uid string) (*models.Folder, error) {
+	folderOk, err := client.Folders.GetFolderByUID(uid)
+	if err != nil {
+		var gErrNotFound *folders.GetFolderByUIDNotFound
+		var gErrForbidden *folders.GetFolderByUIDForbidden
+		if errors.As(err, &gErrNotFound) || errors.As(err, &gErrForbidden) {
+			return nil, fmt.Errorf("couldn't fetch folder '%s' from remote: %w", uid, grizzly.ErrNotFound)
+		}
+		return nil, err
+	}
+	return folderOk.GetPayload(), nil
+}
+
+// searchFoldersByTitle retrieves the folders titled exactly as given
+var searchFoldersByTitle = func(client *gclient.GrafanaHTTPAPI, title string) ([]*models.Hit, error) {
+	folderType := "dash-folder"
+	params := search.NewSearchParams().WithQuery(&title).WithType(&folderType)
+	found, err := searchAll(client, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var hits []*models.Hit
+	for _, hit := range found {
+		if hit.Title == title {
+			hits = append(hits, hit)
+		}
+	}
+	return hits, nil
+}
@@ -1,8 +1,12 @@
 package grafana
 
 import (
+	"fmt"
 	"testing"
 
+	gclient "github.com/grafana/grafana-openapi-client-go/client"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/grafana/grizzly/pkg/config"
 	"github.com/grafana/grizzly/pkg/grizzly"
 	"github.com/stretchr/testify/require"
 )
@@ -95,3 +99,72 @@ func TestSortFolders(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveRemoteFolder(t *testing.T) {
+	// "shared" is both the UID of a folder, and the title of another one
+	remote := []*models.Folder{
+		{ID: 1, UID: "shared", Title: "Team A"},
+		{ID: 2, UID: "abcdef", Title: "shared"},
+		{ID: 3, UID: "ops-1", Title: "Ops"},
+		{ID: 4, UID: "ops-2", Title: "Ops"},
+	}
+
+	originalGetFolderByUID, originalSearchFoldersByTitle := getFolderByUID, searchFoldersByTitle
+	t.Cleanup(func() {
+		getFolderByUID, searchFoldersByTitle = originalGetFolderByUID, originalSearchFoldersByTitle
+	})
+	getFolderByUID = func(client *gclient.GrafanaHTTPAPI, uid string) (*models.Folder, error) {
+		for _, folder := range remote {
+			if folder.UID == uid {
+				return folder, nil
+			}
+		}
+		return nil, fmt.Errorf("couldn't fetch folder '%s' from remote: %w", uid, grizzly.ErrNotFound)
+	}
+	searchFoldersByTitle = func(client *gclient.GrafanaHTTPAPI, title string) ([]*models.Hit, error) {
+		var hits []*models.Hit
+		for _, folder := range remote {
+			if folder.Title == title {
+				hits = append(hits, &models.Hit{UID: folder.UID, Title: folder.Title})
+			}
+		}
+		return hits, nil
+	}
+
+	handler := NewFolderHandler(NewProvider(&config.GrafanaConfig{URL: "http://localhost:3000"}))
+
+	t.Run("UID takes precedence over a folder with the same title", func(t *testing.T) {
+		folder, err := handler.resolveRemoteFolder("shared")
+		require.NoError(t, err)
+		require.Equal(t, "shared", folder.Name())
+		require.Equal(t, "Team A", folder.Spec()["title"])
+	})
+
+	t.Run("falls back to title", func(t *testing.T) {
+		folder, err := handler.resolveRemoteFolder("Team A")
+		require.NoError(t, err)
+		require.Equal(t, "shared", folder.Name())
+	})
+
+	t.Run("ambiguous title", func(t *testing.T) {
+		_, err := handler.resolveRemoteFolder("Ops")
+		require.ErrorContains(t, err, "ambiguous")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := handler.resolveRemoteFolder("missing")
+		require.ErrorIs(t, err, grizzly.ErrNotFound)
+	})
+
+	t.Run("folders are resolved to their UID", func(t *testing.T) {
+		uid, err := handler.ResolveFolderUID("Team A")
+		require.NoError(t, err)
+		require.Equal(t, "shared", uid)
+	})
+
+	t.Run("the General folder is resolved to the UID dashboards refer to", func(t *testing.T) {
+		uid, err := handler.ResolveFolderUID("General")
+		require.NoError(t, err)
+		require.Equal(t, generalFolderUID, uid)
+	})
+}
@@ -0,0 +1,274 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	log "github.com/sirupsen/logrus"
+)
+
+const GenericAPIKind = "GenericAPIResource"
+
+const genericAPIPattern = "generic-api/resource-%s.%s"
+
+// genericAPIPathPrefix is the prefix of the paths of the Grafana API, which
+// the client already targets
+const genericAPIPathPrefix = "/api/"
+
+var _ grizzly.Handler = &GenericAPIHandler{}
+
+// GenericAPIHandler is a Grizzly Handler for Grafana API endpoints without a
+// dedicated handler. Each resource describes the endpoint managing it along
+// with the body to send, allowing to manage new Grafana features before
+// Grizzly supports them.
+type GenericAPIHandler struct {
+	grizzly.BaseHandler
+}
+
+// NewGenericAPIHandler returns a new Grizzly Handler for generic Grafana API resources
+func NewGenericAPIHandler(provider grizzly.Provider) *GenericAPIHandler {
+	return &GenericAPIHandler{
+		BaseHandler: grizzly.NewBaseHandler(provider, GenericAPIKind, false),
+	}
+}
+
+// genericAPIResource describes the spec of a generic resource
+type genericAPIResource struct {
+	// Path is the collection endpoint, resources being created with a POST to it
+	Path string `json:"path"`
+	// ItemPath is the endpoint of a single resource, where {id} is replaced by
+	// its ID. Defaults to <path>/{id}.
+	ItemPath string `json:"itemPath,omitempty"`
+	// IDField is the field of the body holding the ID of the resource.
+	// Defaults to uid.
+	IDField string `json:"idField,omitempty"`
+	// ResponsePath is the dot-separated path of the resource within the
+	// response of its endpoint (ex: dashboard). Defaults to the whole response.
+	ResponsePath string `json:"responsePath,omitempty"`
+	// UpdateMethod is the HTTP method updating the resource at its endpoint.
+	// Defaults to PUT.
+	UpdateMethod string         `json:"updateMethod,omitempty"`
+	Body         map[string]any `json:"body"`
+}
+
+// ResourceFilePath returns the location on disk where a resource should be updated
+func (h *GenericAPIHandler) ResourceFilePath(resource grizzly.Resource, filetype string) string {
+	filename := strings.ReplaceAll(resource.Name(), string(os.PathSeparator), "-")
+	return fmt.Sprintf(genericAPIPattern, filename, filetype)
+}
+
+// Validate checks that a resource describes its endpoint and holds an ID
+func (h *GenericAPIHandler) Validate(resource grizzly.Resource) error {
+	r, err := unmarshalGenericAPIResource(resource)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(r.Path, genericAPIPathPrefix) {
+		return fmt.Errorf("path of %s must start with %s, got '%s'", resource.Name(), genericAPIPathPrefix, r.Path)
+	}
+	if r.ItemPath != "" && (!strings.HasPrefix(r.ItemPath, genericAPIPathPrefix) || !strings.Contains(r.ItemPath, "{id}")) {
+		return fmt.Errorf("itemPath of %s must start with %s and contain {id}, got '%s'", resource.Name(), genericAPIPathPrefix, r.ItemPath)
+	}
+	switch r.UpdateMethod {
+	case http.MethodPut, http.MethodPost, http.MethodPatch:
+	default:
+		return fmt.Errorf("updateMethod of %s must be one of PUT, POST, PATCH, got '%s'", resource.Name(), r.UpdateMethod)
+	}
+	if _, err := r.id(); err != nil {
+		return fmt.Errorf("%s: %w", resource.Name(), err)
+	}
+	return nil
+}
+
+// GetSpecUID returns the UID of a generic resource, which is the name of the resource
+func (h *GenericAPIHandler) GetSpecUID(resource grizzly.Resource) (string, error) {
+	return resource.Name(), nil
+}
+
+// GetByUID isn't supported: the endpoint of a generic resource is only known
+// from its local definition
+func (h *GenericAPIHandler) GetByUID(uid string) (*grizzly.Resource, error) {
+	return nil, fmt.Errorf("%s resources can only be retrieved along with their local definition: %w", h.Kind(), grizzly.ErrNotImplemented)
+}
+
+// GetRemote retrieves a resource from the endpoint it describes. Only the
+// fields of the local body are kept, leaving out the ones added by Grafana.
+func (h *GenericAPIHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
+	r, err := unmarshalGenericAPIResource(resource)
+	if err != nil {
+		return nil, err
+	}
+	itemPath, err := r.itemPath()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := h.submit(http.MethodGet, itemPath, nil)
+	if err != nil {
+		return nil, wrapAPIError(err)
+	}
+
+	remoteBody, err := atResponsePath(response, r.ResponsePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", resource.Name(), err)
+	}
+	body := map[string]any{}
+	for key := range r.Body {
+		if value, ok := remoteBody[key]; ok {
+			body[key] = value
+		}
+	}
+
+	remote := resource.Clone()
+	remote.SetSpecValue("body", body)
+	return &remote, nil
+}
+
+// ListRemote returns no resources, as generic resources can't be discovered
+func (h *GenericAPIHandler) ListRemote() ([]string, error) {
+	log.Debugf("%s resources can't be listed", h.Kind())
+	return []string{}, nil
+}
+
+// Add creates a resource by posting its body to its collection endpoint
+func (h *GenericAPIHandler) Add(resource grizzly.Resource) error {
+	r, err := unmarshalGenericAPIResource(resource)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.submit(http.MethodPost, r.Path, r.Body)
+	return wrapAPIError(err)
+}
+
+// Update sends the body of a resource to its endpoint
+func (h *GenericAPIHandler) Update(existing, resource grizzly.Resource) error {
+	r, err := unmarshalGenericAPIResource(resource)
+	if err != nil {
+		return err
+	}
+	itemPath, err := r.itemPath()
+	if err != nil {
+		return err
+	}
+
+	_, err = h.submit(r.UpdateMethod, itemPath, r.Body)
+	return wrapAPIError(err)
+}
+
+// submit sends a request to the Grafana API, through the client shared with
+// other handlers (authentication, organization, TLS settings...), and returns
+// the decoded response
+func (h *GenericAPIHandler) submit(method string, apiPath string, body any) (any, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	// the client already targets the API root
+	pathPattern := "/" + strings.TrimPrefix(apiPath, genericAPIPathPrefix)
+	return client.Transport.Submit(&runtime.ClientOperation{
+		ID:                 "genericAPI",
+		Method:             method,
+		PathPattern:        pathPattern,
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"http", "https"},
+		Params: runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+			if body == nil {
+				return nil
+			}
+			return r.SetBodyParam(body)
+		}),
+		Reader: runtime.ClientResponseReaderFunc(func(response runtime.ClientResponse, _ runtime.Consumer) (any, error) {
+			content, err := io.ReadAll(response.Body())
+			if err != nil {
+				return nil, err
+			}
+			if response.Code() >= http.StatusMultipleChoices {
+				return nil, runtime.NewAPIError(fmt.Sprintf("%s %s", method, apiPath), string(content), response.Code())
+			}
+			if len(content) == 0 {
+				return nil, nil
+			}
+			var decoded any
+			if err := json.Unmarshal(content, &decoded); err != nil {
+				return nil, fmt.Errorf("%s %s: invalid JSON response: %w", method, apiPath, err)
+			}
+			return decoded, nil
+		}),
+	})
+}
+
+// id returns the ID of a resource, read from its body
+func (r genericAPIResource) id() (string, error) {
+	switch id := r.Body[r.IDField].(type) {
+	case string:
+		if id != "" {
+			return id, nil
+		}
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("body has no %s field, identifying the resource", r.IDField)
+}
+
+// itemPath returns the endpoint of the resource
+func (r genericAPIResource) itemPath() (string, error) {
+	id, err := r.id()
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(r.ItemPath, "{id}", url.PathEscape(id)), nil
+}
+
+// atResponsePath returns the object found at a dot-separated path of a response
+func atResponsePath(response any, responsePath string) (map[string]any, error) {
+	value := response
+	if responsePath != "" {
+		for _, key := range strings.Split(responsePath, ".") {
+			object, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("response has no %s field", responsePath)
+			}
+			value = object[key]
+		}
+	}
+
+	object, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("response at '%s' is not an object", responsePath)
+	}
+	return object, nil
+}
+
+func unmarshalGenericAPIResource(resource grizzly.Resource) (*genericAPIResource, error) {
+	data, err := json.Marshal(resource.Spec())
+	if err != nil {
+		return nil, err
+	}
+	var r genericAPIResource
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	if r.IDField == "" {
+		r.IDField = "uid"
+	}
+	if r.ItemPath == "" {
+		r.ItemPath = strings.TrimSuffix(r.Path, "/") + "/{id}"
+	}
+	r.UpdateMethod = strings.ToUpper(r.UpdateMethod)
+	if r.UpdateMethod == "" {
+		r.UpdateMethod = http.MethodPut
+	}
+	return &r, nil
+}
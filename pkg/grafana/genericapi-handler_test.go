@@ -0,0 +1,86 @@
+package grafana
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/config"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenericAPIHandler(t *testing.T) {
+	requests := []string{}
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/things/existing":
+			_, _ = w.Write([]byte(`{"thing": {"uid": "existing", "title": "Before", "created": "2024-01-01"}, "meta": {}}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "not found"}`))
+		default:
+			received = nil
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	handler := NewGenericAPIHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+
+	newResource := func(uid string, title string) grizzly.Resource {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "thing-"+uid, map[string]any{
+			"path":         "/api/things",
+			"responsePath": "thing",
+			"body":         map[string]any{"uid": uid, "title": title},
+		})
+		require.NoError(t, err)
+		return resource
+	}
+
+	t.Run("remote resources only keep the fields of the local body", func(t *testing.T) {
+		req := require.New(t)
+
+		remote, err := handler.GetRemote(newResource("existing", "After"))
+		req.NoError(err)
+		req.Equal(map[string]any{"uid": "existing", "title": "Before"}, remote.Spec()["body"])
+
+		_, err = handler.GetRemote(newResource("missing", "New"))
+		req.ErrorIs(err, grizzly.ErrNotFound)
+	})
+
+	t.Run("resources are created on their collection and updated at their endpoint", func(t *testing.T) {
+		req := require.New(t)
+		requests = nil
+
+		req.NoError(handler.Add(newResource("missing", "New")))
+		req.Equal("New", received["title"])
+		req.NoError(handler.Update(newResource("existing", "Before"), newResource("existing", "After")))
+		req.Equal("After", received["title"])
+		req.Equal([]string{"POST /api/things", "PUT /api/things/existing"}, requests)
+	})
+
+	t.Run("resources must describe their endpoint and ID", func(t *testing.T) {
+		req := require.New(t)
+
+		req.NoError(handler.Validate(newResource("existing", "Before")))
+
+		resource := newResource("existing", "Before")
+		resource.SetSpecValue("path", "/things")
+		req.ErrorContains(handler.Validate(resource), "must start with /api/")
+
+		resource = newResource("", "Before")
+		req.ErrorContains(handler.Validate(resource), "body has no uid field")
+
+		resource = newResource("existing", "Before")
+		resource.SetSpecValue("updateMethod", "DELETE")
+		req.ErrorContains(handler.Validate(resource), "updateMethod")
+	})
+}
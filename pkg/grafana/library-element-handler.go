@@ -106,7 +106,7 @@ func (h *LibraryElementHandler) GetSpecUID(resource grizzly.Resource) (string, e
 func (h *LibraryElementHandler) GetByUID(uid string) (*grizzly.Resource, error) {
 	resource, err := h.getRemoteLibraryElement(uid)
 	if err != nil {
-		return nil, fmt.Errorf("Error retrieving library element %s: %w", uid, err)
+		return nil, fmt.Errorf("Error retrieving library element %s: %w", uid, wrapAPIError(err))
 	}
 
 	return resource, nil
@@ -114,7 +114,7 @@ func (h *LibraryElementHandler) GetByUID(uid string) (*grizzly.Resource, error)
 
 // GetRemote retrieves an element as a resource
 func (h *LibraryElementHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
-	return h.getRemoteLibraryElement(resource.Name())
+	return wrapRemoteAPIError(h.getRemoteLibraryElement(resource.Name()))
 }
 
 // ListRemote retrieves as list of UIDs of all remote resources
@@ -124,12 +124,12 @@ func (h *LibraryElementHandler) ListRemote() ([]string, error) {
 
 // Add pushes a new element to Grafana via the API
 func (h *LibraryElementHandler) Add(resource grizzly.Resource) error {
-	return h.createElement(resource)
+	return wrapAPIError(h.createElement(resource))
 }
 
 // Update pushes an element to Grafana via the API
 func (h *LibraryElementHandler) Update(existing, resource grizzly.Resource) error {
-	return h.updateElement(existing, resource)
+	return wrapAPIError(h.updateElement(existing, resource))
 }
 
 func (h *LibraryElementHandler) listElements() ([]string, error) {
@@ -140,8 +140,9 @@ func (h *LibraryElementHandler) listElements() ([]string, error) {
 
 	var uids []string
 
+	// pages start at 1: Grafana treats page 0 as the first page
 	perPage := int64(100)
-	page := int64(0)
+	page := int64(1)
 	for {
 		params := library.NewGetLibraryElementsParams()
 		params.PerPage = &perPage
@@ -0,0 +1,179 @@
+package grafana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-openapi-client-go/client/provisioning"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/grafana/grizzly/pkg/grizzly"
+)
+
+const AlertMuteTimingKind = "AlertMuteTiming"
+
+const muteTimingPattern = "alert-mute-timings/muteTiming-%s.%s"
+
+var _ grizzly.Handler = &AlertMuteTimingHandler{}
+
+// AlertMuteTimingHandler is a Grizzly Handler for Grafana alerting mute timings
+type AlertMuteTimingHandler struct {
+	grizzly.BaseHandler
+}
+
+// NewAlertMuteTimingHandler returns a new Grizzly Handler for Grafana alerting mute timings
+func NewAlertMuteTimingHandler(provider grizzly.Provider) *AlertMuteTimingHandler {
+	return &AlertMuteTimingHandler{
+		BaseHandler: grizzly.NewBaseHandler(provider, AlertMuteTimingKind, false),
+	}
+}
+
+// ResourceFilePath returns the location on disk where a resource should be updated
+func (h *AlertMuteTimingHandler) ResourceFilePath(resource grizzly.Resource, filetype string) string {
+	filename := strings.ReplaceAll(resource.Name(), string(os.PathSeparator), "-")
+	return fmt.Sprintf(muteTimingPattern, filename, filetype)
+}
+
+// Prepare gets a resource ready for dispatch to the remote endpoint
+func (h *AlertMuteTimingHandler) Prepare(existing *grizzly.Resource, resource grizzly.Resource) *grizzly.Resource {
+	if !resource.HasSpecString("name") {
+		resource.SetSpecString("name", resource.Name())
+	}
+	return &resource
+}
+
+// Unprepare removes unnecessary elements from a remote resource ready for presentation/comparison
+func (h *AlertMuteTimingHandler) Unprepare(resource grizzly.Resource) *grizzly.Resource {
+	resource.DeleteSpecKey("version")
+	resource.DeleteSpecKey("provenance")
+	return &resource
+}
+
+// Validate checks that the name of a mute timing matches the name of its resource
+func (h *AlertMuteTimingHandler) Validate(resource grizzly.Resource) error {
+	name, exist := resource.GetSpecString("name")
+	if exist && name != resource.Name() {
+		return fmt.Errorf("spec.name '%s' and metadata.name '%s', don't match", name, resource.Name())
+	}
+	return nil
+}
+
+// GetSpecUID returns the UID of a mute timing, which is its name
+func (h *AlertMuteTimingHandler) GetSpecUID(resource grizzly.Resource) (string, error) {
+	name, ok := resource.GetSpecString("name")
+	if !ok {
+		return "", fmt.Errorf("name not specified")
+	}
+	return name, nil
+}
+
+// GetByUID retrieves JSON for a resource from an endpoint, by UID
+func (h *AlertMuteTimingHandler) GetByUID(uid string) (*grizzly.Resource, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Provisioning.GetMuteTiming(uid)
+	if err != nil {
+		var gErr *provisioning.GetMuteTimingNotFound
+		if errors.As(err, &gErr) {
+			return nil, grizzly.ErrNotFound
+		}
+		return nil, wrapAPIError(err)
+	}
+
+	spec, err := structToMap(response.GetPayload())
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := grizzly.NewResource(h.APIVersion(), h.Kind(), uid, spec)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// GetRemote retrieves a mute timing as a Resource
+func (h *AlertMuteTimingHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
+	return h.GetByUID(resource.Name())
+}
+
+// ListRemote retrieves a list of sorted UIDs of all remote mute timings
+func (h *AlertMuteTimingHandler) ListRemote() ([]string, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Provisioning.GetMuteTimings()
+	if err != nil {
+		return nil, err
+	}
+	muteTimings := response.GetPayload()
+	uids := make([]string, 0, len(muteTimings))
+	for _, muteTiming := range muteTimings {
+		uids = append(uids, muteTiming.Name)
+	}
+	sort.Strings(uids)
+	return uids, nil
+}
+
+// Add pushes a mute timing to Grafana via the API
+func (h *AlertMuteTimingHandler) Add(resource grizzly.Resource) error {
+	muteTiming, err := unmarshalMuteTiming(resource)
+	if err != nil {
+		return err
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	params := provisioning.NewPostMuteTimingParams().
+		WithBody(muteTiming).
+		WithXDisableProvenance(&stringtrue)
+	_, err = client.Provisioning.PostMuteTiming(params)
+	return wrapAPIError(err)
+}
+
+// Update pushes a mute timing to Grafana via the API
+func (h *AlertMuteTimingHandler) Update(existing, resource grizzly.Resource) error {
+	muteTiming, err := unmarshalMuteTiming(resource)
+	if err != nil {
+		return err
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	params := provisioning.NewPutMuteTimingParams().
+		WithName(resource.Name()).
+		WithBody(muteTiming).
+		WithXDisableProvenance(&stringtrue)
+	_, err = client.Provisioning.PutMuteTiming(params)
+	return wrapAPIError(err)
+}
+
+func unmarshalMuteTiming(resource grizzly.Resource) (*models.MuteTimeInterval, error) {
+	data, err := json.Marshal(resource.Spec())
+	if err != nil {
+		return nil, err
+	}
+	var muteTiming models.MuteTimeInterval
+	err = json.Unmarshal(data, &muteTiming)
+	if err != nil {
+		return nil, err
+	}
+	if muteTiming.Name == "" {
+		muteTiming.Name = resource.Name()
+	}
+	return &muteTiming, nil
+}
@@ -0,0 +1,38 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMuteTimingHandler(t *testing.T) {
+	handler := NewAlertMuteTimingHandler(&Provider{})
+
+	t.Run("name must match the resource name", func(t *testing.T) {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "weekends", map[string]any{"name": "nights"})
+		require.NoError(t, err)
+		require.Error(t, handler.Validate(resource))
+	})
+
+	t.Run("missing name is taken from the resource name", func(t *testing.T) {
+		req := require.New(t)
+
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "weekends", map[string]any{
+			"time_intervals": []any{map[string]any{"weekdays": []any{"saturday", "sunday"}}},
+		})
+		req.NoError(err)
+		req.NoError(handler.Validate(resource))
+
+		prepared := handler.Prepare(nil, resource)
+		uid, err := handler.GetSpecUID(*prepared)
+		req.NoError(err)
+		req.Equal("weekends", uid)
+
+		muteTiming, err := unmarshalMuteTiming(*prepared)
+		req.NoError(err)
+		req.Equal("weekends", muteTiming.Name)
+		req.Equal([]string{"saturday", "sunday"}, muteTiming.TimeIntervals[0].Weekdays)
+	})
+}
@@ -0,0 +1,154 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+)
+
+var _ grizzly.DiffRenderHandler = &AlertNotificationPolicyHandler{}
+
+// Diff renders the changes made to the notification policy tree route by
+// route: changed fields are shown under the route holding them, which is
+// described by its receiver and matchers.
+func (h *AlertNotificationPolicyHandler) Diff(remote, local grizzly.Resource) (string, error) {
+	var sb strings.Builder
+	if err := diffRoutes(&sb, "root", remote.Spec(), local.Spec(), 0); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// diffRoutes writes the changes from a remote route to a local one, then
+// compares their child routes, position by position
+func diffRoutes(sb *strings.Builder, path string, remote, local map[string]any, depth int) error {
+	if reflect.DeepEqual(remote, local) {
+		return nil
+	}
+
+	remoteRoutes, err := childRoutes(remote)
+	if err != nil {
+		return err
+	}
+	localRoutes, err := childRoutes(local)
+	if err != nil {
+		return err
+	}
+
+	indent := strings.Repeat("  ", depth)
+	fmt.Fprintf(sb, "~ %s%s %s\n", indent, path, routeSummary(local))
+
+	fields := map[string]bool{}
+	for field := range remote {
+		fields[field] = true
+	}
+	for field := range local {
+		fields[field] = true
+	}
+	delete(fields, "routes")
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	for _, field := range names {
+		remoteValue, inRemote := remote[field]
+		localValue, inLocal := local[field]
+		if inRemote && inLocal && reflect.DeepEqual(remoteValue, localValue) {
+			continue
+		}
+		if inRemote {
+			fmt.Fprintf(sb, "- %s  %s: %s\n", indent, field, formatRouteValue(remoteValue))
+		}
+		if inLocal {
+			fmt.Fprintf(sb, "+ %s  %s: %s\n", indent, field, formatRouteValue(localValue))
+		}
+	}
+
+	for i := 0; i < max(len(remoteRoutes), len(localRoutes)); i++ {
+		childPath := fmt.Sprintf("routes[%d]", i)
+		switch {
+		case i >= len(remoteRoutes):
+			fmt.Fprintf(sb, "+ %s  %s %s\n", indent, childPath, routeSummary(localRoutes[i]))
+		case i >= len(localRoutes):
+			fmt.Fprintf(sb, "- %s  %s %s\n", indent, childPath, routeSummary(remoteRoutes[i]))
+		default:
+			if err := diffRoutes(sb, childPath, remoteRoutes[i], localRoutes[i], depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// childRoutes returns the nested routes of a route
+func childRoutes(route map[string]any) ([]map[string]any, error) {
+	value, ok := route["routes"]
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	list, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("routes must be a list, got %T", value)
+	}
+
+	routes := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		child, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("route must be an object, got %T", item)
+		}
+		routes = append(routes, child)
+	}
+	return routes, nil
+}
+
+// routeSummary describes a route by its receiver and matchers
+func routeSummary(route map[string]any) string {
+	parts := []string{}
+	if receiver, ok := route["receiver"].(string); ok && receiver != "" {
+		parts = append(parts, "receiver: "+receiver)
+	}
+
+	matchers := []string{}
+	if objectMatchers, ok := route["object_matchers"].([]any); ok {
+		for _, matcher := range objectMatchers {
+			if terms, ok := matcher.([]any); ok {
+				matchers = append(matchers, fmt.Sprint(terms...))
+			}
+		}
+	}
+	if plainMatchers, ok := route["matchers"].([]any); ok {
+		for _, matcher := range plainMatchers {
+			matchers = append(matchers, fmt.Sprint(matcher))
+		}
+	}
+	if len(matchers) > 0 {
+		parts = append(parts, "matchers: "+strings.Join(matchers, ", "))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(parts, "; ") + ")"
+}
+
+// formatRouteValue renders a field of a route on a single line
+func formatRouteValue(value any) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(content)
+}
@@ -0,0 +1,58 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertNotificationPolicyHandler_Diff(t *testing.T) {
+	handler := NewAlertNotificationPolicyHandler(&Provider{})
+
+	newPolicy := func(spec map[string]any) grizzly.Resource {
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), GlobalAlertNotificationPolicyName, spec)
+		require.NoError(t, err)
+		return resource
+	}
+
+	t.Run("changes are shown under the route holding them", func(t *testing.T) {
+		remote := newPolicy(map[string]any{
+			"receiver": "email",
+			"group_by": []any{"alertname"},
+			"routes": []any{
+				map[string]any{
+					"receiver":        "oncall",
+					"object_matchers": []any{[]any{"team", "=", "a"}},
+				},
+				map[string]any{"receiver": "slack"},
+			},
+		})
+		local := newPolicy(map[string]any{
+			"receiver": "email",
+			"group_by": []any{"alertname"},
+			"routes": []any{
+				map[string]any{
+					"receiver":        "oncall",
+					"object_matchers": []any{[]any{"team", "=", "b"}},
+				},
+				map[string]any{"receiver": "slack"},
+				map[string]any{"receiver": "webhook", "matchers": []any{"severity=critical"}},
+			},
+		})
+
+		diff, err := handler.Diff(remote, local)
+		require.NoError(t, err)
+		require.Equal(t, `~ root (receiver: email)
+~   routes[0] (receiver: oncall; matchers: team=b)
+-     object_matchers: [["team","=","a"]]
++     object_matchers: [["team","=","b"]]
++   routes[2] (receiver: webhook; matchers: severity=critical)
+`, diff)
+	})
+
+	t.Run("invalid routes are reported", func(t *testing.T) {
+		_, err := handler.Diff(newPolicy(map[string]any{"routes": "none"}), newPolicy(map[string]any{}))
+		require.Error(t, err)
+	})
+}
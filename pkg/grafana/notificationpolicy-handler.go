@@ -51,12 +51,12 @@ func (h *AlertNotificationPolicyHandler) GetSpecUID(resource grizzly.Resource) (
 
 // GetByUID retrieves JSON for a resource from an endpoint, by UID
 func (h *AlertNotificationPolicyHandler) GetByUID(uid string) (*grizzly.Resource, error) {
-	return h.getRemoteAlertNotificationPolicy()
+	return wrapRemoteAPIError(h.getRemoteAlertNotificationPolicy())
 }
 
 // GetRemote retrieves a alertNotificationPolicy as a Resource
 func (h *AlertNotificationPolicyHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
-	return h.getRemoteAlertNotificationPolicy()
+	return wrapRemoteAPIError(h.getRemoteAlertNotificationPolicy())
 }
 
 // ListRemote retrieves as list of UIDs of all remote resources
@@ -66,12 +66,12 @@ func (h *AlertNotificationPolicyHandler) ListRemote() ([]string, error) {
 
 // Add pushes a alertNotificationPolicy to Grafana via the API
 func (h *AlertNotificationPolicyHandler) Add(resource grizzly.Resource) error {
-	return h.putAlertNotificationPolicy(resource)
+	return wrapAPIError(h.putAlertNotificationPolicy(resource))
 }
 
 // Update pushes a alertNotificationPolicy to Grafana via the API
 func (h *AlertNotificationPolicyHandler) Update(existing, resource grizzly.Resource) error {
-	return h.putAlertNotificationPolicy(resource)
+	return wrapAPIError(h.putAlertNotificationPolicy(resource))
 }
 
 // getRemoteAlertNotificationPolicy retrieves a alertNotificationPolicy object from Grafana
@@ -93,7 +93,7 @@ func (h *AlertNotificationTemplateHandler) GetByUID(uid string) (*grizzly.Resour
 		if errors.As(err, &gErr) || strings.Contains(err.Error(), "not supported by the TextConsumer, can be resolved by supporting TextUnmarshaler interface") {
 			return nil, grizzly.ErrNotFound
 		}
-		return nil, err
+		return nil, wrapAPIError(err)
 	}
 
 	spec, err := structToMap(response.GetPayload())
@@ -150,7 +150,7 @@ func (h *AlertNotificationTemplateHandler) Add(resource grizzly.Resource) error
 		}).
 		WithXDisableProvenance(&stringtrue)
 	_, err = client.Provisioning.PutTemplate(params)
-	return err
+	return wrapAPIError(err)
 }
 
 // Update pushes a contactPoint to Grafana via the API
@@ -0,0 +1,348 @@
+package grafana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	gclient "github.com/grafana/grafana-openapi-client-go/client"
+	"github.com/grafana/grafana-openapi-client-go/client/dashboard_permissions"
+	"github.com/grafana/grafana-openapi-client-go/client/folder_permissions"
+	"github.com/grafana/grafana-openapi-client-go/client/teams"
+	"github.com/grafana/grafana-openapi-client-go/client/users"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/grafana/grizzly/pkg/grizzly"
+)
+
+const (
+	DashboardPermissionKind = "DashboardPermission"
+	FolderPermissionKind    = "FolderPermission"
+)
+
+var _ grizzly.Handler = &PermissionHandler{}
+var _ grizzly.CanonicalizeHandler = &PermissionHandler{}
+
+// PermissionHandler is a Grizzly Handler for the permissions of Grafana
+// dashboards or folders
+type PermissionHandler struct {
+	grizzly.BaseHandler
+}
+
+// NewDashboardPermissionHandler returns a new Grizzly Handler for the permissions of Grafana dashboards
+func NewDashboardPermissionHandler(provider grizzly.Provider) *PermissionHandler {
+	return &PermissionHandler{
+		BaseHandler: grizzly.NewBaseHandler(provider, DashboardPermissionKind, false),
+	}
+}
+
+// NewFolderPermissionHandler returns a new Grizzly Handler for the permissions of Grafana folders
+func NewFolderPermissionHandler(provider grizzly.Provider) *PermissionHandler {
+	return &PermissionHandler{
+		BaseHandler: grizzly.NewBaseHandler(provider, FolderPermissionKind, false),
+	}
+}
+
+const (
+	dashboardPermissionPattern = "permissions/dashboard-%s.%s"
+	folderPermissionPattern    = "permissions/folder-%s.%s"
+)
+
+var permissionNames = map[models.PermissionType]string{
+	1: "View",
+	2: "Edit",
+	4: "Admin",
+}
+
+// permissionEntry grants a permission to a role, a team or a user. Teams and
+// users are referenced by name and login, as their IDs differ between
+// instances.
+type permissionEntry struct {
+	Role       string `json:"role,omitempty"`
+	Team       string `json:"team,omitempty"`
+	User       string `json:"user,omitempty"`
+	Permission string `json:"permission"`
+}
+
+// less orders permission entries by role, team, user and permission
+func (entry permissionEntry) less(other permissionEntry) bool {
+	if entry.Role != other.Role {
+		return entry.Role < other.Role
+	}
+	if entry.Team != other.Team {
+		return entry.Team < other.Team
+	}
+	if entry.User != other.User {
+		return entry.User < other.User
+	}
+	return entry.Permission < other.Permission
+}
+
+// toPermissionEntry reads a permission entry from its generic
+// representation, as found in the spec of a resource
+func toPermissionEntry(rawEntry any) permissionEntry {
+	entry, _ := rawEntry.(map[string]any)
+	role, _ := entry["role"].(string)
+	team, _ := entry["team"].(string)
+	user, _ := entry["user"].(string)
+	permission, _ := entry["permission"].(string)
+	return permissionEntry{Role: role, Team: team, User: user, Permission: permission}
+}
+
+type permissions struct {
+	UID         string            `json:"uid"`
+	Permissions []permissionEntry `json:"permissions"`
+}
+
+// ResourceFilePath returns the location on disk where a resource should be updated
+func (h *PermissionHandler) ResourceFilePath(resource grizzly.Resource, filetype string) string {
+	if h.Kind() == FolderPermissionKind {
+		return fmt.Sprintf(folderPermissionPattern, resource.Name(), filetype)
+	}
+	return fmt.Sprintf(dashboardPermissionPattern, resource.Name(), filetype)
+}
+
+// Prepare gets a resource ready for dispatch to the remote endpoint
+func (h *PermissionHandler) Prepare(existing *grizzly.Resource, resource grizzly.Resource) *grizzly.Resource {
+	if !resource.HasSpecString("uid") {
+		resource.SetSpecString("uid", resource.Name())
+	}
+	return &resource
+}
+
+// Canonicalize sorts permission entries by role, team and user, as Grafana
+// doesn't keep them in the order they were given
+func (h *PermissionHandler) Canonicalize(resource grizzly.Resource) grizzly.Resource {
+	resource = resource.Clone()
+	entries, ok := resource.Spec()["permissions"].([]any)
+	if !ok {
+		return resource
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return toPermissionEntry(entries[i]).less(toPermissionEntry(entries[j]))
+	})
+	return resource
+}
+
+// Validate checks that the uid matches the name of the resource, and that
+// permission entries are valid
+func (h *PermissionHandler) Validate(resource grizzly.Resource) error {
+	uid, exist := resource.GetSpecString("uid")
+	if exist && uid != resource.Name() {
+		return ErrUIDNameMismatch{UID: uid, Name: resource.Name()}
+	}
+
+	p, err := unmarshalPermissions(resource)
+	if err != nil {
+		return err
+	}
+	for _, entry := range p.Permissions {
+		if _, err := permissionType(entry.Permission); err != nil {
+			return err
+		}
+		if countSet(entry.Role, entry.Team, entry.User) != 1 {
+			return fmt.Errorf("permission entries must reference exactly one of role, team or user")
+		}
+	}
+	return nil
+}
+
+func (h *PermissionHandler) GetSpecUID(resource grizzly.Resource) (string, error) {
+	uid, ok := resource.GetSpecString("uid")
+	if !ok {
+		return "", fmt.Errorf("UID not specified")
+	}
+	return uid, nil
+}
+
+// GetByUID retrieves JSON for a resource from an endpoint, by UID
+func (h *PermissionHandler) GetByUID(uid string) (*grizzly.Resource, error) {
+	return wrapRemoteAPIError(h.getRemotePermissions(uid))
+}
+
+// GetRemote retrieves the permissions of a dashboard or folder as a Resource
+func (h *PermissionHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
+	return wrapRemoteAPIError(h.getRemotePermissions(resource.Name()))
+}
+
+// ListRemote retrieves as list of UIDs of all remote resources
+func (h *PermissionHandler) ListRemote() ([]string, error) {
+	if h.Kind() == FolderPermissionKind {
+		return NewFolderHandler(h.Provider).ListRemote()
+	}
+	return NewDashboardHandler(h.Provider).ListRemote()
+}
+
+// Add pushes permissions to Grafana via the API
+func (h *PermissionHandler) Add(resource grizzly.Resource) error {
+	return wrapAPIError(h.putPermissions(resource))
+}
+
+// Update pushes permissions to Grafana via the API
+func (h *PermissionHandler) Update(existing, resource grizzly.Resource) error {
+	return wrapAPIError(h.putPermissions(resource))
+}
+
+// getRemotePermissions retrieves the permissions explicitly set on a
+// dashboard or folder. Inherited permissions are left out.
+func (h *PermissionHandler) getRemotePermissions(uid string) (*grizzly.Resource, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	var items []*models.DashboardACLInfoDTO
+	if h.Kind() == FolderPermissionKind {
+		permissionsOk, err := client.FolderPermissions.GetFolderPermissionList(uid)
+		if err != nil {
+			var gErr *folder_permissions.GetFolderPermissionListNotFound
+			if errors.As(err, &gErr) {
+				return nil, grizzly.ErrNotFound
+			}
+			return nil, err
+		}
+		items = permissionsOk.GetPayload()
+	} else {
+		permissionsOk, err := client.DashboardPermissions.GetDashboardPermissionsListByUID(uid)
+		if err != nil {
+			var gErr *dashboard_permissions.GetDashboardPermissionsListByUIDNotFound
+			if errors.As(err, &gErr) {
+				return nil, grizzly.ErrNotFound
+			}
+			return nil, err
+		}
+		items = permissionsOk.GetPayload()
+	}
+
+	p := permissions{UID: uid, Permissions: []permissionEntry{}}
+	for _, item := range items {
+		if item.Inherited {
+			continue
+		}
+		p.Permissions = append(p.Permissions, permissionEntry{
+			Role:       item.Role,
+			Team:       item.Team,
+			User:       item.UserLogin,
+			Permission: permissionNames[item.Permission],
+		})
+	}
+	sort.SliceStable(p.Permissions, func(i, j int) bool {
+		return p.Permissions[i].less(p.Permissions[j])
+	})
+
+	spec, err := structToMap(p)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := grizzly.NewResource(h.APIVersion(), h.Kind(), uid, spec)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (h *PermissionHandler) putPermissions(resource grizzly.Resource) error {
+	p, err := unmarshalPermissions(resource)
+	if err != nil {
+		return err
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	command := &models.UpdateDashboardACLCommand{
+		Items: make([]*models.DashboardACLUpdateItem, 0, len(p.Permissions)),
+	}
+	for _, entry := range p.Permissions {
+		item, err := resolvePermissionEntry(client, entry)
+		if err != nil {
+			return fmt.Errorf("resolving permissions of %s: %w", resource.Name(), err)
+		}
+		command.Items = append(command.Items, item)
+	}
+
+	if h.Kind() == FolderPermissionKind {
+		_, err = client.FolderPermissions.UpdateFolderPermissions(resource.Name(), command)
+		return err
+	}
+	_, err = client.DashboardPermissions.UpdateDashboardPermissionsByUID(resource.Name(), command)
+	return err
+}
+
+// resolvePermissionEntry looks up the IDs of teams and users on the remote
+// instance
+func resolvePermissionEntry(client *gclient.GrafanaHTTPAPI, entry permissionEntry) (*models.DashboardACLUpdateItem, error) {
+	permission, err := permissionType(entry.Permission)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.DashboardACLUpdateItem{
+		Permission: permission,
+		Role:       entry.Role,
+	}
+
+	if entry.Team != "" {
+		teamsOk, err := client.Teams.SearchTeams(teams.NewSearchTeamsParams().WithName(&entry.Team))
+		if err != nil {
+			return nil, err
+		}
+		for _, team := range teamsOk.GetPayload().Teams {
+			if team.Name == entry.Team {
+				item.TeamID = team.ID
+			}
+		}
+		if item.TeamID == 0 {
+			return nil, fmt.Errorf("team %s not found", entry.Team)
+		}
+	}
+
+	if entry.User != "" {
+		userOk, err := client.Users.GetUserByLoginOrEmail(entry.User)
+		if err != nil {
+			var gErr *users.GetUserByLoginOrEmailNotFound
+			if errors.As(err, &gErr) {
+				return nil, fmt.Errorf("user %s not found", entry.User)
+			}
+			return nil, err
+		}
+		item.UserID = userOk.GetPayload().ID
+	}
+
+	return item, nil
+}
+
+func permissionType(name string) (models.PermissionType, error) {
+	for permission, permissionName := range permissionNames {
+		if permissionName == name {
+			return permission, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown permission '%s', expected one of View, Edit or Admin", name)
+}
+
+func countSet(values ...string) int {
+	count := 0
+	for _, value := range values {
+		if value != "" {
+			count++
+		}
+	}
+	return count
+}
+
+func unmarshalPermissions(resource grizzly.Resource) (*permissions, error) {
+	data, err := json.Marshal(resource.Spec())
+	if err != nil {
+		return nil, err
+	}
+	var p permissions
+	err = json.Unmarshal(data, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
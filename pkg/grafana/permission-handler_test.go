@@ -0,0 +1,100 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionHandler_Validate(t *testing.T) {
+	handler := NewDashboardPermissionHandler(&Provider{})
+
+	tests := []struct {
+		name          string
+		permissions   []any
+		expectedError string
+	}{
+		{
+			name: "valid entries",
+			permissions: []any{
+				map[string]any{"role": "Viewer", "permission": "View"},
+				map[string]any{"team": "SRE", "permission": "Admin"},
+				map[string]any{"user": "jdoe", "permission": "Edit"},
+			},
+		},
+		{
+			name:          "unknown permission",
+			permissions:   []any{map[string]any{"role": "Viewer", "permission": "Read"}},
+			expectedError: "unknown permission 'Read', expected one of View, Edit or Admin",
+		},
+		{
+			name:          "entry referencing both a team and a user",
+			permissions:   []any{map[string]any{"team": "SRE", "user": "jdoe", "permission": "View"}},
+			expectedError: "permission entries must reference exactly one of role, team or user",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+			resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dashboard-uid", map[string]any{
+				"uid":         "dashboard-uid",
+				"permissions": test.permissions,
+			})
+			req.NoError(err)
+
+			err = handler.Validate(resource)
+			if test.expectedError != "" {
+				req.EqualError(err, test.expectedError)
+				return
+			}
+			req.NoError(err)
+		})
+	}
+}
+
+func TestPermissionHandler_ResourceFilePath(t *testing.T) {
+	req := require.New(t)
+
+	dashboardPermissions, err := grizzly.NewResource("grizzly.grafana.com/v1alpha1", DashboardPermissionKind, "abc", map[string]any{})
+	req.NoError(err)
+	folderPermissions, err := grizzly.NewResource("grizzly.grafana.com/v1alpha1", FolderPermissionKind, "abc", map[string]any{})
+	req.NoError(err)
+
+	req.Equal("permissions/dashboard-abc.yaml", NewDashboardPermissionHandler(&Provider{}).ResourceFilePath(dashboardPermissions, "yaml"))
+	req.Equal("permissions/folder-abc.yaml", NewFolderPermissionHandler(&Provider{}).ResourceFilePath(folderPermissions, "yaml"))
+}
+
+func TestPermissionHandler_Canonicalize(t *testing.T) {
+	req := require.New(t)
+	handler := NewDashboardPermissionHandler(&Provider{})
+
+	local, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dashboard-uid", map[string]any{
+		"uid": "dashboard-uid",
+		"permissions": []any{
+			map[string]any{"user": "jdoe", "permission": "Edit"},
+			map[string]any{"team": "SRE", "permission": "Admin"},
+			map[string]any{"role": "Viewer", "permission": "View"},
+		},
+	})
+	req.NoError(err)
+	// as returned by getRemotePermissions
+	remote, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "dashboard-uid", map[string]any{
+		"uid": "dashboard-uid",
+		"permissions": []any{
+			map[string]any{"team": "SRE", "permission": "Admin"},
+			map[string]any{"user": "jdoe", "permission": "Edit"},
+			map[string]any{"role": "Viewer", "permission": "View"},
+		},
+	})
+	req.NoError(err)
+
+	canonicalLocal := handler.Canonicalize(local)
+	canonicalRemote := handler.Canonicalize(remote)
+	req.Equal(canonicalRemote.Spec(), canonicalLocal.Spec(), "the order of local entries doesn't matter")
+	req.Equal(map[string]any{"user": "jdoe", "permission": "Edit"}, canonicalLocal.Spec()["permissions"].([]any)[0])
+	req.Equal(map[string]any{"role": "Viewer", "permission": "View"}, canonicalLocal.Spec()["permissions"].([]any)[2])
+	req.Equal(map[string]any{"role": "Viewer", "permission": "View"}, canonicalRemote.Spec()["permissions"].([]any)[2])
+	req.Equal(map[string]any{"team": "SRE", "permission": "Admin"}, remote.Spec()["permissions"].([]any)[0], "the given resource is left untouched")
+}
@@ -0,0 +1,233 @@
+package grafana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/grafana/grafana-openapi-client-go/client/playlists"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/grafana/grizzly/pkg/grizzly"
+)
+
+const PlaylistKind = "Playlist"
+
+const playlistItemDashboardByUID = "dashboard_by_uid"
+
+var _ grizzly.Handler = &PlaylistHandler{}
+var _ grizzly.ReferenceHandler = &PlaylistHandler{}
+
+// PlaylistHandler is a Grizzly Handler for Grafana playlists
+type PlaylistHandler struct {
+	grizzly.BaseHandler
+}
+
+// NewPlaylistHandler returns a new Grizzly Handler for Grafana playlists
+func NewPlaylistHandler(provider grizzly.Provider) *PlaylistHandler {
+	return &PlaylistHandler{
+		BaseHandler: grizzly.NewBaseHandler(provider, PlaylistKind, false),
+	}
+}
+
+const (
+	playlistPattern = "playlists/playlist-%s.%s"
+)
+
+// playlist describes a playlist along with its items. Unlike the models
+// provided by the Grafana client, it allows creating playlists with a known UID.
+type playlist struct {
+	UID      string         `json:"uid"`
+	Name     string         `json:"name"`
+	Interval string         `json:"interval"`
+	Items    []playlistItem `json:"items"`
+}
+
+type playlistItem struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// ResourceFilePath returns the location on disk where a resource should be updated
+func (h *PlaylistHandler) ResourceFilePath(resource grizzly.Resource, filetype string) string {
+	return fmt.Sprintf(playlistPattern, resource.Name(), filetype)
+}
+
+// Prepare gets a resource ready for dispatch to the remote endpoint
+func (h *PlaylistHandler) Prepare(existing *grizzly.Resource, resource grizzly.Resource) *grizzly.Resource {
+	if !resource.HasSpecString("uid") {
+		resource.SetSpecString("uid", resource.Name())
+	}
+	return &resource
+}
+
+// Validate returns the uid of resource
+func (h *PlaylistHandler) Validate(resource grizzly.Resource) error {
+	uid, exist := resource.GetSpecString("uid")
+	if exist && uid != resource.Name() {
+		return ErrUIDNameMismatch{UID: uid, Name: resource.Name()}
+	}
+	return nil
+}
+
+func (h *PlaylistHandler) GetSpecUID(resource grizzly.Resource) (string, error) {
+	uid, ok := resource.GetSpecString("uid")
+	if !ok {
+		return "", fmt.Errorf("UID not specified")
+	}
+	return uid, nil
+}
+
+// GetByUID retrieves JSON for a resource from an endpoint, by UID
+func (h *PlaylistHandler) GetByUID(uid string) (*grizzly.Resource, error) {
+	return wrapRemoteAPIError(h.getRemotePlaylist(uid))
+}
+
+// GetRemote retrieves a playlist as a Resource
+func (h *PlaylistHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
+	return wrapRemoteAPIError(h.getRemotePlaylist(resource.Name()))
+}
+
+// ListRemote retrieves as list of UIDs of all remote resources
+func (h *PlaylistHandler) ListRemote() ([]string, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	playlistsOk, err := client.Playlists.SearchPlaylists(playlists.NewSearchPlaylistsParams())
+	if err != nil {
+		return nil, err
+	}
+	remotePlaylists := playlistsOk.GetPayload()
+	uids := make([]string, len(remotePlaylists))
+	for i, remotePlaylist := range remotePlaylists {
+		uids[i] = remotePlaylist.UID
+	}
+	return uids, nil
+}
+
+// Add pushes a playlist to Grafana via the API
+func (h *PlaylistHandler) Add(resource grizzly.Resource) error {
+	p, err := unmarshalPlaylist(resource)
+	if err != nil {
+		return err
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	// models.CreatePlaylistCommand has no UID field: the body is written
+	// directly, so that the playlist is created with the UID of the resource.
+	_, err = client.Playlists.CreatePlaylist(nil, func(op *runtime.ClientOperation) {
+		op.Params = runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+			return r.SetBodyParam(p)
+		})
+	})
+	return wrapAPIError(err)
+}
+
+// Update pushes a playlist to Grafana via the API
+func (h *PlaylistHandler) Update(existing, resource grizzly.Resource) error {
+	p, err := unmarshalPlaylist(resource)
+	if err != nil {
+		return err
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	items := make([]*models.PlaylistItem, len(p.Items))
+	for i, item := range p.Items {
+		items[i] = &models.PlaylistItem{Type: item.Type, Value: item.Value}
+	}
+	_, err = client.Playlists.UpdatePlaylist(p.UID, &models.UpdatePlaylistCommand{
+		UID:      p.UID,
+		Name:     p.Name,
+		Interval: p.Interval,
+		Items:    items,
+	})
+	return wrapAPIError(err)
+}
+
+// getRemotePlaylist retrieves a playlist object, along with its items, from Grafana
+func (h *PlaylistHandler) getRemotePlaylist(uid string) (*grizzly.Resource, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	playlistOk, err := client.Playlists.GetPlaylist(uid)
+	if err != nil {
+		var gErr *playlists.GetPlaylistNotFound
+		if errors.As(err, &gErr) {
+			return nil, grizzly.ErrNotFound
+		}
+		return nil, err
+	}
+	remotePlaylist := playlistOk.GetPayload()
+
+	itemsOk, err := client.Playlists.GetPlaylistItems(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	p := playlist{
+		UID:      remotePlaylist.UID,
+		Name:     remotePlaylist.Name,
+		Interval: remotePlaylist.Interval,
+		Items:    []playlistItem{},
+	}
+	for _, item := range itemsOk.GetPayload() {
+		p.Items = append(p.Items, playlistItem{Type: item.Type, Value: item.Value})
+	}
+
+	spec, err := structToMap(p)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := grizzly.NewResource(h.APIVersion(), h.Kind(), uid, spec)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// UnresolvedReferences describes the playlist items referencing dashboards,
+// by UID, which aren't among the local resources
+func (h *PlaylistHandler) UnresolvedReferences(resource grizzly.Resource, resources grizzly.Resources) []string {
+	p, err := unmarshalPlaylist(resource)
+	if err != nil {
+		return nil
+	}
+
+	var unresolved []string
+	for _, item := range p.Items {
+		if item.Type != playlistItemDashboardByUID {
+			continue
+		}
+		if _, found := resources.Find(grizzly.NewResourceRef(DashboardKind, item.Value)); !found {
+			unresolved = append(unresolved, fmt.Sprintf("references dashboard %s, which isn't among the local resources", item.Value))
+		}
+	}
+	return unresolved
+}
+
+func unmarshalPlaylist(resource grizzly.Resource) (*playlist, error) {
+	data, err := json.Marshal(resource.Spec())
+	if err != nil {
+		return nil, err
+	}
+	var p playlist
+	err = json.Unmarshal(data, &p)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
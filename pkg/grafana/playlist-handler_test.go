@@ -0,0 +1,54 @@
+package grafana
+
+import (
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaylistHandler_Validate(t *testing.T) {
+	handler := NewPlaylistHandler(&Provider{})
+
+	t.Run("uid and name must match", func(t *testing.T) {
+		req := require.New(t)
+
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "noc", map[string]any{"uid": "other"})
+		req.NoError(err)
+
+		req.Equal(ErrUIDNameMismatch{UID: "other", Name: "noc"}, handler.Validate(resource))
+	})
+
+	t.Run("missing uid is taken from the name", func(t *testing.T) {
+		req := require.New(t)
+
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "noc", map[string]any{"name": "NOC"})
+		req.NoError(err)
+		req.NoError(handler.Validate(resource))
+
+		prepared := handler.Prepare(nil, resource)
+		playlist, err := unmarshalPlaylist(*prepared)
+		req.NoError(err)
+		req.Equal("noc", playlist.UID)
+	})
+}
+
+func TestPlaylistHandler_UnresolvedReferences(t *testing.T) {
+	req := require.New(t)
+	handler := NewPlaylistHandler(&Provider{})
+
+	playlist, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "noc", map[string]any{
+		"uid": "noc",
+		"items": []any{
+			map[string]any{"type": "dashboard_by_uid", "value": "overview"},
+			map[string]any{"type": "dashboard_by_uid", "value": "latency"},
+			map[string]any{"type": "dashboard_by_tag", "value": "noc"},
+		},
+	})
+	req.NoError(err)
+	dashboard, err := grizzly.NewResource(handler.APIVersion(), DashboardKind, "overview", map[string]any{"uid": "overview"})
+	req.NoError(err)
+
+	unresolved := handler.UnresolvedReferences(playlist, grizzly.NewResources(playlist, dashboard))
+	req.Equal([]string{"references dashboard latency, which isn't among the local resources"}, unresolved)
+}
@@ -1,14 +1,20 @@
 package grafana
 
 import (
+	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"os"
+	"path"
 	"path/filepath"
 
 	gclient "github.com/grafana/grafana-openapi-client-go/client"
+	"github.com/grafana/grafana-openapi-client-go/client/org"
 	"github.com/grafana/grizzly/internal/httputils"
 	"github.com/grafana/grizzly/pkg/config"
 	"github.com/grafana/grizzly/pkg/grizzly"
@@ -18,10 +24,13 @@ import (
 type Provider struct {
 	config *config.GrafanaConfig
 	client *gclient.GrafanaHTTPAPI
+	// ctx, when set, aborts the requests sent by the client once done
+	ctx context.Context
 }
 
 type ClientProvider interface {
 	Client() (*gclient.GrafanaHTTPAPI, error)
+	HTTPClient() (*http.Client, error)
 	Config() *config.GrafanaConfig
 }
 
@@ -32,6 +41,27 @@ func NewProvider(config *config.GrafanaConfig) *Provider {
 	}
 }
 
+var _ grizzly.ContextProvider = &Provider{}
+
+// WithContext returns a copy of the provider whose requests are aborted once
+// the context is done
+func (p *Provider) WithContext(ctx context.Context) grizzly.Provider {
+	return &Provider{
+		config: p.config,
+		ctx:    ctx,
+	}
+}
+
+// cachedRemote retrieves a remote resource through the cache of the run the
+// provider is bound to, if any
+func cachedRemote(provider grizzly.Provider, kind, uid string, fetch func() (*grizzly.Resource, error)) (*grizzly.Resource, error) {
+	p, ok := provider.(*Provider)
+	if !ok {
+		return fetch()
+	}
+	return grizzly.CachedRemote(p.ctx, kind, uid, fetch)
+}
+
 func (p *Provider) Validate() error {
 	if p.config.URL == "" {
 		return fmt.Errorf("grafana URL is not set")
@@ -55,16 +85,47 @@ func (p *Provider) Status() grizzly.ProviderStatus {
 		return status
 	}
 
-	if _, err = client.Dashboards.GetHomeDashboard(); err != nil {
+	details, err := checkConnection(client)
+	if err != nil {
 		status.OnlineReason = err.Error()
 		return status
 	}
 
 	status.Online = true
+	status.OnlineDetails = details
 
 	return status
 }
 
+// checkConnection exercises the URL, credentials and organization used by a
+// client, and describes who the client is authenticated as
+func checkConnection(client *gclient.GrafanaHTTPAPI) (string, error) {
+	if _, err := client.Health.GetHealth(); err != nil {
+		return "", fmt.Errorf("could not reach Grafana: %w", err)
+	}
+
+	orgOk, err := client.Org.GetCurrentOrg()
+	if err != nil {
+		var unauthorized *org.GetCurrentOrgUnauthorized
+		if errors.As(err, &unauthorized) {
+			return "", errors.New("invalid credentials")
+		}
+		var forbidden *org.GetCurrentOrgForbidden
+		if errors.As(err, &forbidden) {
+			return "", errors.New("not allowed to access the organization")
+		}
+		return "", fmt.Errorf("could not retrieve the current organization: %w", err)
+	}
+	orgName := orgOk.GetPayload().Name
+
+	// API keys aren't attached to any user
+	userOk, err := client.SignedInUser.GetSignedInUser()
+	if err != nil {
+		return fmt.Sprintf("authenticated on org %s", orgName), nil
+	}
+	return fmt.Sprintf("authenticated as %s on org %s", userOk.GetPayload().Login, orgName), nil
+}
+
 func (p *Provider) Name() string {
 	return "Grafana"
 }
@@ -92,21 +153,16 @@ func (p *Provider) Client() (*gclient.GrafanaHTTPAPI, error) {
 	transportConfig := gclient.DefaultTransportConfig().
 		WithHost(parsedURL.Host).
 		WithSchemes([]string{parsedURL.Scheme}).
-		WithBasePath(filepath.Join(parsedURL.Path, "api"))
+		// URL paths always use slashes, whatever the OS
+		WithBasePath(path.Join("/", parsedURL.Path, "api"))
+	transportConfig.OrgID = p.config.OrgID
 
-	httpClient, err := httputils.NewHTTPClient()
+	httpClient, err := p.HTTPClient()
 	if err != nil {
 		return nil, err
 	}
 	transportConfig.Client = httpClient
 
-	if parsedURL.Scheme == "https" && p.config.InsecureSkipVerify {
-		transportConfig.TLSConfig = &tls.Config{
-			InsecureSkipVerify: true,
-			ServerName:         p.config.TLSHost,
-		}
-	}
-
 	if p.config.Token != "" {
 		if p.config.User != "" {
 			transportConfig.BasicAuth = url.UserPassword(p.config.User, p.config.Token)
@@ -119,6 +175,53 @@ func (p *Provider) Client() (*gclient.GrafanaHTTPAPI, error) {
 	return grafanaClient, nil
 }
 
+// HTTPClient returns the HTTP client underlying the API client, to reach
+// Grafana endpoints the API client doesn't cover. Requests must be
+// authenticated by the caller.
+func (p *Provider) HTTPClient() (*http.Client, error) {
+	httpClient, err := httputils.NewHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	transport, err := p.transport()
+	if err != nil {
+		return nil, err
+	}
+	httpClient.Transport = &httputils.LoggedHTTPRoundTripper{DecoratedTransport: transport}
+	if p.ctx != nil {
+		httpClient.Transport = &httputils.ContextRoundTripper{Context: p.ctx, DecoratedTransport: httpClient.Transport}
+	}
+	return httpClient, nil
+}
+
+// transport returns the HTTP transport used to reach Grafana, honoring the
+// proxy environment variables and the TLS settings of the configuration
+func (p *Provider) transport() (*http.Transport, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: p.config.InsecureSkipVerify,
+		ServerName:         p.config.TLSHost,
+	}
+
+	if p.config.CAPath != "" {
+		certPool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, err
+		}
+
+		caCertPEM, err := os.ReadFile(p.config.CAPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if !certPool.AppendCertsFromPEM(caCertPEM) {
+			return nil, fmt.Errorf("could not append ca-bundle at path %s to existing certificates", p.config.CAPath)
+		}
+		tlsConfig.RootCAs = certPool
+	}
+
+	return httputils.NewTransport(tlsConfig), nil
+}
+
 func (p *Provider) Config() *config.GrafanaConfig {
 	return p.config
 }
@@ -130,16 +233,45 @@ func (p *Provider) APIVersion() string {
 
 // GetHandlers lists the resource handlers for the Grafana provider
 func (p *Provider) GetHandlers() []grizzly.Handler {
-	return []grizzly.Handler{
+	dashboardHandler := NewDashboardHandler(p)
+	if p.config != nil {
+		if len(p.config.DiffIgnore) > 0 {
+			dashboardHandler.DiffIgnore = p.config.DiffIgnore
+		}
+		dashboardHandler.SortPanels = !p.config.KeepPanelOrder
+		dashboardHandler.IgnoreVariableValues = p.config.IgnoreVariableValues
+		dashboardHandler.PreventOverwrite = p.config.PreventOverwrite
+		dashboardHandler.ReadOnly = p.config.ReadOnlyDashboards
+		dashboardHandler.KeepIDs = p.config.KeepDashboardIDs
+		dashboardHandler.Message = p.config.DashboardMessage
+		dashboardHandler.MessageVersion = p.config.DashboardMessageVersion
+	}
+
+	// resources are applied in this order: each kind comes after the kinds
+	// it may reference
+	handlers := []grizzly.Handler{
 		NewDatasourceHandler(p),
 		NewFolderHandler(p),
 		NewLibraryElementHandler(p),
-		NewDashboardHandler(p),
-		NewAlertRuleGroupHandler(p),
-		NewAlertNotificationPolicyHandler(p),
-		NewAlertContactPointHandler(p),
+		dashboardHandler,
+		NewFolderPermissionHandler(p),
+		NewDashboardPermissionHandler(p),
 		NewAlertNotificationTemplateHandler(p),
+		NewAlertContactPointHandler(p),
+		NewAlertMuteTimingHandler(p),
+		NewAlertNotificationPolicyHandler(p),
+		NewAlertRuleGroupHandler(p),
+		NewAlertRuleHandler(p),
+		NewPlaylistHandler(p),
+		NewAnnotationHandler(p),
+		// generic resources may reference any other kind
+		NewGenericAPIHandler(p),
 	}
+	// reports are only available in Grafana Enterprise
+	if p.config != nil && p.config.Reports {
+		handlers = append(handlers, NewReportHandler(p))
+	}
+	return handlers
 }
 
 func (p *Provider) SetupProxy() (*httputil.ReverseProxy, string, error) {
@@ -156,7 +288,13 @@ func (p *Provider) SetupProxy() (*httputil.ReverseProxy, string, error) {
 		return nil, "", err
 	}
 
+	transport, err := p.transport()
+	if err != nil {
+		return nil, "", err
+	}
+
 	return &httputil.ReverseProxy{
+		Transport: transport,
 		Rewrite: func(r *httputil.ProxyRequest) {
 			u.Path = "" // to ensure possible sub-paths won't be added twice.
 			r.SetURL(u)
@@ -0,0 +1,346 @@
+package grafana
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/config"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderTransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	caPath := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(caPath, caPEM, 0600))
+
+	get := func(cfg config.GrafanaConfig) error {
+		transport, err := NewProvider(&cfg).transport()
+		if err != nil {
+			return err
+		}
+		response, err := (&http.Client{Transport: transport}).Get(server.URL)
+		if err != nil {
+			return err
+		}
+		return response.Body.Close()
+	}
+
+	t.Run("unknown authorities are rejected", func(t *testing.T) {
+		require.Error(t, get(config.GrafanaConfig{URL: server.URL}))
+	})
+
+	t.Run("custom CA bundle is trusted", func(t *testing.T) {
+		require.NoError(t, get(config.GrafanaConfig{URL: server.URL, CAPath: caPath}))
+	})
+
+	t.Run("verification can be skipped", func(t *testing.T) {
+		require.NoError(t, get(config.GrafanaConfig{URL: server.URL, InsecureSkipVerify: true}))
+	})
+
+	t.Run("invalid CA bundle is reported", func(t *testing.T) {
+		invalidPath := filepath.Join(t.TempDir(), "invalid.pem")
+		require.NoError(t, os.WriteFile(invalidPath, []byte("not a certificate"), 0600))
+
+		require.ErrorContains(t, get(config.GrafanaConfig{URL: server.URL, CAPath: invalidPath}), "could not append ca-bundle")
+	})
+}
+
+func TestProviderOrgID(t *testing.T) {
+	var orgHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orgHeader = r.Header.Get("X-Grafana-Org-Id")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"database": "ok"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	for _, tc := range []struct {
+		name     string
+		orgID    int64
+		expected string
+	}{
+		{"current organization by default", 0, ""},
+		{"configured organization", 3, "3"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := require.New(t)
+
+			client, err := NewProvider(&config.GrafanaConfig{URL: server.URL, OrgID: tc.orgID}).Client()
+			req.NoError(err)
+
+			_, err = client.Health.GetHealth()
+			req.NoError(err)
+			req.Equal(tc.expected, orgHeader)
+
+			request := httptest.NewRequest(http.MethodGet, server.URL, nil)
+			authenticateRequest(&config.GrafanaConfig{OrgID: tc.orgID}, request)
+			req.Equal(tc.expected, request.Header.Get("X-Grafana-Org-Id"))
+		})
+	}
+}
+
+func TestProviderSubPath(t *testing.T) {
+	var requestedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"database": "ok"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	for _, tc := range []struct {
+		name     string
+		url      string
+		expected string
+	}{
+		{"root URL", server.URL, "/api/health"},
+		{"sub-path", server.URL + "/grafana", "/grafana/api/health"},
+		{"sub-path with trailing slash", server.URL + "/grafana/", "/grafana/api/health"},
+		{"nested sub-path", server.URL + "/tools/grafana/", "/tools/grafana/api/health"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := require.New(t)
+
+			client, err := NewProvider(&config.GrafanaConfig{URL: tc.url}).Client()
+			req.NoError(err)
+
+			_, err = client.Health.GetHealth()
+			req.NoError(err)
+			req.Equal(tc.expected, requestedPath)
+		})
+	}
+
+	t.Run("proxied requests keep the sub-path", func(t *testing.T) {
+		req := require.New(t)
+
+		proxy, subPath, err := NewProvider(&config.GrafanaConfig{URL: server.URL + "/grafana/"}).SetupProxy()
+		req.NoError(err)
+		req.Equal("/grafana/", subPath)
+
+		proxied := httptest.NewRecorder()
+		proxy.ServeHTTP(proxied, httptest.NewRequest(http.MethodGet, "/grafana/api/dashboards/uid/dash", nil))
+		req.Equal(http.StatusOK, proxied.Code)
+		req.Equal("/grafana/api/dashboards/uid/dash", requestedPath)
+	})
+}
+
+func TestProviderWithContext(t *testing.T) {
+	req := require.New(t)
+
+	received := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider := NewProvider(&config.GrafanaConfig{URL: server.URL}).WithContext(ctx)
+	client, err := provider.(ClientProvider).Client()
+	req.NoError(err)
+
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	_, err = client.Health.GetHealth()
+	req.ErrorIs(err, context.Canceled)
+}
+
+func TestRegistryWithContextKeepsHandlerSettings(t *testing.T) {
+	req := require.New(t)
+	registry := grizzly.NewRegistry([]grizzly.Provider{NewProvider(&config.GrafanaConfig{URL: "http://localhost:3000"})})
+	customized := registry.Handlers[DashboardKind].(*DashboardHandler)
+	customized.DiffIgnore = []string{"id"}
+	customized.SortPanels = false
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	bound := registry.WithContext(ctx)
+	handler, err := bound.GetHandler(DashboardKind)
+	req.NoError(err)
+	dashboardHandler := handler.(*DashboardHandler)
+
+	req.NotSame(customized, dashboardHandler)
+	req.Equal([]string{"id"}, dashboardHandler.DiffIgnore)
+	req.False(dashboardHandler.SortPanels)
+	req.Same(ctx, dashboardHandler.Provider.(*Provider).ctx, "the handler is bound to the context")
+	req.Contains(bound.HandlerOrder, handler, "the handler is bound once for all uses")
+}
+
+func TestProviderStatus(t *testing.T) {
+	newServer := func(orgStatus, userStatus int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch r.URL.Path {
+			case "/api/health":
+				_, _ = w.Write([]byte(`{"database": "ok"}`))
+			case "/api/org":
+				w.WriteHeader(orgStatus)
+				_, _ = w.Write([]byte(`{"id": 1, "name": "Main Org."}`))
+			case "/api/user":
+				w.WriteHeader(userStatus)
+				_, _ = w.Write([]byte(`{"id": 1, "login": "admin"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	t.Run("user and organization are reported", func(t *testing.T) {
+		req := require.New(t)
+		server := newServer(http.StatusOK, http.StatusOK)
+
+		status := NewProvider(&config.GrafanaConfig{URL: server.URL, User: "admin", Token: "admin"}).Status()
+		req.True(status.Online, status.OnlineReason)
+		req.Equal("authenticated as admin on org Main Org.", status.OnlineDetails)
+	})
+
+	t.Run("API keys aren't attached to a user", func(t *testing.T) {
+		req := require.New(t)
+		server := newServer(http.StatusOK, http.StatusNotFound)
+
+		status := NewProvider(&config.GrafanaConfig{URL: server.URL, Token: "key"}).Status()
+		req.True(status.Online, status.OnlineReason)
+		req.Equal("authenticated on org Main Org.", status.OnlineDetails)
+	})
+
+	t.Run("invalid credentials are reported", func(t *testing.T) {
+		req := require.New(t)
+		server := newServer(http.StatusUnauthorized, http.StatusUnauthorized)
+
+		status := NewProvider(&config.GrafanaConfig{URL: server.URL, Token: "invalid"}).Status()
+		req.False(status.Online)
+		req.Equal("invalid credentials", status.OnlineReason)
+	})
+
+	t.Run("unreachable instances are reported", func(t *testing.T) {
+		req := require.New(t)
+		server := newServer(http.StatusOK, http.StatusOK)
+		server.Close()
+
+		status := NewProvider(&config.GrafanaConfig{URL: server.URL}).Status()
+		req.False(status.Online)
+		req.Contains(status.OnlineReason, "could not reach Grafana")
+	})
+}
+
+func TestProviderHandlerOrder(t *testing.T) {
+	kinds := []string{}
+	for _, handler := range NewProvider(&config.GrafanaConfig{}).GetHandlers() {
+		kinds = append(kinds, handler.Kind())
+	}
+	position := func(kind string) int {
+		for i, k := range kinds {
+			if k == kind {
+				return i
+			}
+		}
+		t.Fatalf("no handler for %s", kind)
+		return -1
+	}
+
+	// each kind must be applied after the ones it references
+	dependencies := [][2]string{
+		{DashboardFolderKind, DashboardKind},
+		{DatasourceKind, DashboardKind},
+		{LibraryElementKind, DashboardKind},
+		{DashboardKind, DashboardPermissionKind},
+		{DashboardFolderKind, FolderPermissionKind},
+		{KindAlertNotificationTemplate, AlertContactPointKind},
+		{AlertContactPointKind, AlertNotificationPolicyKind},
+		{AlertMuteTimingKind, AlertNotificationPolicyKind},
+		{AlertContactPointKind, AlertRuleGroupKind},
+		{DashboardFolderKind, AlertRuleGroupKind},
+		{AlertContactPointKind, AlertRuleKind},
+		{DashboardKind, PlaylistKind},
+		{DashboardKind, AnnotationKind},
+	}
+	for _, dependency := range dependencies {
+		require.Less(t, position(dependency[0]), position(dependency[1]), "%s must be applied before %s", dependency[0], dependency[1])
+	}
+}
+
+func TestProviderDashboardSettings(t *testing.T) {
+	t.Cleanup(viper.Reset)
+
+	// diffDashboard compares dashboards with the handler set up from a
+	// configuration file holding the given Grafana settings
+	diffDashboard := func(t *testing.T, settings string, local, remote map[string]any) grizzly.ResourceDiff {
+		t.Helper()
+		req := require.New(t)
+		viper.Reset()
+
+		path := filepath.Join(t.TempDir(), "grizzly.yaml")
+		req.NoError(os.WriteFile(path, []byte(`apiVersion: v1alpha1
+current-context: default
+contexts:
+  default:
+    grafana:
+      url: http://localhost:3000
+`+settings), 0600))
+		t.Setenv(config.ConfigFileEnv, path)
+		t.Setenv("GRAFANA_URL", "")
+		config.Initialise()
+		req.NoError(config.Read())
+		currentContext, err := config.CurrentContext()
+		req.NoError(err)
+
+		newDashboard := func(spec map[string]any) grizzly.Resource {
+			spec["uid"] = "dash"
+			resource, err := grizzly.NewResource("grizzly.grafana.com/v1alpha1", DashboardKind, "dash", spec)
+			req.NoError(err)
+			resource.SetMetadata("folder", generalFolderUID)
+			return resource
+		}
+		registry := grizzly.NewRegistry([]grizzly.Provider{NewProvider(&currentContext.Grafana)}).
+			WithOfflineRemote(grizzly.NewResources(newDashboard(remote)))
+
+		diffs, err := grizzly.DiffResources(context.Background(), registry, grizzly.NewResources(newDashboard(local)), grizzly.DiffOptions{})
+		req.NoError(err)
+		req.Len(diffs, 1)
+		return diffs[0]
+	}
+
+	t.Run("ignored fields are read from the configuration", func(t *testing.T) {
+		local := map[string]any{"title": "dash", "version": 3, "time": map[string]any{"from": "now-1h"}}
+		remote := map[string]any{"title": "dash", "version": 7, "time": map[string]any{"from": "now-6h"}}
+
+		diff := diffDashboard(t, "", local, remote)
+		require.Equal(t, grizzly.DiffStatusChanged, diff.Status)
+		require.NotContains(t, diff.Patch, "version", "the default fields are ignored")
+
+		diff = diffDashboard(t, "      diff-ignore: [version, time.from]\n", local, remote)
+		require.Equal(t, grizzly.DiffStatusUnchanged, diff.Status)
+	})
+
+	t.Run("panels can be compared in their order", func(t *testing.T) {
+		local := map[string]any{"panels": []any{
+			map[string]any{"id": 2, "title": "second"},
+			map[string]any{"id": 1, "title": "first"},
+		}}
+		remote := map[string]any{"panels": []any{
+			map[string]any{"id": 1, "title": "first"},
+			map[string]any{"id": 2, "title": "second"},
+		}}
+
+		diff := diffDashboard(t, "", local, remote)
+		require.Equal(t, grizzly.DiffStatusUnchanged, diff.Status, "panels are sorted by default")
+
+		diff = diffDashboard(t, "      keep-panel-order: true\n", local, remote)
+		require.Equal(t, grizzly.DiffStatusChanged, diff.Status)
+	})
+}
@@ -0,0 +1,80 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+)
+
+// getRaw sends a GET request to a path of Grafana, made of elems, and
+// returns the response along with its body as is. It allows reaching
+// endpoints outside of the API, or reading responses the API client would
+// decode.
+func getRaw(provider ClientProvider, query url.Values, elems ...string) (*http.Response, []byte, error) {
+	httpClient, err := provider.HTTPClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := url.Parse(provider.Config().URL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid Grafana URL")
+	}
+	// URL paths always use slashes, whatever the OS
+	u.Path = path.Join(append([]string{"/", u.Path}, elems...)...)
+	u.RawQuery = query.Encode()
+
+	request, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	authenticateRequest(provider.Config(), request)
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, content, nil
+}
+
+// getRawDashboard returns a dashboard exactly as Grafana stores it: the
+// bytes of its JSON model, as returned by the API, without being decoded and
+// encoded again, which could reorder or drop fields
+func getRawDashboard(provider ClientProvider, uid string) ([]byte, error) {
+	response, content, err := getRaw(provider, nil, "api", "dashboards", "uid", uid)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("couldn't fetch dashboard '%s' from remote: %w", uid, grizzly.ErrNotFound)
+	}
+	if response.StatusCode >= http.StatusMultipleChoices {
+		return nil, APIError{
+			StatusCode: response.StatusCode,
+			Err:        fmt.Errorf("fetching dashboard %s failed with status %d: %s", uid, response.StatusCode, strings.TrimSpace(string(content))),
+		}
+	}
+
+	// the model is kept as raw bytes, only the envelope around it is decoded
+	var body struct {
+		Dashboard json.RawMessage `json:"dashboard"`
+	}
+	if err := json.Unmarshal(content, &body); err != nil {
+		return nil, fmt.Errorf("fetching dashboard %s returned an invalid body: %w", uid, err)
+	}
+	if len(body.Dashboard) == 0 {
+		return nil, fmt.Errorf("fetching dashboard %s returned no dashboard", uid)
+	}
+	return body.Dashboard, nil
+}
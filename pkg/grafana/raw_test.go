@@ -0,0 +1,36 @@
+package grafana
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/config"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardHandler_GetRemoteRaw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/dashboards/uid/abc" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Dashboard not found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"meta":{"folderUid":"general"},"dashboard":{"uid":"abc","title":"ABC","refresh":"","panels":[{"z":1,"a":2.50}]}}`))
+	}))
+	t.Cleanup(server.Close)
+	handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+
+	t.Run("dashboards are returned as Grafana stores them", func(t *testing.T) {
+		content, err := handler.GetRemoteRaw("abc")
+		require.NoError(t, err)
+		require.Equal(t, `{"uid":"abc","title":"ABC","refresh":"","panels":[{"z":1,"a":2.50}]}`, string(content))
+	})
+
+	t.Run("missing dashboards aren't found", func(t *testing.T) {
+		_, err := handler.GetRemoteRaw("missing")
+		require.ErrorIs(t, err, grizzly.ErrNotFound)
+	})
+}
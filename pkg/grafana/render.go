@@ -0,0 +1,59 @@
+package grafana
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+)
+
+// ErrRenderingNotSupported signals a Grafana instance unable to render
+// images, which requires the image renderer plugin or service
+var ErrRenderingNotSupported = fmt.Errorf("rendering requires the Grafana image renderer: %w", grizzly.ErrNotImplemented)
+
+// renderDashboard renders a dashboard, or one of its panels, as a PNG image
+// through the rendering endpoint of Grafana, which lives outside of the API
+func renderDashboard(provider ClientProvider, uid string, opts grizzly.RenderOptions) ([]byte, error) {
+	route := "d"
+	query := url.Values{}
+	if opts.PanelID != 0 {
+		route = "d-solo"
+		query.Set("panelId", strconv.Itoa(opts.PanelID))
+	}
+	setQueryParam(query, "from", opts.From)
+	setQueryParam(query, "to", opts.To)
+	setQueryParam(query, "theme", opts.Theme)
+	if opts.Width > 0 {
+		query.Set("width", strconv.Itoa(opts.Width))
+	}
+	if opts.Height > 0 {
+		query.Set("height", strconv.Itoa(opts.Height))
+	}
+
+	response, content, err := getRaw(provider, query, "render", route, uid)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode >= http.StatusMultipleChoices {
+		// Grafana answers with an error page rather than an image when no
+		// renderer is available
+		if response.StatusCode == http.StatusNotFound || strings.Contains(strings.ToLower(string(content)), "renderer") {
+			return nil, ErrRenderingNotSupported
+		}
+		return nil, fmt.Errorf("rendering dashboard %s failed with status %d: %s", uid, response.StatusCode, strings.TrimSpace(string(content)))
+	}
+	if contentType := response.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/png") {
+		return nil, fmt.Errorf("rendering dashboard %s returned %s content, not a PNG image", uid, contentType)
+	}
+	return content, nil
+}
+
+// setQueryParam sets a query parameter, unless its value is empty
+func setQueryParam(query url.Values, key, value string) {
+	if value != "" {
+		query.Set(key, value)
+	}
+}
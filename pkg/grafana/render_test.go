@@ -0,0 +1,56 @@
+package grafana
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/config"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardHandler_Render(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+
+	t.Run("dashboards are rendered by the rendering endpoint", func(t *testing.T) {
+		req := require.New(t)
+		var path, query, authorization string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, query, authorization = r.URL.Path, r.URL.RawQuery, r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(png)
+		}))
+		t.Cleanup(server.Close)
+		handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL + "/grafana", Token: "secret"}))
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "abc", map[string]any{"uid": "abc"})
+		req.NoError(err)
+
+		image, err := handler.Render(resource, grizzly.RenderOptions{From: "now-1h", To: "now", Theme: "dark", Width: 800, Height: 400})
+		req.NoError(err)
+		req.Equal(png, image)
+		req.Equal("/grafana/render/d/abc", path)
+		req.Equal("from=now-1h&height=400&theme=dark&to=now&width=800", query)
+		req.Equal("Bearer secret", authorization)
+
+		_, err = handler.Render(resource, grizzly.RenderOptions{PanelID: 2})
+		req.NoError(err)
+		req.Equal("/grafana/render/d-solo/abc", path)
+		req.Equal("panelId=2", query)
+	})
+
+	t.Run("rendering isn't supported without an image renderer", func(t *testing.T) {
+		req := require.New(t)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Rendering failed: no image renderer available/installed", http.StatusInternalServerError)
+		}))
+		t.Cleanup(server.Close)
+		handler := NewDashboardHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "abc", map[string]any{"uid": "abc"})
+		req.NoError(err)
+
+		_, err = handler.Render(resource, grizzly.RenderOptions{})
+		req.ErrorIs(err, ErrRenderingNotSupported)
+		req.ErrorIs(err, grizzly.ErrNotImplemented)
+	})
+}
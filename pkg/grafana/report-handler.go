@@ -0,0 +1,221 @@
+package grafana
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	log "github.com/sirupsen/logrus"
+)
+
+const ReportKind = "Report"
+
+const reportPattern = "reports/report-%s.%s"
+
+// ErrReportsNotSupported signals a Grafana instance without the reporting
+// API, which is only available in Grafana Enterprise
+var ErrReportsNotSupported = fmt.Errorf("reports require Grafana Enterprise: %w", grizzly.ErrNotImplemented)
+
+var _ grizzly.Handler = &ReportHandler{}
+
+// ReportHandler is a Grizzly Handler for Grafana Enterprise reports
+type ReportHandler struct {
+	grizzly.BaseHandler
+}
+
+// NewReportHandler returns a new Grizzly Handler for Grafana Enterprise reports
+func NewReportHandler(provider grizzly.Provider) *ReportHandler {
+	return &ReportHandler{
+		BaseHandler: grizzly.NewBaseHandler(provider, ReportKind, false),
+	}
+}
+
+// ResourceFilePath returns the location on disk where a resource should be updated
+func (h *ReportHandler) ResourceFilePath(resource grizzly.Resource, filetype string) string {
+	filename := strings.ReplaceAll(resource.Name(), string(os.PathSeparator), "-")
+	return fmt.Sprintf(reportPattern, filename, filetype)
+}
+
+// Prepare gets a resource ready for dispatch to the remote endpoint
+func (h *ReportHandler) Prepare(existing *grizzly.Resource, resource grizzly.Resource) *grizzly.Resource {
+	if !resource.HasSpecString("name") {
+		resource.SetSpecString("name", resource.Name())
+	}
+	return &resource
+}
+
+// Validate checks that the name of a report matches the name of its resource
+func (h *ReportHandler) Validate(resource grizzly.Resource) error {
+	name, exist := resource.GetSpecString("name")
+	if exist && name != resource.Name() {
+		return fmt.Errorf("spec.name '%s' and metadata.name '%s', don't match", name, resource.Name())
+	}
+	return nil
+}
+
+// GetSpecUID returns the UID of a report, which is its name. Grafana assigns
+// IDs to reports when they are created, so reports are found back by name.
+func (h *ReportHandler) GetSpecUID(resource grizzly.Resource) (string, error) {
+	name, ok := resource.GetSpecString("name")
+	if !ok {
+		return "", fmt.Errorf("name not specified")
+	}
+	return name, nil
+}
+
+// GetByUID retrieves JSON for a resource from an endpoint, by UID
+func (h *ReportHandler) GetByUID(uid string) (*grizzly.Resource, error) {
+	remote, err := h.findReport(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	// only keep the fields that can be set, leaving out IDs and timestamps
+	data, err := json.Marshal(remote)
+	if err != nil {
+		return nil, err
+	}
+	var report models.CreateOrUpdateReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+
+	spec, err := structToMap(report)
+	if err != nil {
+		return nil, err
+	}
+
+	resource, err := grizzly.NewResource(h.APIVersion(), h.Kind(), uid, spec)
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+// GetRemote retrieves a report as a Resource
+func (h *ReportHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
+	return h.GetByUID(resource.Name())
+}
+
+// ListRemote retrieves a list of sorted UIDs of all remote reports. Grafana
+// instances without the reporting API have none.
+func (h *ReportHandler) ListRemote() ([]string, error) {
+	reports, err := h.listReports()
+	if errors.Is(err, ErrReportsNotSupported) {
+		log.Warnf("Skipping reports: %s", err)
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	uids := make([]string, 0, len(reports))
+	for _, report := range reports {
+		uids = append(uids, report.Name)
+	}
+	sort.Strings(uids)
+	return uids, nil
+}
+
+// Add pushes a report to Grafana via the API
+func (h *ReportHandler) Add(resource grizzly.Resource) error {
+	report, err := unmarshalReport(resource)
+	if err != nil {
+		return err
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Reports.CreateReport(report)
+	return wrapReportAPIError(err)
+}
+
+// Update pushes a report to Grafana via the API
+func (h *ReportHandler) Update(existing, resource grizzly.Resource) error {
+	report, err := unmarshalReport(resource)
+	if err != nil {
+		return err
+	}
+
+	remote, err := h.findReport(resource.Name())
+	if err != nil {
+		return err
+	}
+
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Reports.UpdateReport(remote.ID, report)
+	return wrapReportAPIError(err)
+}
+
+// findReport retrieves the report with the given name
+func (h *ReportHandler) findReport(name string) (*models.Report, error) {
+	reports, err := h.listReports()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*models.Report
+	for _, report := range reports {
+		if report.Name == name {
+			found = append(found, report)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, grizzly.ErrNotFound
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("%d reports are named '%s', expected at most one", len(found), name)
+	}
+}
+
+func (h *ReportHandler) listReports() ([]*models.Report, error) {
+	client, err := h.Provider.(ClientProvider).Client()
+	if err != nil {
+		return nil, err
+	}
+
+	reportsOk, err := client.Reports.GetReports()
+	if err != nil {
+		return nil, wrapReportAPIError(err)
+	}
+	return reportsOk.GetPayload(), nil
+}
+
+// wrapReportAPIError is wrapAPIError for the reporting API, which Grafana
+// OSS doesn't serve: its routes are reported as ErrReportsNotSupported.
+func wrapReportAPIError(err error) error {
+	err = wrapAPIError(err)
+	if errors.Is(err, grizzly.ErrNotFound) {
+		return ErrReportsNotSupported
+	}
+	return err
+}
+
+func unmarshalReport(resource grizzly.Resource) (*models.CreateOrUpdateReport, error) {
+	data, err := json.Marshal(resource.Spec())
+	if err != nil {
+		return nil, err
+	}
+	var report models.CreateOrUpdateReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, err
+	}
+	if report.Name == "" {
+		report.Name = resource.Name()
+	}
+	return &report, nil
+}
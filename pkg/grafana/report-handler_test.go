@@ -0,0 +1,98 @@
+package grafana
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/config"
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportHandler(t *testing.T) {
+	var updated map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/reports":
+			_, _ = w.Write([]byte(`[{"id": 7, "uid": "abc", "name": "weekly", "recipients": "team@example.com", "created": "2024-01-01T00:00:00Z"}]`))
+		case r.Method == http.MethodPut && r.URL.Path == "/api/reports/7":
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			_, _ = w.Write([]byte(`{"message": "Report updated"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "Not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	handler := NewReportHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+
+	t.Run("reports are found by name", func(t *testing.T) {
+		req := require.New(t)
+
+		uids, err := handler.ListRemote()
+		req.NoError(err)
+		req.Equal([]string{"weekly"}, uids)
+
+		remote, err := handler.GetByUID("weekly")
+		req.NoError(err)
+		req.Equal("team@example.com", remote.Spec()["recipients"])
+		req.NotContains(remote.Spec(), "id", "server-assigned fields are left out")
+
+		_, err = handler.GetByUID("monthly")
+		req.ErrorIs(err, grizzly.ErrNotFound)
+	})
+
+	t.Run("reports are updated by ID", func(t *testing.T) {
+		req := require.New(t)
+
+		resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "weekly", map[string]any{"recipients": "sre@example.com"})
+		req.NoError(err)
+		req.NoError(handler.Validate(resource))
+		req.NoError(handler.Update(resource, *handler.Prepare(nil, resource)))
+		req.Equal("weekly", updated["name"])
+		req.Equal("sre@example.com", updated["recipients"])
+	})
+}
+
+func TestReportHandler_NotSupported(t *testing.T) {
+	req := require.New(t)
+	// Grafana OSS doesn't serve the reporting API
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not found"}`))
+	}))
+	t.Cleanup(server.Close)
+	handler := NewReportHandler(NewProvider(&config.GrafanaConfig{URL: server.URL}))
+
+	uids, err := handler.ListRemote()
+	req.NoError(err, "reports are skipped when listing")
+	req.Empty(uids)
+
+	_, err = handler.GetByUID("weekly")
+	req.ErrorIs(err, ErrReportsNotSupported)
+
+	resource, err := grizzly.NewResource(handler.APIVersion(), handler.Kind(), "weekly", map[string]any{"name": "weekly"})
+	req.NoError(err)
+	req.ErrorIs(handler.Add(resource), grizzly.ErrNotImplemented)
+}
+
+func TestProviderReportHandler(t *testing.T) {
+	hasReports := func(provider *Provider) bool {
+		for _, handler := range provider.GetHandlers() {
+			if handler.Kind() == ReportKind {
+				return true
+			}
+		}
+		return false
+	}
+
+	require.False(t, hasReports(NewProvider(&config.GrafanaConfig{})), "reports are disabled by default")
+	require.True(t, hasReports(NewProvider(&config.GrafanaConfig{Reports: true})))
+}
@@ -0,0 +1,59 @@
+package grafana
+
+import (
+	"errors"
+	"time"
+
+	gclient "github.com/grafana/grafana-openapi-client-go/client"
+	"github.com/grafana/grafana-openapi-client-go/client/snapshots"
+	"github.com/grafana/grafana-openapi-client-go/models"
+	"github.com/grafana/grizzly/pkg/grizzly"
+)
+
+// postSnapshot uploads a dashboard as a snapshot
+func postSnapshot(client *gclient.GrafanaHTTPAPI, dashboard map[string]any, opts grizzly.SnapshotOptions) (*models.CreateDashboardSnapshotOKBody, error) {
+	body := models.CreateDashboardSnapshotCommand{
+		Dashboard: dashboard,
+		Name:      opts.Name,
+	}
+	if opts.ExpiresSeconds > 0 {
+		body.Expires = int64(opts.ExpiresSeconds)
+	}
+
+	response, err := client.Snapshots.CreateDashboardSnapshot(&body, nil)
+	if err != nil {
+		return nil, err
+	}
+	return response.GetPayload(), nil
+}
+
+// listSnapshots retrieves the snapshots stored by Grafana
+func listSnapshots(client *gclient.GrafanaHTTPAPI) ([]grizzly.SnapshotInfo, error) {
+	snapshotsOk, err := client.Snapshots.SearchDashboardSnapshots(snapshots.NewSearchDashboardSnapshotsParams())
+	if err != nil {
+		return nil, err
+	}
+
+	infos := []grizzly.SnapshotInfo{}
+	for _, snapshot := range snapshotsOk.GetPayload() {
+		infos = append(infos, grizzly.SnapshotInfo{
+			Key:     snapshot.Key,
+			Name:    snapshot.Name,
+			Expires: time.Time(snapshot.Expires).Format(time.RFC3339),
+		})
+	}
+	return infos, nil
+}
+
+// deleteSnapshot deletes a snapshot, by key
+func deleteSnapshot(client *gclient.GrafanaHTTPAPI, key string) error {
+	_, err := client.Snapshots.DeleteDashboardSnapshot(key)
+	if err != nil {
+		var gErr *snapshots.DeleteDashboardSnapshotNotFound
+		if errors.As(err, &gErr) {
+			return grizzly.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
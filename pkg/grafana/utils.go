@@ -6,8 +6,12 @@ import (
 	"io"
 	"net/http"
 	"regexp"
+	"strconv"
 
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
 	gclient "github.com/grafana/grafana-openapi-client-go/client"
+	"github.com/grafana/grafana-openapi-client-go/client/search"
 	"github.com/grafana/grafana-openapi-client-go/models"
 	"github.com/grafana/grizzly/internal/httputils"
 	"github.com/grafana/grizzly/pkg/config"
@@ -19,6 +23,55 @@ var (
 	folderURLRegex = regexp.MustCompile("/dashboards/f/([^/]+)")
 )
 
+// streamJSON encodes a value as JSON as it is read, rather than holding the
+// whole document in memory, which matters for large dashboards
+func streamJSON(v any) io.ReadCloser {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(json.NewEncoder(writer).Encode(v))
+	}()
+	return reader
+}
+
+// searchPageSize is the number of results requested for each page of a search
+var searchPageSize = int64(1000)
+
+// searchAll runs a search, following result pages until all hits are retrieved
+func searchAll(client *gclient.GrafanaHTTPAPI, params *search.SearchParams, opts ...search.ClientOption) ([]*models.Hit, error) {
+	var hits []*models.Hit
+
+	limit := searchPageSize
+	params.SetLimit(&limit)
+	for page := int64(1); ; page++ {
+		params.SetPage(&page)
+
+		searchOk, err := client.Search.Search(params, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		hits = append(hits, searchOk.GetPayload()...)
+		if int64(len(searchOk.GetPayload())) < limit {
+			return hits, nil
+		}
+	}
+}
+
+// withDashboardUIDs restricts a search to some dashboards. Each UID is sent as
+// its own query parameter, the way Grafana reads them, where the client would
+// send a comma-separated list.
+func withDashboardUIDs(uids []string) search.ClientOption {
+	return func(op *runtime.ClientOperation) {
+		params := op.Params
+		op.Params = runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, registry strfmt.Registry) error {
+			if err := params.WriteToRequest(r, registry); err != nil {
+				return err
+			}
+			return r.SetQueryParam("dashboardUIDs", uids...)
+		})
+	}
+}
+
 func extractFolderUID(client *gclient.GrafanaHTTPAPI, d models.DashboardFullWithMeta) string {
 	folderUID := d.Meta.FolderUID
 	if folderUID == "" {
@@ -107,4 +160,7 @@ func authenticateRequest(config *config.GrafanaConfig, request *http.Request) {
 	} else if config.Token != "" {
 		request.Header.Set("Authorization", "Bearer "+config.Token)
 	}
+	if config.OrgID != 0 {
+		request.Header.Set(gclient.OrgIDHeader, strconv.FormatInt(config.OrgID, 10))
+	}
 }
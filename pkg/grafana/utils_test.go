@@ -1,9 +1,15 @@
 package grafana
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"testing"
 
 	gclient "github.com/grafana/grafana-openapi-client-go/client"
+	"github.com/grafana/grafana-openapi-client-go/client/search"
 	"github.com/grafana/grafana-openapi-client-go/models"
 	"github.com/grafana/grizzly/pkg/config"
 	"github.com/stretchr/testify/require"
@@ -53,3 +59,35 @@ func TestExtractFolderUID(t *testing.T) {
 		require.Equal(t, "12345", uid)
 	})
 }
+
+func TestSearchAll(t *testing.T) {
+	req := require.New(t)
+
+	pageSize := searchPageSize
+	searchPageSize = 2
+	t.Cleanup(func() { searchPageSize = pageSize })
+
+	// 5 hits, served 2 by 2
+	var requestedPages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		requestedPages = append(requestedPages, r.URL.Query().Get("page"))
+
+		hits := []map[string]string{}
+		for i := (page - 1) * 2; i < page*2 && i < 5; i++ {
+			hits = append(hits, map[string]string{"uid": fmt.Sprintf("uid-%d", i)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(hits)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewProvider(&config.GrafanaConfig{URL: server.URL}).Client()
+	req.NoError(err)
+
+	hits, err := searchAll(client, search.NewSearchParams())
+	req.NoError(err)
+	req.Len(hits, 5)
+	req.Equal("uid-4", hits[4].UID)
+	req.Equal([]string{"1", "2", "3"}, requestedPages)
+}
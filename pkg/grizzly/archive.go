@@ -0,0 +1,153 @@
+package grizzly
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// readerParser describes a FormatParser able to parse resources from a
+// reader, such as an entry of an archive, rather than from a file. The
+// format of the source is set by the parser.
+type readerParser interface {
+	parseReader(r io.Reader, source Source, options ParserOptions) (Resources, error)
+}
+
+// isArchive checks whether a file is an archive resources can be read from:
+// a gzipped tarball (.tar.gz, .tgz) or a zip archive
+func isArchive(file string) bool {
+	name := strings.ToLower(file)
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz") || strings.HasSuffix(name, ".zip")
+}
+
+// parseArchive parses the resources held by the entries of an archive,
+// without extracting it. Each entry is parsed according to its extension,
+// as the files of a directory would be. Jsonnet entries aren't supported,
+// as the files they import would be out of reach.
+func (parser *ChainParser) parseArchive(archive string, options ParserOptions) (Resources, error) {
+	resources := NewResources()
+	var finalErr error
+
+	parseEntry := func(name string, r io.Reader) error {
+		path := filepath.Join(archive, name)
+		parsed, err := parser.parseArchiveEntry(path, r, options)
+		if err != nil {
+			if !parser.continueOnError {
+				return err
+			}
+			finalErr = multierror.Append(finalErr, err)
+			return nil
+		}
+		if err := mergeUnique(resources, parsed); err != nil {
+			finalErr = multierror.Append(finalErr, err)
+		}
+		return nil
+	}
+
+	var err error
+	if strings.HasSuffix(strings.ToLower(archive), ".zip") {
+		err = walkZip(archive, parseEntry)
+	} else {
+		err = walkTarball(archive, parseEntry)
+	}
+	if err != nil {
+		return resources, multierror.Append(finalErr, err)
+	}
+
+	return resources, finalErr
+}
+
+func (parser *ChainParser) parseArchiveEntry(path string, r io.Reader, options ParserOptions) (Resources, error) {
+	for _, formatParser := range parser.formatParsers {
+		if !formatParser.Accept(path) {
+			continue
+		}
+
+		if forced, ok := formatParser.(acceptAllParser); ok {
+			formatParser = forced.FormatParser
+		}
+		entryParser, ok := formatParser.(readerParser)
+		if !ok {
+			return Resources{}, ParseError{File: path, Err: fmt.Errorf("this format can't be read from archives, only JSON and YAML files can")}
+		}
+
+		// entries can't be written back to the archive
+		source := Source{Path: path, Rewritable: false}
+		resources, err := entryParser.parseReader(r, source, options)
+		if err != nil {
+			return Resources{}, ParseError{File: path, Err: err}
+		}
+		return resources, nil
+	}
+
+	return Resources{}, NewWarning(NewUnrecognisedFormatError(path))
+}
+
+// walkTarball calls fn with each regular file of a gzipped tarball, in the
+// order they are stored
+func walkTarball(archive string, fn func(name string, r io.Reader) error) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", archive, err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", archive, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(header.Name, tarReader); err != nil {
+			return err
+		}
+	}
+}
+
+// walkZip calls fn with each file of a zip archive, in the order they are
+// stored
+func walkZip(archive string, fn func(name string, r io.Reader) error) error {
+	zipReader, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", archive, err)
+	}
+	defer zipReader.Close()
+
+	for _, entry := range zipReader.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		err := func() error {
+			r, err := entry.Open()
+			if err != nil {
+				return fmt.Errorf("reading %s: %w", archive, err)
+			}
+			defer r.Close()
+			return fn(entry.Name, r)
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package grizzly
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RemoteCache holds the remote resources retrieved during a single run, so
+// that a resource looked up several times is only fetched once. A nil cache
+// is valid, and always retrieves resources.
+type RemoteCache struct {
+	mu        sync.Mutex
+	resources map[ResourceRef]Resource
+	// missing holds the resources known not to exist remotely
+	missing map[ResourceRef]bool
+}
+
+// NewRemoteCache returns an empty cache of remote resources
+func NewRemoteCache() *RemoteCache {
+	return &RemoteCache{
+		resources: map[ResourceRef]Resource{},
+		missing:   map[ResourceRef]bool{},
+	}
+}
+
+// Get returns the remote resource identified by a kind and a UID. When the
+// resource was already retrieved, a copy of it is returned. Otherwise, it is
+// retrieved using fetch.
+func (c *RemoteCache) Get(kind, uid string, fetch func() (*Resource, error)) (*Resource, error) {
+	if c == nil {
+		return fetch()
+	}
+	ref := NewResourceRef(kind, uid)
+
+	c.mu.Lock()
+	cached, found := c.resources[ref]
+	missing := c.missing[ref]
+	c.mu.Unlock()
+
+	if missing {
+		return nil, ErrNotFound
+	}
+	if found {
+		clone := cached.Clone()
+		return &clone, nil
+	}
+
+	resource, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.resources[ref] = resource.Clone()
+	c.mu.Unlock()
+
+	return resource, nil
+}
+
+// Invalidate forgets a remote resource, to be called once it has been added
+// or updated.
+func (c *RemoteCache) Invalidate(kind, uid string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ref := NewResourceRef(kind, uid)
+	delete(c.resources, ref)
+	delete(c.missing, ref)
+}
+
+// add records remote resources, as retrieved in a batch
+func (c *RemoteCache) add(resources []Resource) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, resource := range resources {
+		c.resources[resource.Ref()] = resource.Clone()
+	}
+}
+
+// markMissing records resources as not existing remotely
+func (c *RemoteCache) markMissing(kind string, uids []string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, uid := range uids {
+		c.missing[NewResourceRef(kind, uid)] = true
+	}
+}
+
+type remoteCacheKey struct{}
+
+// CachedRemote returns a remote resource through the cache of the run the
+// context belongs to (see Registry.WithRemoteCache), or using fetch when the
+// run doesn't cache remote resources. It lets handlers share the cache of
+// the run when looking up the resources they depend on.
+func CachedRemote(ctx context.Context, kind, uid string, fetch func() (*Resource, error)) (*Resource, error) {
+	return remoteCacheFrom(ctx).Get(kind, uid, fetch)
+}
+
+func remoteCacheFrom(ctx context.Context) *RemoteCache {
+	if ctx == nil {
+		return nil
+	}
+	cache, _ := ctx.Value(remoteCacheKey{}).(*RemoteCache)
+	return cache
+}
+
+// prefetchRemotes fills the cache with the remote equivalents of the
+// resources of a run, for the handlers able to retrieve them in batches, so
+// that they aren't requested one by one. Resources missing remotely are
+// recorded too. It is only done when the registry caches remote resources.
+// Failed lookups are only logged, as resources are then retrieved
+// individually.
+func (r Registry) prefetchRemotes(resources Resources) {
+	if r.cache == nil {
+		return
+	}
+	if _, ok := r.offlineResources(); ok {
+		return
+	}
+
+	kinds := []string{}
+	byKind := map[string][]Resource{}
+	for _, resource := range resources.AsList() {
+		if _, ok := byKind[resource.Kind()]; !ok {
+			kinds = append(kinds, resource.Kind())
+		}
+		byKind[resource.Kind()] = append(byKind[resource.Kind()], resource)
+	}
+
+	for _, kind := range kinds {
+		handler, err := r.GetHandler(kind)
+		if err != nil {
+			continue
+		}
+		batchHandler, ok := handler.(BatchRemoteHandler)
+		if !ok {
+			continue
+		}
+		existing, missing, err := batchHandler.GetRemotes(byKind[kind])
+		if err != nil {
+			log.Warnf("Could not look up %s resources in a batch, retrieving them one by one: %v", kind, err)
+			continue
+		}
+		r.cache.add(existing)
+		r.cache.markMissing(kind, missing)
+	}
+}
+
+// getRemote retrieves the remote equivalent of a resource, through the cache,
+// or from the offline remote when in use
+func (r Registry) getRemote(handler Handler, resource Resource) (*Resource, error) {
+	if resources, ok := r.offlineResources(); ok {
+		return getOffline(resources, handler.Kind(), resource.Name())
+	}
+	return r.cache.Get(handler.Kind(), resource.Name(), func() (*Resource, error) {
+		return handler.GetRemote(resource)
+	})
+}
+
+// getByUID retrieves a remote resource by UID, through the cache, or from the
+// offline remote when in use
+func (r Registry) getByUID(handler Handler, uid string) (*Resource, error) {
+	if resources, ok := r.offlineResources(); ok {
+		return getOffline(resources, handler.Kind(), uid)
+	}
+	return r.cache.Get(handler.Kind(), uid, func() (*Resource, error) {
+		return handler.GetByUID(uid)
+	})
+}
@@ -0,0 +1,112 @@
+package grizzly
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteCache(t *testing.T) {
+	fetches := 0
+	fetch := func() (*Resource, error) {
+		fetches++
+		resource, _ := NewResource("v1", "Kind", "uid", map[string]any{"title": "remote"})
+		return &resource, nil
+	}
+
+	t.Run("nil cache always fetches", func(t *testing.T) {
+		req := require.New(t)
+		fetches = 0
+
+		var cache *RemoteCache
+		_, _ = cache.Get("Kind", "uid", fetch)
+		_, _ = cache.Get("Kind", "uid", fetch)
+
+		req.Equal(2, fetches)
+	})
+
+	t.Run("cache fetches once, until invalidated", func(t *testing.T) {
+		req := require.New(t)
+		cache := NewRemoteCache()
+		fetches = 0
+
+		first, err := cache.Get("Kind", "uid", fetch)
+		req.NoError(err)
+		first.SetSpecString("title", "modified")
+
+		second, err := cache.Get("Kind", "uid", fetch)
+		req.NoError(err)
+		req.Equal(1, fetches)
+		req.Equal("remote", second.GetSpecValue("title"), "cached resources must not be altered by callers")
+
+		cache.Invalidate("Kind", "uid")
+		_, _ = cache.Get("Kind", "uid", fetch)
+		req.Equal(2, fetches)
+	})
+
+	t.Run("resources known to be missing aren't fetched, until invalidated", func(t *testing.T) {
+		req := require.New(t)
+		cache := NewRemoteCache()
+		fetches = 0
+
+		cache.markMissing("Kind", []string{"missing"})
+		_, err := cache.Get("Kind", "missing", fetch)
+		req.ErrorIs(err, ErrNotFound)
+		req.Equal(0, fetches)
+
+		cache.Invalidate("Kind", "missing")
+		_, err = cache.Get("Kind", "missing", fetch)
+		req.NoError(err)
+		req.Equal(1, fetches)
+	})
+
+	t.Run("resources retrieved in a batch aren't fetched again", func(t *testing.T) {
+		req := require.New(t)
+		cache := NewRemoteCache()
+		fetches = 0
+
+		batched, _ := NewResource("v1", "Kind", "batched", map[string]any{"title": "batched"})
+		cache.add([]Resource{batched})
+		resource, err := cache.Get("Kind", "batched", fetch)
+		req.NoError(err)
+		req.Equal(0, fetches)
+		req.Equal("batched", resource.GetSpecValue("title"))
+	})
+
+	t.Run("caches aren't shared between registries", func(t *testing.T) {
+		req := require.New(t)
+		registry := NewRegistry(nil)
+		fetches = 0
+
+		first := registry.WithRemoteCache()
+		_, _ = CachedRemote(context.WithValue(context.Background(), remoteCacheKey{}, first.cache), "Kind", "uid", fetch)
+		_, _ = first.WithContext(context.Background()).cache.Get("Kind", "uid", fetch)
+		req.Equal(1, fetches, "copies of a registry share its cache")
+
+		_, _ = registry.WithRemoteCache().cache.Get("Kind", "uid", fetch)
+		req.Equal(2, fetches)
+
+		_, _ = CachedRemote(context.Background(), "Kind", "uid", fetch)
+		req.Equal(3, fetches, "contexts without a cache always fetch")
+	})
+
+	t.Run("cache can be used concurrently", func(t *testing.T) {
+		cache := NewRemoteCache()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = cache.Get("Kind", "concurrent", func() (*Resource, error) {
+					resource, _ := NewResource("v1", "Kind", "concurrent", map[string]any{})
+					return &resource, nil
+				})
+				cache.Invalidate("Kind", "concurrent")
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,55 @@
+package grizzly
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grafana/grizzly/pkg/grizzly/notifier"
+)
+
+// promptReader is where answers to interactive prompts are read from
+var promptReader = bufio.NewReader(os.Stdin)
+
+// confirmChange shows the changes made to a resource as `grr diff` shows
+// them, compared to its remote equivalent (nil for a new resource), and asks
+// whether to apply them. Changes are confirmed without asking when not
+// requested, or when stdout isn't a terminal.
+func confirmChange(registry Registry, handler Handler, opts ApplyOptions, resource Resource, remote *Resource) (bool, error) {
+	if !opts.Interactive || !interactive {
+		return true, nil
+	}
+
+	// comparing resources unprepares them, which must not alter the ones
+	// about to be applied
+	resource = resource.Clone()
+	if remote != nil {
+		cloned := remote.Clone()
+		remote = &cloned
+	}
+	diff, err := compareResources(registry, handler, &resource, remote, remoteDiffLabels, DiffOptions{ContextLines: opts.ContextLines})
+	if err != nil {
+		return false, err
+	}
+
+	notifier.HasChanges(resource.Ref(), truncateDiff(diff.changes(), opts.MaxDiffLines))
+	return confirm("apply this change? [y/N] "), nil
+}
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(question string) bool {
+	fmt.Fprint(outputWriter, question)
+
+	answer, err := promptReader.ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
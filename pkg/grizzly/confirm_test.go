@@ -0,0 +1,165 @@
+package grizzly
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestConfirm(t *testing.T) {
+	original := promptReader
+	t.Cleanup(func() { promptReader = original })
+
+	promptReader = bufio.NewReader(strings.NewReader("y\nYes\nn\n\nmaybe\n"))
+
+	for i, expected := range []bool{true, true, false, false, false, false} {
+		if got := confirm("apply this change? [y/N] "); got != expected {
+			t.Errorf("answer %d: expected %v, got %v", i, expected, got)
+		}
+	}
+}
+
+// diffHandler is a handler leaving out the id of resources, and rendering
+// the changes it is given, if any
+type diffHandler struct {
+	stubHandler
+	rendered string
+}
+
+func (h diffHandler) Unprepare(resource Resource) *Resource {
+	resource.DeleteSpecKey("id")
+	return &resource
+}
+
+func (h diffHandler) ResourceFilePath(resource Resource, filetype string) string {
+	return resource.Name() + "." + filetype
+}
+
+func (h diffHandler) Diff(remote, local Resource) (string, error) {
+	if h.rendered == "" {
+		return "", errors.New("no rendering")
+	}
+	return h.rendered, nil
+}
+
+func newConfirmRegistry(handler Handler) Registry {
+	return NewRegistry([]Provider{stubProvider{name: "Test", handlers: []Handler{handler}}})
+}
+
+func TestConfirmChangeNotInteractive(t *testing.T) {
+	original := promptReader
+	t.Cleanup(func() { promptReader = original })
+
+	// nothing to read: any prompt would decline the change
+	promptReader = bufio.NewReader(strings.NewReader(""))
+
+	handler := diffHandler{stubHandler: stubHandler{kind: "Dashboard"}}
+	registry := newConfirmRegistry(handler)
+	local, err := NewResource("v1", "Dashboard", "uid", map[string]any{"title": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := local.Clone()
+	remote.SetSpecString("title", "a")
+
+	confirmed, err := confirmChange(registry, handler, ApplyOptions{}, local, &remote)
+	if err != nil || !confirmed {
+		t.Errorf("expected change to be confirmed when interactive mode isn't requested, got %v (%v)", confirmed, err)
+	}
+
+	interactiveBefore := interactive
+	t.Cleanup(func() { interactive = interactiveBefore })
+	interactive = false
+
+	confirmed, err = confirmChange(registry, handler, ApplyOptions{Interactive: true}, local, &remote)
+	if err != nil || !confirmed {
+		t.Errorf("expected change to be confirmed when stdout isn't a terminal, got %v (%v)", confirmed, err)
+	}
+}
+
+// TestConfirmChangeDiff checks that changes applied interactively are shown
+// like `grr diff` shows them
+func TestConfirmChangeDiff(t *testing.T) {
+	originalReader, originalInteractive, originalNoColor := promptReader, interactive, color.NoColor
+	t.Cleanup(func() {
+		promptReader, interactive, color.NoColor = originalReader, originalInteractive, originalNoColor
+		SetOutput(os.Stdout)
+	})
+	color.NoColor = true
+
+	spec := func(changed string) map[string]any {
+		return map[string]any{"id": 1, "a": 1, "b": 2, "c": 3, "d": 4, "e": changed, "f": 6, "g": 7, "h": 8, "i": 9}
+	}
+	local, err := NewResource("v1", "Dashboard", "uid", spec("after"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, err := NewResource("v1", "Dashboard", "uid", spec("before"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// prompted returns what is shown before asking to confirm a change
+	prompted := func(handler Handler, opts ApplyOptions) string {
+		t.Helper()
+		promptReader = bufio.NewReader(strings.NewReader("n\n"))
+		var out bytes.Buffer
+		SetOutput(&out)
+		interactive = true
+		opts.Interactive = true
+		confirmed, err := confirmChange(newConfirmRegistry(handler), handler, opts, local, &remote)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if confirmed {
+			t.Error("expected the change to be declined")
+		}
+		return out.String()
+	}
+	handler := diffHandler{stubHandler: stubHandler{kind: "Dashboard"}}
+
+	t.Run("changes are shown as a unified diff", func(t *testing.T) {
+		output := prompted(handler, ApplyOptions{})
+		for _, line := range []string{"--- Remote", "+++ Local", "-    e: before", "+    e: after", "    h: 8"} {
+			if !strings.Contains(output, line) {
+				t.Errorf("expected a unified diff, containing %q:\n%s", line, output)
+			}
+		}
+		if strings.Contains(output, "    a: 1") {
+			t.Errorf("expected only %d lines of context:\n%s", DefaultDiffContextLines, output)
+		}
+	})
+
+	t.Run("context lines can be set", func(t *testing.T) {
+		output := prompted(handler, ApplyOptions{ContextLines: -1})
+		if !strings.Contains(output, "    a: 1") {
+			t.Errorf("expected the resource in full:\n%s", output)
+		}
+	})
+
+	t.Run("changes are truncated beyond the limit", func(t *testing.T) {
+		output := prompted(handler, ApplyOptions{MaxDiffLines: 3})
+		if strings.Contains(output, "+    e: after") || !strings.Contains(output, "more lines, 1 resource changed") {
+			t.Errorf("expected the changes to be truncated:\n%s", output)
+		}
+	})
+
+	t.Run("changes rendered by the handler are preferred", func(t *testing.T) {
+		output := prompted(diffHandler{stubHandler: stubHandler{kind: "Dashboard"}, rendered: "~ e: before -> after\n"}, ApplyOptions{})
+		if !strings.Contains(output, "~ e: before -> after") || strings.Contains(output, "--- Remote") {
+			t.Errorf("expected the changes rendered by the handler:\n%s", output)
+		}
+	})
+
+	t.Run("the resources compared are left untouched", func(t *testing.T) {
+		prompted(handler, ApplyOptions{})
+		if local.GetSpecValue("id") == nil || remote.GetSpecValue("id") == nil {
+			t.Error("expected the resources to be left as is")
+		}
+	})
+}
@@ -0,0 +1,338 @@
+package grizzly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+type DiffStatus string
+
+const (
+	// DiffStatusNew indicates that the resource doesn't exist remotely
+	DiffStatusNew DiffStatus = "new"
+	// DiffStatusChanged indicates that the local and remote resources differ
+	DiffStatusChanged DiffStatus = "changed"
+	// DiffStatusUnchanged indicates that the local and remote resources are identical
+	DiffStatusUnchanged DiffStatus = "unchanged"
+	// DiffStatusRemoved indicates that the resource only exists on the side
+	// compared against, when comparing local resources to each other
+	DiffStatusRemoved DiffStatus = "removed"
+)
+
+// diffLabels names the sides of a comparison in unified diffs
+type diffLabels struct {
+	from, to string
+}
+
+var (
+	// remoteDiffLabels are the labels of local resources compared to
+	// remote ones
+	remoteDiffLabels = diffLabels{from: "Remote", to: "Local"}
+	// localDiffLabels are the labels of local resources compared to each other
+	localDiffLabels = diffLabels{from: "Before", to: "After"}
+)
+
+// ResourceDiff describes the differences between a local resource and its
+// remote equivalent
+type ResourceDiff struct {
+	Kind   string     `yaml:"kind" json:"kind"`
+	UID    string     `yaml:"uid" json:"uid"`
+	Status DiffStatus `yaml:"status" json:"status"`
+	// Patch holds a unified diff from the remote resource to the local one
+	Patch string `yaml:"patch,omitempty" json:"patch,omitempty"`
+
+	// rendered holds the changes as rendered by the handler, if supported
+	rendered string
+}
+
+// changes returns the most readable description of the changes
+func (diff ResourceDiff) changes() string {
+	if diff.rendered != "" {
+		return diff.rendered
+	}
+	return diff.Patch
+}
+
+// DiffResources compares resources to those at the endpoints, and returns the
+// result of each comparison
+func DiffResources(ctx context.Context, registry Registry, resources Resources, opts DiffOptions) ([]ResourceDiff, error) {
+	diffs := make([]ResourceDiff, 0, resources.Len())
+
+	err := forEachDiff(ctx, registry, resources, opts, func(diff ResourceDiff) {
+		diffs = append(diffs, diff)
+	})
+
+	return diffs, err
+}
+
+func forEachDiff(ctx context.Context, registry Registry, resources Resources, opts DiffOptions, callback func(diff ResourceDiff)) error {
+	registry = registry.WithContext(ctx)
+	resources = opts.Selector.Filter(resources)
+	resources = overrideFolder(registry, resources, opts.Folder)
+	registry.prefetchRemotes(resources)
+
+	for _, resource := range resources.AsList() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		handler, err := registry.GetHandler(resource.Kind())
+		if err != nil {
+			return err
+		}
+
+		resource = *handler.Unprepare(resource)
+
+		log.Debugf("Getting the remote value for `%s`", resource.Ref())
+		remote, err := registry.getRemote(handler, resource)
+		if errors.Is(err, ErrNotFound) {
+			remote = nil
+		} else if err != nil {
+			return fmt.Errorf("Error retrieving resource from %s %s: %v", resource.Kind(), resource.Name(), err)
+		}
+
+		diff, err := compareResources(registry, handler, &resource, remote, remoteDiffLabels, opts)
+		if err != nil {
+			return err
+		}
+		callback(diff)
+	}
+
+	return nil
+}
+
+// forEachLocalDiff compares two sets of local resources, such as the output
+// of two versions of the same Jsonnet code, pairing resources by kind and
+// UID. Resources only found in the second set are new, while those only
+// found in the first one were removed.
+func forEachLocalDiff(ctx context.Context, registry Registry, before, after Resources, opts DiffOptions, callback func(diff ResourceDiff)) error {
+	before = opts.Selector.Filter(before)
+	after = opts.Selector.Filter(after)
+
+	compare := func(resource, previous *Resource) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		kind := resource
+		if kind == nil {
+			kind = previous
+		}
+		handler, err := registry.GetHandler(kind.Kind())
+		if err != nil {
+			return err
+		}
+		diff, err := compareResources(registry, handler, resource, previous, localDiffLabels, opts)
+		if err != nil {
+			return err
+		}
+		callback(diff)
+		return nil
+	}
+
+	for _, resource := range after.AsList() {
+		var previous *Resource
+		if found, ok := before.Find(resource.Ref()); ok {
+			previous = &found
+		}
+		if err := compare(&resource, previous); err != nil {
+			return err
+		}
+	}
+	for _, previous := range before.AsList() {
+		if _, ok := after.Find(previous.Ref()); ok {
+			continue
+		}
+		if err := compare(nil, &previous); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compareResources compares a resource to another version of it, such as the
+// remote one. Either may be nil: without the other version, the resource is
+// new, and without the resource, it was removed.
+func compareResources(registry Registry, handler Handler, resource, other *Resource, labels diffLabels, opts DiffOptions) (ResourceDiff, error) {
+	var diff ResourceDiff
+	var localResource, otherResource Resource
+	var local, otherRepresentation []byte
+	var err error
+	if other != nil {
+		otherResource, otherRepresentation, err = comparableRepresentation(registry, handler, *other, opts)
+		if err != nil {
+			return diff, err
+		}
+		diff.Kind, diff.UID = otherResource.Kind(), otherResource.Name()
+	}
+	if resource != nil {
+		localResource, local, err = comparableRepresentation(registry, handler, *resource, opts)
+		if err != nil {
+			return diff, err
+		}
+		diff.Kind, diff.UID = localResource.Kind(), localResource.Name()
+	}
+
+	switch {
+	case other == nil:
+		diff.Status = DiffStatusNew
+		diff.Patch = unifiedDiff("", string(local), labels, opts.ContextLines)
+	case resource == nil:
+		diff.Status = DiffStatusRemoved
+		diff.Patch = unifiedDiff(string(otherRepresentation), "", labels, opts.ContextLines)
+	case string(local) == string(otherRepresentation):
+		diff.Status = DiffStatusUnchanged
+	default:
+		diff.Status = DiffStatusChanged
+		diff.Patch = unifiedDiff(string(otherRepresentation), string(local), labels, opts.ContextLines)
+		diff.rendered = renderDiff(handler, otherResource, localResource)
+	}
+	return diff, nil
+}
+
+// comparableRepresentation returns the comparable form of a resource, along
+// with its representation in the output format of the options
+func comparableRepresentation(registry Registry, handler Handler, resource Resource, opts DiffOptions) (Resource, []byte, error) {
+	comparable := comparableForm(handler, *handler.Unprepare(resource))
+	representation, _, _, err := Format(registry, "", &comparable, opts.OutputFormat, opts.OnlySpec)
+	return comparable, representation, err
+}
+
+// renderDiff describes the changes between two resources as the handler
+// renders them. An empty string is returned when the handler doesn't support
+// it, or fails to.
+func renderDiff(handler Handler, remote, local Resource) string {
+	renderer, ok := handler.(DiffRenderHandler)
+	if !ok {
+		return ""
+	}
+
+	rendered, err := renderer.Diff(remote, local)
+	if err != nil {
+		log.Debugf("Rendering the changes of `%s` failed, falling back to a unified diff: %s", local.Ref(), err)
+		return ""
+	}
+	return rendered
+}
+
+// DefaultDiffContextLines is the number of unchanged lines shown around each
+// change by default
+const DefaultDiffContextLines = 3
+
+// unifiedDiff shows the changes from the remote representation of a resource
+// to the local one, surrounded by contextLines unchanged lines. Zero selects
+// DefaultDiffContextLines, and a negative value shows the resource in full.
+func unifiedDiff(remote, local string, labels diffLabels, contextLines int) string {
+	remoteLines := difflib.SplitLines(remote)
+	localLines := difflib.SplitLines(local)
+
+	switch {
+	case contextLines == 0:
+		contextLines = DefaultDiffContextLines
+	case contextLines < 0:
+		contextLines = max(len(remoteLines), len(localLines))
+	}
+
+	diff := difflib.UnifiedDiff{
+		A:        remoteLines,
+		B:        localLines,
+		FromFile: labels.from,
+		ToFile:   labels.to,
+		Context:  contextLines,
+	}
+	difference, _ := difflib.GetUnifiedDiffString(diff)
+	return difference
+}
+
+// truncateDiff keeps the first maxLines lines of the changes made to a
+// resource, and tells how many were left out. Zero keeps all lines.
+func truncateDiff(changes string, maxLines int) string {
+	if maxLines <= 0 {
+		return changes
+	}
+	lines := difflib.SplitLines(strings.TrimSuffix(changes, "\n"))
+	if len(lines) <= maxLines {
+		return changes
+	}
+	return strings.Join(lines[:maxLines], "") + fmt.Sprintf("... (%d more lines, 1 resource changed)\n", len(lines)-maxLines)
+}
+
+// DiffFormats lists the formats differences can be reported in (see
+// DiffOptions.DiffFormat)
+var DiffFormats = []string{formatDefault, formatJSON, formatYAML}
+
+// validateDiffFormat rejects unknown diff formats, instead of falling back to
+// text. An empty format selects the default one.
+func validateDiffFormat(format string) error {
+	if format != "" && !slices.Contains(DiffFormats, format) {
+		return fmt.Errorf("unknown diff format %q, expected one of: %s", format, strings.Join(DiffFormats, ", "))
+	}
+	return nil
+}
+
+func printDiffs(diffs []ResourceDiff, format string) error {
+	var output []byte
+	var err error
+	switch format {
+	case formatYAML:
+		output, err = yaml.Marshal(diffs)
+	case formatJSON:
+		output, err = json.MarshalIndent(diffs, "", "  ")
+	default:
+		return fmt.Errorf("unknown diff format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(outputWriter, string(output))
+	return nil
+}
+
+// comparableForm returns the form of a resource used to compare it: stripped
+// of the fields its handler ignores, and canonicalized if the handler supports it.
+func comparableForm(handler Handler, resource Resource) Resource {
+	resource = withoutIgnoredFields(handler, resource)
+
+	canonicalizeHandler, ok := handler.(CanonicalizeHandler)
+	if !ok {
+		return resource
+	}
+	return canonicalizeHandler.Canonicalize(resource)
+}
+
+// withoutIgnoredFields returns a copy of the given resource, stripped of the
+// fields that its handler asks to ignore during comparisons.
+func withoutIgnoredFields(handler Handler, resource Resource) Resource {
+	ignoreHandler, ok := handler.(DiffIgnoreHandler)
+	if !ok || len(ignoreHandler.DiffIgnorePaths()) == 0 {
+		return resource
+	}
+
+	resource = resource.Clone()
+	for _, path := range ignoreHandler.DiffIgnorePaths() {
+		deletePath(resource.Spec(), strings.Split(path, "."))
+	}
+
+	return resource
+}
+
+func deletePath(data map[string]any, path []string) {
+	if len(path) == 1 {
+		delete(data, path[0])
+		return
+	}
+
+	child, ok := data[path[0]].(map[string]any)
+	if !ok {
+		return
+	}
+
+	deletePath(child, path[1:])
+}
@@ -0,0 +1,201 @@
+package grizzly_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffResources(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider(
+		newFakeProvider().resource("unchanged", map[string]any{"title": "unchanged"}),
+		newFakeProvider().resource("changed", map[string]any{"title": "before"}),
+	)
+
+	resources := grizzly.NewResources(
+		provider.resource("new", map[string]any{"title": "new"}),
+		provider.resource("unchanged", map[string]any{"title": "unchanged"}),
+		provider.resource("changed", map[string]any{"title": "after"}),
+	)
+
+	diffs, err := grizzly.DiffResources(context.Background(), provider.registry(), resources, grizzly.DiffOptions{OutputFormat: "yaml"})
+	req.NoError(err)
+	req.Len(diffs, 3)
+
+	req.Equal(fakeKind, diffs[0].Kind)
+	req.Equal("new", diffs[0].UID)
+	req.Equal(grizzly.DiffStatusNew, diffs[0].Status)
+	req.Contains(diffs[0].Patch, "+    title: new")
+
+	req.Equal("unchanged", diffs[1].UID)
+	req.Equal(grizzly.DiffStatusUnchanged, diffs[1].Status)
+	req.Empty(diffs[1].Patch)
+
+	req.Equal("changed", diffs[2].UID)
+	req.Equal(grizzly.DiffStatusChanged, diffs[2].Status)
+	req.Contains(diffs[2].Patch, "-    title: before")
+	req.Contains(diffs[2].Patch, "+    title: after")
+}
+
+func TestDiffSummary(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider(
+		newFakeProvider().resource("unchanged", map[string]any{"title": "unchanged"}),
+		newFakeProvider().resource("changed", map[string]any{"title": "before"}),
+	)
+
+	resources := grizzly.NewResources(
+		provider.resource("new", map[string]any{"title": "new"}),
+		provider.resource("unchanged", map[string]any{"title": "unchanged"}),
+		provider.resource("changed", map[string]any{"title": "after"}),
+	)
+
+	var out bytes.Buffer
+	grizzly.SetOutput(&out)
+	t.Cleanup(func() { grizzly.SetOutput(os.Stdout) })
+
+	req.NoError(grizzly.Diff(context.Background(), provider.registry(), resources, grizzly.DiffOptions{OutputFormat: "yaml"}))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	req.Equal("1 unchanged, 1 changed, 1 new", lines[len(lines)-1])
+	req.Contains(out.String(), "Fake.unchanged")
+
+	out.Reset()
+	req.NoError(grizzly.Diff(context.Background(), provider.registry(), resources, grizzly.DiffOptions{OutputFormat: "yaml", OnlyChanges: true}))
+	req.NotContains(out.String(), "Fake.unchanged", "unchanged resources are left out")
+	req.Contains(out.String(), "Fake.new")
+	req.Contains(out.String(), "Fake.changed")
+	req.Contains(out.String(), "1 unchanged, 1 changed, 1 new", "unchanged resources are still counted")
+}
+
+func TestDiffLocal(t *testing.T) {
+	req := require.New(t)
+	// the remote resource is left out of the comparison
+	provider := newFakeProvider(newFakeProvider().resource("added", map[string]any{"title": "added"}))
+
+	before := grizzly.NewResources(
+		provider.resource("unchanged", map[string]any{"title": "unchanged"}),
+		provider.resource("changed", map[string]any{"title": "before"}),
+		provider.resource("removed", map[string]any{"title": "removed"}),
+	)
+	after := grizzly.NewResources(
+		provider.resource("unchanged", map[string]any{"title": "unchanged"}),
+		provider.resource("changed", map[string]any{"title": "after"}),
+		provider.resource("added", map[string]any{"title": "added"}),
+	)
+
+	var out bytes.Buffer
+	grizzly.SetOutput(&out)
+	t.Cleanup(func() { grizzly.SetOutput(os.Stdout) })
+
+	req.NoError(grizzly.DiffLocal(context.Background(), provider.registry(), before, after, grizzly.DiffOptions{OutputFormat: "yaml"}))
+
+	req.Contains(out.String(), "Fake.unchanged no differences")
+	req.Contains(out.String(), "Fake.added added")
+	req.Contains(out.String(), "Fake.removed removed")
+	req.Contains(out.String(), "--- Before")
+	req.Contains(out.String(), "+++ After")
+	req.Contains(out.String(), "-    title: before")
+	req.Contains(out.String(), "+    title: after")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	req.Equal("1 unchanged, 1 changed, 1 new, 1 removed", lines[len(lines)-1])
+}
+
+func TestDiffUnknownFormat(t *testing.T) {
+	provider := newFakeProvider()
+	resources := grizzly.NewResources(provider.resource("dashboard", map[string]any{"title": "dashboard"}))
+
+	err := grizzly.Diff(context.Background(), provider.registry(), resources, grizzly.DiffOptions{OutputFormat: "yaml", DiffFormat: "jsn"})
+	require.ErrorContains(t, err, `unknown diff format "jsn", expected one of: default, json, yaml`)
+
+	err = grizzly.DiffLocal(context.Background(), provider.registry(), resources, resources, grizzly.DiffOptions{OutputFormat: "yaml", DiffFormat: "jsn"})
+	require.ErrorContains(t, err, `unknown diff format "jsn"`)
+}
+
+func TestDiffContextLines(t *testing.T) {
+	spec := func(changed string) map[string]any {
+		return map[string]any{"a": 1, "b": 2, "c": 3, "d": 4, "e": changed, "f": 6, "g": 7, "h": 8, "i": 9}
+	}
+	provider := newFakeProvider(newFakeProvider().resource("changed", spec("before")))
+	resources := grizzly.NewResources(provider.resource("changed", spec("after")))
+
+	patch := func(contextLines int) string {
+		diffs, err := grizzly.DiffResources(context.Background(), provider.registry(), resources, grizzly.DiffOptions{
+			OutputFormat: "yaml",
+			ContextLines: contextLines,
+		})
+		require.NoError(t, err)
+		require.Len(t, diffs, 1)
+		return diffs[0].Patch
+	}
+
+	t.Run("three lines of context by default", func(t *testing.T) {
+		req := require.New(t)
+		p := patch(0)
+		req.Contains(p, "    b: 2")
+		req.Contains(p, "    h: 8")
+		req.NotContains(p, "    a: 1")
+		req.NotContains(p, "    i: 9")
+	})
+
+	t.Run("context can be narrowed", func(t *testing.T) {
+		req := require.New(t)
+		p := patch(1)
+		req.Contains(p, "    d: 4")
+		req.Contains(p, "    f: 6")
+		req.NotContains(p, "    c: 3")
+		req.NotContains(p, "    g: 7")
+	})
+
+	t.Run("resources can be shown in full", func(t *testing.T) {
+		req := require.New(t)
+		p := patch(-1)
+		req.Contains(p, "kind: "+fakeKind)
+		req.Contains(p, "    a: 1")
+		req.Contains(p, "    i: 9")
+		req.Equal(1, strings.Count(p, "@@ -"), "a single hunk is expected")
+	})
+}
+
+func TestDiffMaxDiffLines(t *testing.T) {
+	spec := func(changed string) map[string]any {
+		return map[string]any{"a": 1, "b": 2, "c": 3, "d": 4, "e": changed, "f": 6, "g": 7, "h": 8, "i": 9}
+	}
+	provider := newFakeProvider(newFakeProvider().resource("changed", spec("before")))
+	resources := grizzly.NewResources(provider.resource("changed", spec("after")))
+
+	var out bytes.Buffer
+	grizzly.SetOutput(&out)
+	t.Cleanup(func() { grizzly.SetOutput(os.Stdout) })
+
+	diff := func(maxDiffLines int) string {
+		out.Reset()
+		require.NoError(t, grizzly.Diff(context.Background(), provider.registry(), resources, grizzly.DiffOptions{
+			OutputFormat: "yaml",
+			ContextLines: -1,
+			MaxDiffLines: maxDiffLines,
+		}))
+		return out.String()
+	}
+
+	t.Run("changes are shown in full by default", func(t *testing.T) {
+		req := require.New(t)
+		output := diff(0)
+		req.Contains(output, "    i: 9")
+		req.NotContains(output, "more lines")
+	})
+
+	t.Run("changes are truncated beyond the limit", func(t *testing.T) {
+		req := require.New(t)
+		output := diff(3)
+		req.Contains(output, "+++ Local")
+		req.NotContains(output, "    a: 1")
+		req.Regexp(`\.\.\. \(\d+ more lines, 1 resource changed\)`, output)
+	})
+}
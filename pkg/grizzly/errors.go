@@ -14,8 +14,31 @@ var (
 
 	// ErrHandlerNotFound indicates that no handler was found for a particular resource Kind.
 	ErrHandlerNotFound = errors.New("handler not found")
+
+	// ErrTooManyResources signals an apply refused as it exceeds ApplyOptions.MaxResources
+	ErrTooManyResources = errors.New("too many resources")
+
+	// ErrWriteRejected signals a change refused by a remote endpoint as it is
+	// read-only, such as a replica. Providers' errors match it when their
+	// endpoint reports so.
+	ErrWriteRejected = errors.New("write rejected")
 )
 
+// WriteRejectedError describes a change to a resource refused by its remote
+// endpoint, as the endpoint or the resource is read-only
+type WriteRejectedError struct {
+	Action ApplyAction
+	Err    error
+}
+
+func (e WriteRejectedError) Error() string {
+	return fmt.Sprintf("the remote endpoint rejected the %s as read-only (ex: a replica): %s", e.Action, e.Err)
+}
+
+func (e WriteRejectedError) Unwrap() error {
+	return e.Err
+}
+
 // APIErr encapsulates an error from the Grafana API
 type APIErr struct {
 	Err  error
@@ -38,6 +38,15 @@ var (
 	ResourceUpdated    = EventType{ID: "resource-updated", Severity: Notice, HumanReadable: "updated"}
 	ResourcePulled     = EventType{ID: "resource-pulled", Severity: Notice, HumanReadable: "pulled"}
 	ResourceFailure    = EventType{ID: "resource-failure", Severity: Error, HumanReadable: "failed"}
+	ResourceSkipped    = EventType{ID: "resource-skipped", Severity: Info, HumanReadable: "skipped"}
+
+	// Emitted when updating resources without changes, with ApplyOptions.Force
+	ResourceForceUpdated = EventType{ID: "resource-force-updated", Severity: Notice, HumanReadable: "force-updated"}
+
+	// Emitted by dry-runs, instead of actually adding or updating resources
+	ResourceWouldBeAdded        = EventType{ID: "resource-would-be-added", Severity: Notice, HumanReadable: "would be added"}
+	ResourceWouldBeUpdated      = EventType{ID: "resource-would-be-updated", Severity: Notice, HumanReadable: "would be updated"}
+	ResourceWouldBeForceUpdated = EventType{ID: "resource-would-be-force-updated", Severity: Notice, HumanReadable: "would be force-updated"}
 )
 
 type Event struct {
@@ -56,6 +65,17 @@ func EventToPlainText(event Event) string {
 	return fmt.Sprintf("%s %s: %s\n", event.ResourceRef, event.Type.HumanReadable, event.Details)
 }
 
+// OnlyChanges wraps an EventFormatter to leave out the informational events
+// (ex: unchanged or skipped resources), which are still counted in summaries
+func OnlyChanges(formatter EventFormatter) EventFormatter {
+	return func(event Event) string {
+		if event.Type.Severity == Info {
+			return ""
+		}
+		return formatter(event)
+	}
+}
+
 func EventToColoredText(event Event) string {
 	var colorFunc func(...interface{}) string
 
@@ -80,6 +100,33 @@ func EventToColoredText(event Event) string {
 	return fmt.Sprintf("%s %s: %s\n", event.ResourceRef, eventType, event.Details)
 }
 
+// jsonEvent is the structure of the events written by EventToJSON
+type jsonEvent struct {
+	Action string `json:"action"`
+	Kind   string `json:"kind"`
+	UID    string `json:"uid"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// EventToJSON formats events as newline-delimited JSON objects, for log
+// pipelines and CI tooling
+func EventToJSON(event Event) string {
+	// references are formatted as <kind>.<uid>, or only hold a kind for
+	// events not related to a particular resource
+	kind, uid, _ := strings.Cut(event.ResourceRef, ".")
+
+	content, err := json.Marshal(jsonEvent{
+		Action: event.Type.ID,
+		Kind:   kind,
+		UID:    uid,
+		Detail: event.Details,
+	})
+	if err != nil {
+		return EventToPlainText(event)
+	}
+	return string(content) + "\n"
+}
+
 type Summary struct {
 	EventCounts map[EventType]int
 }
@@ -0,0 +1,55 @@
+package grizzly_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventToJSON(t *testing.T) {
+	t.Run("events are written as JSON lines", func(t *testing.T) {
+		req := require.New(t)
+
+		line := grizzly.EventToJSON(grizzly.Event{
+			Type:        grizzly.ResourceFailure,
+			ResourceRef: grizzly.NewResourceRef("Dashboard", "my.dashboard").String(),
+			Details:     "something went wrong",
+		})
+
+		req.True(strings.HasSuffix(line, "\n"))
+		req.Equal(1, strings.Count(line, "\n"))
+
+		event := map[string]string{}
+		req.NoError(json.Unmarshal([]byte(line), &event))
+		req.Equal(map[string]string{
+			"action": "resource-failure",
+			"kind":   "Dashboard",
+			"uid":    "my.dashboard",
+			"detail": "something went wrong",
+		}, event)
+	})
+
+	t.Run("details are omitted when empty", func(t *testing.T) {
+		req := require.New(t)
+
+		line := grizzly.EventToJSON(grizzly.Event{
+			Type:        grizzly.ResourceAdded,
+			ResourceRef: grizzly.NewResourceRef("Folder", "team-a").String(),
+		})
+
+		req.JSONEq(`{"action": "resource-added", "kind": "Folder", "uid": "team-a"}`, line)
+	})
+}
+
+func TestOnlyChanges(t *testing.T) {
+	formatter := grizzly.OnlyChanges(grizzly.EventToPlainText)
+	ref := grizzly.NewResourceRef("Dashboard", "overview").String()
+
+	require.Empty(t, formatter(grizzly.Event{Type: grizzly.ResourceNotChanged, ResourceRef: ref}))
+	require.Empty(t, formatter(grizzly.Event{Type: grizzly.ResourceSkipped, ResourceRef: ref}))
+	require.Equal(t, "Dashboard.overview updated\n", formatter(grizzly.Event{Type: grizzly.ResourceUpdated, ResourceRef: ref}))
+	require.Equal(t, "Dashboard.overview failed: boom\n", formatter(grizzly.Event{Type: grizzly.ResourceFailure, ResourceRef: ref, Details: "boom"}))
+}
@@ -50,7 +50,13 @@ func (h *BaseHandler) Detect(map[string]any) bool {
 	return false
 }
 
-// Handler describes a handler for a single API resource handled by a single provider
+// Handler describes a handler for a single API resource handled by a single provider.
+//
+// Custom handlers can be added to a Registry with RegisterHandler. Embedding
+// BaseHandler (see NewBaseHandler) provides APIVersion, Kind, UsesFolders,
+// Prepare, Unprepare, GetUID, Sort and Detect, leaving ResourceFilePath,
+// GetSpecUID, GetByUID, GetRemote, ListRemote, Add, Update and Validate to be
+// implemented.
 type Handler interface {
 	APIVersion() string
 	Kind() string
@@ -98,11 +104,200 @@ type Handler interface {
 	Detect(map[string]any) bool
 }
 
+// SnapshotOptions describes how a snapshot is created
+type SnapshotOptions struct {
+	// Name of the snapshot. The remote endpoint picks one when empty.
+	Name string
+	// ExpiresSeconds is the lifetime of the snapshot, which never expires when 0
+	ExpiresSeconds int
+}
+
 // SnapshotHandler describes a handler that has the ability to push a resource as
 // a snapshot
 type SnapshotHandler interface {
-	// Snapshot pushes a resource as a snapshot with an expiry
-	Snapshot(resource Resource, expiresSeconds int) error
+	// Snapshot pushes a resource as a snapshot
+	Snapshot(resource Resource, opts SnapshotOptions) error
+}
+
+// RenderOptions describes how a resource is rendered as an image
+type RenderOptions struct {
+	// From and To bound the time range shown, as absolute or relative times
+	// (ex: now-6h). The range saved with the resource is used when empty.
+	From string
+	To   string
+	// Theme of the image, light or dark. The default theme is used when empty.
+	Theme string
+	// Width and Height of the image, in pixels
+	Width  int
+	Height int
+	// PanelID selects a single panel to render, rather than the whole resource
+	PanelID int
+}
+
+// RenderHandler describes a handler able to render a resource as a PNG image
+type RenderHandler interface {
+	// Render renders a remote resource as a PNG image. ErrNotImplemented is
+	// returned if the remote endpoint can't render images.
+	Render(resource Resource, opts RenderOptions) ([]byte, error)
+}
+
+// SnapshotInfo describes a snapshot stored by a remote endpoint
+type SnapshotInfo struct {
+	Key     string `yaml:"key" json:"key"`
+	Name    string `yaml:"name" json:"name"`
+	Expires string `yaml:"expires" json:"expires"`
+}
+
+// SnapshotManager describes a handler able to list and delete the snapshots
+// stored by its remote endpoint
+type SnapshotManager interface {
+	// ListSnapshots retrieves the snapshots stored remotely
+	ListSnapshots() ([]SnapshotInfo, error)
+
+	// DeleteSnapshot deletes a snapshot, by key. ErrNotFound is returned if
+	// no such snapshot exists.
+	DeleteSnapshot(key string) error
+}
+
+// RollbackHandler describes a handler able to restore a previous version of a
+// remote resource
+type RollbackHandler interface {
+	// Rollback restores a version of a remote resource. It returns the
+	// restored resource, along with the version number it was given.
+	Rollback(uid string, version int64) (*Resource, int64, error)
+}
+
+// Version describes a version of a remote resource
+type Version struct {
+	Version int64  `yaml:"version" json:"version"`
+	Created string `yaml:"created" json:"created"`
+	Author  string `yaml:"author" json:"author"`
+	Message string `yaml:"message" json:"message"`
+}
+
+// VersionsHandler describes a handler able to list the versions kept for a
+// remote resource, to pick one to roll back to
+type VersionsHandler interface {
+	// Versions lists the versions of a remote resource, latest first.
+	// ErrNotFound is returned if the resource doesn't exist.
+	Versions(uid string) ([]Version, error)
+}
+
+// RawRemoteHandler describes a handler able to return remote resources
+// exactly as the endpoint stores them, for faithful backups
+type RawRemoteHandler interface {
+	// GetRemoteRaw returns the spec of a remote resource, as the bytes
+	// returned by the endpoint, without re-encoding them.
+	// ErrNotFound is returned if the resource doesn't exist.
+	GetRemoteRaw(uid string) ([]byte, error)
+}
+
+// DiffIgnoreHandler describes a handler for resources holding fields that are
+// managed by the remote endpoint, and that should be ignored when comparing
+// local and remote resources
+type DiffIgnoreHandler interface {
+	// DiffIgnorePaths lists the fields of the spec to ignore when comparing
+	// resources. Nested fields are separated by dots (ex: meta.updated)
+	DiffIgnorePaths() []string
+}
+
+// CanonicalizeHandler describes a handler for resources whose representation
+// may change without any semantic difference (ex: the order of some lists),
+// and that should be put in a canonical form before comparing local and
+// remote resources
+type CanonicalizeHandler interface {
+	// Canonicalize returns a canonical copy of a resource, leaving the given
+	// resource untouched
+	Canonicalize(resource Resource) Resource
+}
+
+// DiffRenderHandler describes a handler rendering the changes made to its
+// resources in a more readable way than a line-based diff
+type DiffRenderHandler interface {
+	// Diff describes the changes from a remote resource to a local one. On
+	// error, a unified diff is shown instead.
+	Diff(remote, local Resource) (string, error)
+}
+
+// FolderResolverHandler describes a handler managing the folders other
+// resources are stored in (ex: dashboard folders)
+type FolderResolverHandler interface {
+	// ResolveFolderUID returns the UID of a folder referenced by UID or title
+	ResolveFolderUID(ref string) (string, error)
+}
+
+// BatchRemoteHandler describes a handler able to retrieve many resources at
+// once (ex: through a search API), instead of one request per resource
+type BatchRemoteHandler interface {
+	// GetRemotes returns the remote equivalents of the given resources, as
+	// GetRemote would, along with the UIDs of the resources which don't exist
+	// remotely. Resources which can't be looked up this way are left out.
+	GetRemotes(resources []Resource) ([]Resource, []string, error)
+}
+
+// DereferenceHandler describes a handler able to rewrite the references a
+// resource makes to others by name, which differ between remote endpoints,
+// to the UIDs they have on the remote endpoint
+type DereferenceHandler interface {
+	// Dereference returns a copy of a resource whose references by name are
+	// rewritten, along with a description of each rewrite
+	Dereference(resource Resource) (Resource, []string, error)
+}
+
+// AppliedDetailsHandler describes a handler reporting details on the
+// resources it added or updated (ex: the URL they are available at), which
+// are shown along with the outcome of applying them.
+type AppliedDetailsHandler interface {
+	// AppliedDetails describes the last add or update of a resource. An empty
+	// string is returned when there is nothing to report.
+	AppliedDetails(resource Resource) string
+}
+
+// ReferenceHandler describes a handler for resources referencing others
+// (ex: the dashboards of a playlist), able to point out the references that
+// can't be found among the local resources
+type ReferenceHandler interface {
+	// UnresolvedReferences describes each reference of a resource to a
+	// resource which isn't among the given ones. These are only warnings:
+	// the referenced resources may well exist remotely.
+	UnresolvedReferences(resource Resource, resources Resources) []string
+}
+
+// RemoteValidateHandler describes a handler able to check resources against
+// their remote endpoint, catching errors a local validation can't (ex: a
+// missing folder), without changing any remote resource
+type RemoteValidateHandler interface {
+	// ValidateRemote reports why the remote endpoint would reject a resource
+	ValidateRemote(resource Resource) error
+}
+
+// ConflictHandler describes a handler able to find the remote resources a
+// local resource could collide with once applied, such as resources owned by
+// another team in a shared instance, without changing any remote resource
+type ConflictHandler interface {
+	// Conflicts describes each remote resource the resource could collide
+	// with (ex: the same UID, or the same title). Potential collisions
+	// aren't errors: the resource may well be the one applied previously.
+	Conflicts(resource Resource) ([]string, error)
+}
+
+// HealthCheckHandler describes a handler able to check whether remote
+// resources work once applied (ex: whether a datasource can connect to its
+// backend with the credentials it was given)
+type HealthCheckHandler interface {
+	// CheckHealth checks the remote version of a resource, returning whether
+	// it is healthy along with the message describing the outcome. Failing
+	// checks aren't errors: errors are reserved to checks that couldn't run.
+	CheckHealth(resource Resource) (bool, string, error)
+}
+
+// UIDGeneratorHandler describes a handler able to derive a stable UID for
+// resources that don't specify any
+type UIDGeneratorHandler interface {
+	// GenerateUID derives a UID from the content of a resource, sets it in the
+	// spec of the resource, and returns it. The same content must always
+	// yield the same UID.
+	GenerateUID(resource *Resource) (string, error)
 }
 
 // ListenHandler describes a handler that has the ability to watch a single
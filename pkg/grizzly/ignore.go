@@ -0,0 +1,112 @@
+package grizzly
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFile is the name of the file listing the paths to skip when parsing a directory
+const IgnoreFile = ".grizzlyignore"
+
+// ignoreRules holds the gitignore-style patterns of an ignore file. Patterns
+// are anchored to the directory holding the file.
+type ignoreRules []ignoreRule
+
+type ignoreRule struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+}
+
+// loadIgnoreRules reads the ignore file of a directory, if any
+func loadIgnoreRules(dir string) (ignoreRules, error) {
+	file, err := os.Open(filepath.Join(dir, IgnoreFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rules := ignoreRules{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, scanner.Err()
+}
+
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ignoreRule{}, false
+	}
+
+	rule := ignoreRule{}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return ignoreRule{}, false
+	}
+
+	rule.segments = strings.Split(line, "/")
+	return rule, true
+}
+
+// Ignored checks whether a path, relative to the directory holding the
+// ignore file, is excluded. The last matching pattern wins.
+func (rules ignoreRules) Ignored(relativePath string, isDir bool) bool {
+	segments := strings.Split(filepath.ToSlash(relativePath), "/")
+
+	ignored := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if matchSegments(rule.segments, segments) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matchSegments matches path segments against pattern segments, where "**"
+// matches any number of segments, including none.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	matched, err := path.Match(pattern[0], segments[0])
+	if err != nil || !matched {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,44 @@
+package grizzly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreRules(t *testing.T) {
+	rules := ignoreRules{}
+	for _, line := range []string{
+		"# helpers",
+		"**/_*.libsonnet",
+		"vendor/",
+		"/drafts/*.json",
+		"!drafts/keep.json",
+		"",
+	} {
+		if rule, ok := parseIgnoreRule(line); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	for _, tc := range []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"_helpers.libsonnet", false, true},
+		{"lib/nested/_helpers.libsonnet", false, true},
+		{"lib/helpers.libsonnet", false, false},
+		{"vendor", true, true},
+		{"vendor", false, false},
+		{"lib/vendor", true, false},
+		{"drafts/dashboard.json", false, true},
+		{"drafts/nested/dashboard.json", false, false},
+		{"drafts/keep.json", false, false},
+		{"dashboards/dashboard.json", false, false},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			require.Equal(t, tc.expected, rules.Ignored(tc.path, tc.isDir))
+		})
+	}
+}
@@ -2,6 +2,7 @@ package grizzly
 
 import (
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -34,17 +35,24 @@ func (parser *JSONParser) Parse(file string, options ParserOptions) (Resources,
 	}
 	defer f.Close()
 
-	var m any
-	err = json.NewDecoder(f).Decode(&m)
-	if err != nil {
-		return Resources{}, err
-	}
-
 	source := Source{
-		Format:     formatJSON,
 		Path:       file,
 		Rewritable: true,
 	}
+	return parser.parseReader(f, source, options)
+}
 
-	return parseAny(parser.registry, m, options.DefaultResourceKind, options.DefaultFolderUID, source)
+// parseReader parses the JSON document read from r into resources
+func (parser *JSONParser) parseReader(r io.Reader, source Source, options ParserOptions) (Resources, error) {
+	source.Format = formatJSON
+	var m any
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return Resources{}, err
+	}
+
+	resources, err := parseAny(parser.registry, m, options, source)
+	if err != nil {
+		return Resources{}, err
+	}
+	return resources.Sort(), nil
 }
@@ -13,17 +13,23 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+// DefaultJsonnetDashboardKeys are the keys of the Jsonnet output holding
+// dashboards, following the convention of monitoring mixins
+var DefaultJsonnetDashboardKeys = []string{"grafanaDashboards"}
+
 type JsonnetParser struct {
-	registry     Registry
-	jsonnetPaths []string
-	logger       *log.Entry
+	registry      Registry
+	jsonnetPaths  []string
+	dashboardKeys []string
+	logger        *log.Entry
 }
 
 func NewJsonnetParser(registry Registry, jsonnetPaths []string) *JsonnetParser {
 	return &JsonnetParser{
-		registry:     registry,
-		jsonnetPaths: jsonnetPaths,
-		logger:       log.WithField("parser", "jsonnet"),
+		registry:      registry,
+		jsonnetPaths:  jsonnetPaths,
+		dashboardKeys: DefaultJsonnetDashboardKeys,
+		logger:        log.WithField("parser", "jsonnet"),
 	}
 }
 
@@ -44,7 +50,7 @@ func (parser *JsonnetParser) Parse(file string, options ParserOptions) (Resource
 	if err != nil {
 		return Resources{}, err
 	}
-	result, err := evaluateJsonnet(file, currentWorkingDirectory, parser.jsonnetPaths)
+	result, err := evaluateJsonnet(file, currentWorkingDirectory, parser.jsonnetPaths, parser.dashboardKeys)
 	if err != nil {
 		return Resources{}, err
 	}
@@ -59,7 +65,11 @@ func (parser *JsonnetParser) Parse(file string, options ParserOptions) (Resource
 		Rewritable: false,
 	}
 
-	return parseAny(parser.registry, data, options.DefaultResourceKind, options.DefaultFolderUID, source)
+	resources, err := parseAny(parser.registry, data, options, source)
+	if err != nil {
+		return Resources{}, err
+	}
+	return resources.Sort(), nil
 }
 
 // extendedImporter does stuff
@@ -77,8 +87,13 @@ type importProcessor func(contents, foundAt string) (c *jsonnet.Contents, err er
 //go:embed grizzly.jsonnet
 var script string
 
-func evaluateJsonnet(jsonnetFile, wd string, jpath []string) (string, error) {
-	s := fmt.Sprintf(script, jsonnetFile)
+func evaluateJsonnet(jsonnetFile, wd string, jpath []string, dashboardKeys []string) (string, error) {
+	// JSON strings are valid Jsonnet strings
+	keys, err := json.Marshal(dashboardKeys)
+	if err != nil {
+		return "", err
+	}
+	s := fmt.Sprintf(script, jsonnetFile, keys)
 	vm := jsonnet.MakeVM()
 	vm.Importer(newExtendedImporter(jsonnetFile, wd, jpath))
 	vm.NativeFunction(escapeStringRegexNativeFunc())
@@ -2,6 +2,9 @@ package notifier
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/fatih/color"
 )
@@ -10,63 +13,97 @@ var (
 	red    = color.New(color.FgRed).SprintFunc()
 	yellow = color.New(color.FgYellow).SprintFunc()
 	green  = color.New(color.FgGreen).SprintFunc()
+	cyan   = color.New(color.FgCyan).SprintFunc()
+	bold   = color.New(color.Bold).SprintFunc()
 )
 
+// output is where notifications are written, stdout by default
+var output io.Writer = os.Stdout
+
+// SetOutput changes where notifications are written
+func SetOutput(w io.Writer) {
+	output = w
+}
+
 // NoChanges announces that nothing has changed
 func NoChanges(obj fmt.Stringer) {
-	fmt.Printf("%s %s\n", obj.String(), yellow("no differences"))
+	fmt.Fprintf(output, "%s %s\n", obj.String(), yellow("no differences"))
 }
 
 // HasChanges announces that a resource has changed, and displays the differences
 func HasChanges(obj fmt.Stringer, diff string) {
-	fmt.Printf("%s %s\n", obj.String(), red("changes detected:"))
-	fmt.Println(diff)
+	fmt.Fprintf(output, "%s %s\n", obj.String(), red("changes detected:"))
+	fmt.Fprintln(output, colorDiff(diff))
+}
+
+// colorDiff highlights the added and removed lines of a unified diff.
+// Colors are automatically disabled when stdout isn't a terminal.
+func colorDiff(diff string) string {
+	if color.NoColor {
+		return diff
+	}
+
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = bold(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = green(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = red(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = cyan(line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
 }
 
 // NotFound announces that a resource was not found on the remote endpoint
 func NotFound(obj fmt.Stringer) {
-	fmt.Printf("%s %s\n", obj.String(), yellow("not found"))
+	fmt.Fprintf(output, "%s %s\n", obj.String(), yellow("not found"))
 }
 
 // Added announces that a resource has been added to the remote endpoint
 func Added(obj fmt.Stringer) {
-	fmt.Printf("%s %s\n", obj.String(), green("added"))
+	fmt.Fprintf(output, "%s %s\n", obj.String(), green("added"))
 }
 
 // Updated announces that a resource has been updated at the remote endpoint
 func Updated(obj fmt.Stringer) {
-	fmt.Printf("%s %s\n", obj.String(), green("updated"))
+	fmt.Fprintf(output, "%s %s\n", obj.String(), green("updated"))
 }
 
 // NotSupported announces that a behaviour is not supported by a handler
 func NotSupported(obj fmt.Stringer, behaviour string) {
-	fmt.Printf("%s %s\n", obj.String(), red("does not support "+behaviour))
+	fmt.Fprintf(output, "%s %s\n", obj.String(), red("does not support "+behaviour))
 }
 
 // Info announces a message in green
 func Info(obj fmt.Stringer, msg string) {
 	if obj == nil {
-		fmt.Println(green(msg))
+		fmt.Fprintln(output, green(msg))
 	} else {
-		fmt.Printf("%s %s\n", obj.String(), green(msg))
+		fmt.Fprintf(output, "%s %s\n", obj.String(), green(msg))
 	}
 }
 
 // Warn announces a message in yellow
 func Warn(obj fmt.Stringer, msg string) {
 	if obj == nil {
-		fmt.Println(yellow(msg))
+		fmt.Fprintln(output, yellow(msg))
 	} else {
-		fmt.Printf("%s %s\n", obj.String(), yellow(msg))
+		fmt.Fprintf(output, "%s %s\n", obj.String(), yellow(msg))
 	}
 }
 
 // Error announces a message in yellow
 func Error(obj fmt.Stringer, msg string) {
 	if obj == nil {
-		fmt.Println(red(msg))
+		fmt.Fprintln(output, red(msg))
 	} else {
-		fmt.Printf("%s %s\n", obj.String(), red(msg))
+		fmt.Fprintf(output, "%s %s\n", obj.String(), red(msg))
 	}
 }
 
@@ -0,0 +1,47 @@
+package notifier
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorDiff(t *testing.T) {
+	diff := "--- Remote\n+++ Local\n@@ -1 +1 @@\n-title: before\n+title: after\n context\n"
+
+	t.Run("added and removed lines are colored", func(t *testing.T) {
+		req := require.New(t)
+		noColor := color.NoColor
+		color.NoColor = false
+		t.Cleanup(func() { color.NoColor = noColor })
+
+		colored := colorDiff(diff)
+
+		req.Contains(colored, green("+title: after"))
+		req.Contains(colored, red("-title: before"))
+		req.NotEqual(diff, colored)
+		req.Contains(colored, "\n context\n")
+	})
+
+	t.Run("diff is left untouched without colors", func(t *testing.T) {
+		req := require.New(t)
+		noColor := color.NoColor
+		color.NoColor = true
+		t.Cleanup(func() { color.NoColor = noColor })
+
+		req.Equal(diff, colorDiff(diff))
+	})
+}
+
+func TestSetOutput(t *testing.T) {
+	var out bytes.Buffer
+	SetOutput(&out)
+	t.Cleanup(func() { SetOutput(os.Stdout) })
+
+	Added(SimpleString("Dashboard.uid"))
+	require.Contains(t, out.String(), "Dashboard.uid")
+	require.Contains(t, out.String(), "added")
+}
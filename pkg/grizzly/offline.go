@@ -0,0 +1,34 @@
+package grizzly
+
+import (
+	"fmt"
+)
+
+// WithOfflineRemote returns a copy of the registry where the given resources,
+// typically read from an export directory with ParseExport, stand in for the
+// remote endpoints: remote resources are looked up among them, without
+// reaching any endpoint. As nothing can be pushed to them, only diffs and dry
+// runs are supported.
+func (r Registry) WithOfflineRemote(resources Resources) Registry {
+	r.offline = &resources
+	return r
+}
+
+// offlineResources returns the resources standing in for the remote
+// endpoints, if any
+func (r Registry) offlineResources() (Resources, bool) {
+	if r.offline == nil {
+		return Resources{}, false
+	}
+	return *r.offline, true
+}
+
+// getOffline looks up a remote resource among the offline ones
+func getOffline(resources Resources, kind, uid string) (*Resource, error) {
+	resource, found := resources.Find(NewResourceRef(kind, uid))
+	if !found {
+		return nil, fmt.Errorf("%s not found in the offline remote: %w", NewResourceRef(kind, uid), ErrNotFound)
+	}
+	clone := resource.Clone()
+	return &clone, nil
+}
@@ -0,0 +1,38 @@
+package grizzly
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineRemote(t *testing.T) {
+	req := require.New(t)
+	registry := NewRegistry(nil)
+	_, ok := registry.offlineResources()
+	req.False(ok, "remote endpoints are used by default")
+
+	exported, err := NewResource("v1", "Kind", "uid", map[string]any{"title": "exported"})
+	req.NoError(err)
+	offlineRegistry := registry.WithOfflineRemote(NewResources(exported))
+
+	_, ok = registry.offlineResources()
+	req.False(ok, "the registry the offline one is made from is left as is")
+	resources, ok := offlineRegistry.WithContext(context.Background()).offlineResources()
+	req.True(ok)
+
+	remote, err := getOffline(resources, "Kind", "uid")
+	req.NoError(err)
+	remote.SetSpecString("title", "modified")
+
+	remote, err = getOffline(resources, "Kind", "uid")
+	req.NoError(err)
+	req.Equal("exported", remote.GetSpecValue("title"), "offline resources must not be altered by callers")
+
+	_, err = getOffline(resources, "Kind", "missing")
+	req.ErrorIs(err, ErrNotFound)
+
+	err = Apply(context.Background(), offlineRegistry, NewResources(), ApplyOptions{}, nil)
+	req.ErrorContains(err, "can't be applied to an offline remote")
+}
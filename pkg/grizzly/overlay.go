@@ -0,0 +1,82 @@
+package grizzly
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ApplyOverlays patches resources with the overlays found in some paths,
+// each of them possibly being a glob pattern. Overlays are resources with an
+// envelope, holding only the fields to change: each of them is merged onto
+// the resource of the same kind and name, following JSON merge patches
+// (RFC 7386). Objects are merged recursively, null values remove fields, and
+// other values, lists included, replace the ones of the resource.
+//
+// Overlays are applied in the order they are found. Patched resources are no
+// longer rewritable, as their files don't hold their whole definition.
+func ApplyOverlays(parser Parser, resources Resources, patterns []string, options ParserOptions) (Resources, error) {
+	// overlays don't have overlays of their own
+	options.Overlays = nil
+
+	var finalErr error
+	for _, pattern := range patterns {
+		paths, err := expandPattern(pattern)
+		if err != nil {
+			finalErr = multierror.Append(finalErr, err)
+			continue
+		}
+
+		for _, path := range paths {
+			overlays, err := parser.Parse(path, options)
+			if err != nil {
+				finalErr = multierror.Append(finalErr, err)
+				continue
+			}
+
+			for _, overlay := range overlays.AsList() {
+				if !overlay.Source.WithEnvelope {
+					finalErr = multierror.Append(finalErr, fmt.Errorf("overlay %s (%s): overlays must have an envelope (apiVersion, kind, metadata.name)", overlay.Ref(), path))
+					continue
+				}
+				resource, found := resources.Find(overlay.Ref())
+				if !found {
+					finalErr = multierror.Append(finalErr, fmt.Errorf("overlay %s (%s): no resource to patch", overlay.Ref(), path))
+					continue
+				}
+
+				patched := Resource{
+					Body:   mergePatch(resource.Body, overlay.Body).(map[string]any),
+					Source: resource.Source,
+				}
+				patched.Source.Rewritable = false
+				resources.Add(patched)
+			}
+		}
+	}
+
+	return resources, finalErr
+}
+
+// mergePatch applies a JSON merge patch to a value, without altering it
+func mergePatch(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return cloneValue(patch)
+	}
+
+	merged := map[string]any{}
+	if targetObject, ok := target.(map[string]any); ok {
+		for key, value := range targetObject {
+			merged[key] = cloneValue(value)
+		}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = mergePatch(merged[key], value)
+	}
+	return merged
+}
@@ -1,6 +1,8 @@
 package grizzly
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"os"
@@ -26,6 +28,15 @@ func (err ParseError) Error() string {
 type ParserOptions struct {
 	DefaultResourceKind string
 	DefaultFolderUID    string
+	// DeriveUIDs gives resources without envelope nor UID a UID derived from
+	// their content, when their handler supports it (ex: dashboard titles)
+	DeriveUIDs bool
+	// Overlays lists paths of overlays patching the resources parsed by
+	// ParsePaths (see ApplyOverlays)
+	Overlays []string
+	// Transforms lists paths of Jsonnet transforms run over the resources
+	// parsed by ParsePaths, after overlays (see ApplyTransforms)
+	Transforms []string
 }
 
 type FormatParser interface {
@@ -39,9 +50,15 @@ type Parser interface {
 }
 
 type parsersConfig struct {
-	continueOnError bool
+	continueOnError      bool
+	strict               bool
+	format               string
+	jsonnetDashboardKeys []string
 }
 
+// InputFormats lists the formats that can be forced with ParserFormat
+var InputFormats = []string{"json", "yaml", "jsonnet"}
+
 type ParserOpt func(config *parsersConfig)
 
 func ParserContinueOnError(continueOnError bool) ParserOpt {
@@ -50,6 +67,31 @@ func ParserContinueOnError(continueOnError bool) ParserOpt {
 	}
 }
 
+// ParserStrict makes parsing fail on YAML documents of an unknown kind,
+// instead of skipping them with a warning
+func ParserStrict(strict bool) ParserOpt {
+	return func(config *parsersConfig) {
+		config.strict = strict
+	}
+}
+
+// ParserFormat forces all files to be parsed with the given format (one of
+// InputFormats), whatever their extension. Files are otherwise parsed
+// according to their extension.
+func ParserFormat(format string) ParserOpt {
+	return func(config *parsersConfig) {
+		config.format = format
+	}
+}
+
+// ParserJsonnetDashboardKeys sets the keys of the Jsonnet output holding
+// dashboards. Defaults to DefaultJsonnetDashboardKeys.
+func ParserJsonnetDashboardKeys(keys []string) ParserOpt {
+	return func(config *parsersConfig) {
+		config.jsonnetDashboardKeys = keys
+	}
+}
+
 func DefaultParser(registry Registry, targets []string, jsonnetPaths []string, opts ...ParserOpt) Parser {
 	config := &parsersConfig{}
 
@@ -57,17 +99,223 @@ func DefaultParser(registry Registry, targets []string, jsonnetPaths []string, o
 		opt(config)
 	}
 
+	jsonnetParser := NewJsonnetParser(registry, jsonnetPaths)
+	if len(config.jsonnetDashboardKeys) > 0 {
+		jsonnetParser.dashboardKeys = config.jsonnetDashboardKeys
+	}
+
+	formatParsers := map[string]FormatParser{
+		"json":    NewJSONParser(registry),
+		"yaml":    NewYAMLParser(registry, config.strict),
+		"jsonnet": jsonnetParser,
+	}
+
+	chain := []FormatParser{formatParsers["json"], formatParsers["yaml"], formatParsers["jsonnet"]}
+	if forced, ok := formatParsers[config.format]; ok {
+		chain = []FormatParser{acceptAllParser{forced}}
+	}
+
 	return NewFilteredParser(
 		registry,
-		NewChainParser([]FormatParser{
-			NewJSONParser(registry),
-			NewYAMLParser(registry),
-			NewJsonnetParser(registry, jsonnetPaths),
-		}, config.continueOnError),
+		NewChainParser(chain, config.continueOnError),
 		targets,
 	)
 }
 
+// acceptAllParser parses every file with the decorated parser, whatever
+// their extension
+type acceptAllParser struct {
+	FormatParser
+}
+
+func (parser acceptAllParser) Accept(file string) bool {
+	return true
+}
+
+// ParsePaths parses the resources found in several paths, each of them
+// possibly being a glob pattern (ex: dashboards/*.jsonnet), and merges them.
+// A resource found more than once must be defined identically every time.
+func ParsePaths(registry Registry, parser Parser, patterns []string, options ParserOptions) (Resources, error) {
+	resources := NewResources()
+	var finalErr error
+
+	for _, pattern := range patterns {
+		paths, err := expandPattern(pattern)
+		if err != nil {
+			finalErr = multierror.Append(finalErr, err)
+			continue
+		}
+
+		for _, path := range paths {
+			parsed, err := parser.Parse(path, options)
+			if err != nil {
+				finalErr = multierror.Append(finalErr, err)
+			}
+
+			if err := mergeUnique(resources, parsed); err != nil {
+				finalErr = multierror.Append(finalErr, err)
+			}
+		}
+	}
+
+	if len(options.Overlays) > 0 {
+		var err error
+		resources, err = ApplyOverlays(parser, resources, options.Overlays, options)
+		if err != nil {
+			finalErr = multierror.Append(finalErr, err)
+		}
+	}
+
+	if len(options.Transforms) > 0 {
+		var err error
+		resources, err = ApplyTransforms(resources, options.Transforms)
+		if err != nil {
+			finalErr = multierror.Append(finalErr, err)
+		}
+	}
+
+	return registry.Sort(resources), finalErr
+}
+
+// ParseExport parses the resources of a directory written by Export with
+// ExportLayoutDirectory: one sub-directory per kind, itself holding a
+// sub-directory per folder for the resources living in folders. The kind and
+// folder of resources exported without their envelope are derived from this
+// layout. Files at the root of the directory, such as the provisioning
+// manifest, are ignored.
+func ParseExport(registry Registry, parser Parser, exportDir string, options ParserOptions) (Resources, error) {
+	entries, err := os.ReadDir(exportDir)
+	if err != nil {
+		return Resources{}, err
+	}
+
+	resources := NewResources()
+	var finalErr error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		handler, err := registry.GetHandler(entry.Name())
+		if err != nil {
+			finalErr = multierror.Append(finalErr, fmt.Errorf("%s: %w", filepath.Join(exportDir, entry.Name()), err))
+			continue
+		}
+
+		kindDir := filepath.Join(exportDir, entry.Name())
+		err = filepath.WalkDir(kindDir, func(path string, info fs.DirEntry, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+
+			fileOptions := options
+			fileOptions.DefaultResourceKind = handler.Kind()
+			if handler.UsesFolders() {
+				relativePath, err := filepath.Rel(kindDir, path)
+				if err != nil {
+					return err
+				}
+				if folder, _, nested := strings.Cut(filepath.ToSlash(relativePath), "/"); nested {
+					fileOptions.DefaultFolderUID = folder
+				}
+			}
+
+			parsed, err := parser.Parse(path, fileOptions)
+			if err != nil {
+				finalErr = multierror.Append(finalErr, err)
+			}
+			if err := mergeUnique(resources, parsed); err != nil {
+				finalErr = multierror.Append(finalErr, err)
+			}
+			return nil
+		})
+		if err != nil {
+			finalErr = multierror.Append(finalErr, err)
+		}
+	}
+
+	return registry.Sort(resources), finalErr
+}
+
+// addUnique adds a resource to a set of resources, unless it's already part of
+// it. A resource found more than once with a different content, for example a
+// dashboard UID used in two folders, is reported instead of silently replacing
+// the previous one.
+func addUnique(resources Resources, resource Resource) error {
+	existing, found := resources.Find(resource.Ref())
+	if !found {
+		resources.Add(resource)
+		return nil
+	}
+	if sameContent(existing, resource) {
+		return nil
+	}
+	return fmt.Errorf("resource %s is defined more than once, with different content (%s and %s)", resource.Ref(), describeSource(existing), describeSource(resource))
+}
+
+// mergeUnique adds resources to a set of resources with addUnique, reporting
+// every conflict
+func mergeUnique(resources Resources, others Resources) error {
+	var finalErr error
+	for _, resource := range others.AsList() {
+		if err := addUnique(resources, resource); err != nil {
+			finalErr = multierror.Append(finalErr, err)
+		}
+	}
+	return finalErr
+}
+
+// describeSource tells where a resource was defined: its file, its location
+// within that file and its folder, when known
+func describeSource(resource Resource) string {
+	description := resource.Source.Path
+	if resource.Source.Location != "" {
+		description += " at " + resource.Source.Location
+	}
+	if folder := resource.GetMetadata("folder"); folder != "" {
+		description += " in folder " + folder
+	}
+	if description == "" {
+		return "unknown source"
+	}
+	return strings.TrimPrefix(description, " ")
+}
+
+// sameContent compares the envelope of resources through their JSON
+// representation, as numbers aren't decoded to the same types by every format
+// parser.
+func sameContent(a, b Resource) bool {
+	envelope := func(r Resource) ([]byte, error) {
+		return json.Marshal(map[string]any{
+			"apiVersion": r.APIVersion(),
+			"metadata":   r.Body["metadata"],
+			"spec":       r.Spec(),
+		})
+	}
+	aJSON, aErr := envelope(a)
+	bJSON, bErr := envelope(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// expandPattern returns the paths matching a glob pattern. Patterns without
+// any glob meta character are returned as-is, so that missing paths are
+// reported by the parser.
+func expandPattern(pattern string) ([]string, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no files match pattern '%s'", pattern)
+	}
+
+	return matches, nil
+}
+
 type FilteredParser struct {
 	registry  Registry
 	decorated Parser
@@ -139,10 +387,18 @@ func (parser *ChainParser) Parse(resourcePath string, options ParserOptions) (Re
 		return Resources{}, err
 	}
 
+	if !stat.IsDir() && isArchive(resourcePath) {
+		return parser.parseArchive(resourcePath, options)
+	}
 	if !stat.IsDir() {
 		return parser.parseFile(resourcePath, options)
 	}
 
+	rules, err := loadIgnoreRules(resourcePath)
+	if err != nil {
+		return Resources{}, err
+	}
+
 	parsedResources := NewResources()
 	var finalErr error
 	_ = filepath.WalkDir(resourcePath, func(path string, info fs.DirEntry, err error) error {
@@ -150,7 +406,18 @@ func (parser *ChainParser) Parse(resourcePath string, options ParserOptions) (Re
 			return err
 		}
 
-		if info.IsDir() {
+		relativePath, err := filepath.Rel(resourcePath, path)
+		if err != nil {
+			return err
+		}
+		if relativePath != "." && rules.Ignored(relativePath, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() || relativePath == IgnoreFile {
 			return nil
 		}
 
@@ -164,7 +431,9 @@ func (parser *ChainParser) Parse(resourcePath string, options ParserOptions) (Re
 				return nil
 			}
 		}
-		parsedResources.Merge(r)
+		if err := mergeUnique(parsedResources, r); err != nil {
+			finalErr = multierror.Append(finalErr, err)
+		}
 
 		return nil
 	})
@@ -188,16 +457,16 @@ func (parser *ChainParser) parseFile(file string, options ParserOptions) (Resour
 	return Resources{}, NewWarning(NewUnrecognisedFormatError(file))
 }
 
-func parseAny(registry Registry, data any, resourceKind, folderUID string, source Source) (Resources, error) {
+func parseAny(registry Registry, data any, options ParserOptions, source Source) (Resources, error) {
 	if slice, ok := isSlice(data); ok {
 		resources := NewResources()
 		for _, elem := range slice {
-			parsedResources, err := parseAny(registry, elem, resourceKind, folderUID, source)
+			parsedResources, err := parseAny(registry, elem, options, source)
 			if err != nil {
 				return Resources{}, err
 			}
-			for _, resource := range parsedResources.AsList() {
-				resources.Add(resource)
+			if err := mergeUnique(resources, parsedResources); err != nil {
+				return Resources{}, err
 			}
 		}
 		return resources, nil
@@ -221,9 +490,10 @@ func parseAny(registry Registry, data any, resourceKind, folderUID string, sourc
 	}
 
 	kind := registry.Detect(data)
-	if kind == "" && resourceKind != "" {
-		kind = resourceKind
+	if kind == "" && options.DefaultResourceKind != "" {
+		kind = options.DefaultResourceKind
 	}
+	folderUID := options.DefaultFolderUID
 
 	if kind != "" {
 		handler, err := registry.GetHandler(kind)
@@ -245,7 +515,13 @@ func parseAny(registry Registry, data any, resourceKind, folderUID string, sourc
 
 		uid, err := handler.GetSpecUID(resource)
 		if err != nil {
-			return Resources{}, err
+			generator, ok := handler.(UIDGeneratorHandler)
+			if !options.DeriveUIDs || !ok {
+				return Resources{}, err
+			}
+			if uid, err = generator.GenerateUID(&resource); err != nil {
+				return Resources{}, err
+			}
 		}
 
 		resource.SetMetadata("name", uid)
@@ -409,8 +685,7 @@ func (w *walker) walkObj(obj map[string]any, path trace) error {
 		source.Location = path.Full()
 		source.Rewritable = false
 		resource.SetSource(source)
-		w.resources.Add(*resource)
-		return nil
+		return addUnique(w.resources, *resource)
 	}
 
 	keys := make([]string, 0, len(obj))
@@ -425,8 +700,11 @@ func (w *walker) walkObj(obj map[string]any, path trace) error {
 			continue
 		}
 		err := w.walkJSON(obj[key], path)
+		if primitiveErr, ok := err.(ErrorPrimitiveReached); ok {
+			return primitiveErr.WithContainingObj(obj, validateErr)
+		}
 		if err != nil {
-			return err.(ErrorPrimitiveReached).WithContainingObj(obj, validateErr)
+			return err
 		}
 	}
 
@@ -1,11 +1,20 @@
 package grizzly_test
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/grafana/grizzly/pkg/config"
 	"github.com/grafana/grizzly/pkg/grafana"
 	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/grafana/grizzly/pkg/mimir"
+	"github.com/grafana/grizzly/pkg/syntheticmonitoring"
 	"github.com/stretchr/testify/require"
 )
 
@@ -241,3 +250,639 @@ func TestParseKindDetection(t *testing.T) {
 		}
 	})
 }
+
+func TestParsePaths(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+	parseOpts := grizzly.ParserOptions{DefaultFolderUID: grafana.DefaultFolder}
+
+	t.Run("glob patterns are expanded, and identical resources are deduplicated", func(t *testing.T) {
+		req := require.New(t)
+
+		resources, err := grizzly.ParsePaths(registry, parser, []string{
+			"testdata/parsing/dashboard-with-envelope.*",
+			"testdata/parsing/datasource-with-envelope.json",
+		}, parseOpts)
+		req.NoError(err)
+
+		req.Equal(2, resources.Len())
+		first := resources.First()
+		req.Equal("Datasource", first.Kind(), "resources are expected to be sorted")
+	})
+
+	t.Run("patterns matching nothing are reported", func(t *testing.T) {
+		req := require.New(t)
+
+		_, err := grizzly.ParsePaths(registry, parser, []string{"testdata/parsing/*.unknown"}, parseOpts)
+		req.ErrorContains(err, "no files match pattern 'testdata/parsing/*.unknown'")
+	})
+
+	t.Run("conflicting duplicates are reported", func(t *testing.T) {
+		req := require.New(t)
+		dir := t.TempDir()
+		for i, title := range []string{"first", "second"} {
+			content := fmt.Sprintf("apiVersion: grizzly.grafana.com/v1alpha1\nkind: Dashboard\nmetadata:\n  name: dup\n  folder: general\nspec:\n  uid: dup\n  title: %s\n", title)
+			req.NoError(os.WriteFile(filepath.Join(dir, fmt.Sprintf("dashboard-%d.yaml", i)), []byte(content), 0644))
+		}
+
+		_, err := grizzly.ParsePaths(registry, parser, []string{filepath.Join(dir, "*.yaml")}, parseOpts)
+		req.ErrorContains(err, "resource Dashboard.dup is defined more than once, with different content")
+	})
+
+	t.Run("dashboards sharing a UID across folders are reported", func(t *testing.T) {
+		req := require.New(t)
+		dir := t.TempDir()
+		content := `local dashboard(folder) = {
+  apiVersion: 'grizzly.grafana.com/v1alpha1',
+  kind: 'Dashboard',
+  metadata: { name: 'dup', folder: folder },
+  spec: { uid: 'dup', title: folder },
+};
+{ team_a: dashboard('team-a'), team_b: dashboard('team-b') }
+`
+		req.NoError(os.WriteFile(filepath.Join(dir, "dashboards.jsonnet"), []byte(content), 0644))
+
+		_, err := grizzly.ParsePaths(registry, parser, []string{filepath.Join(dir, "dashboards.jsonnet")}, parseOpts)
+		req.ErrorContains(err, "resource Dashboard.dup is defined more than once, with different content")
+		req.ErrorContains(err, "dashboards.jsonnet at .team_a in folder team-a")
+		req.ErrorContains(err, "dashboards.jsonnet at .team_b in folder team-b")
+	})
+
+	t.Run("files excluded by the ignore file are skipped", func(t *testing.T) {
+		req := require.New(t)
+		dir := t.TempDir()
+		req.NoError(os.MkdirAll(filepath.Join(dir, "vendor"), 0755))
+		for _, name := range []string{"kept.yaml", "_draft.yaml", "vendor/vendored.yaml"} {
+			uid := strings.TrimSuffix(filepath.Base(name), ".yaml")
+			content := fmt.Sprintf("apiVersion: grizzly.grafana.com/v1alpha1\nkind: Dashboard\nmetadata:\n  name: %[1]s\n  folder: general\nspec:\n  uid: %[1]s\n", uid)
+			req.NoError(os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		}
+		req.NoError(os.WriteFile(filepath.Join(dir, grizzly.IgnoreFile), []byte("vendor/\n**/_*.yaml\n"), 0644))
+
+		resources, err := grizzly.ParsePaths(registry, parser, []string{dir}, parseOpts)
+		req.NoError(err)
+		req.Equal(1, resources.Len())
+		first := resources.First()
+		req.Equal("kept", first.Name())
+	})
+}
+
+func TestParseExport(t *testing.T) {
+	req := require.New(t)
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+
+	dir := t.TempDir()
+	req.NoError(os.MkdirAll(filepath.Join(dir, "Dashboard", "team-a"), 0755))
+	req.NoError(os.WriteFile(filepath.Join(dir, "Dashboard", "team-a", "latency.json"), []byte(`{"uid": "latency", "title": "Latency"}`), 0644))
+	req.NoError(os.MkdirAll(filepath.Join(dir, "Datasource"), 0755))
+	content, err := os.ReadFile("testdata/parsing/datasource-with-envelope.json")
+	req.NoError(err)
+	req.NoError(os.WriteFile(filepath.Join(dir, "Datasource", "prometheus.json"), content, 0644))
+	req.NoError(os.WriteFile(filepath.Join(dir, grizzly.ProvisioningManifestFile), []byte("apiVersion: 1\n"), 0644))
+
+	resources, err := grizzly.ParseExport(registry, parser, dir, grizzly.ParserOptions{})
+	req.NoError(err)
+	req.Equal(2, resources.Len())
+
+	dashboard, found := resources.Find(grizzly.NewResourceRef("Dashboard", "latency"))
+	req.True(found)
+	req.Equal("team-a", dashboard.GetMetadata("folder"), "the folder is derived from the directory layout")
+	_, found = resources.Find(grizzly.NewResourceRef("Datasource", "prometheus"))
+	req.True(found)
+
+	req.NoError(os.MkdirAll(filepath.Join(dir, "Unknown"), 0755))
+	_, err = grizzly.ParseExport(registry, parser, dir, grizzly.ParserOptions{})
+	req.Error(err, "directories of unknown kinds are reported")
+}
+
+func TestParseYAMLUnknownKinds(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parseOpts := grizzly.ParserOptions{DefaultFolderUID: grafana.DefaultFolder}
+
+	t.Run("documents of unknown kinds are skipped", func(t *testing.T) {
+		req := require.New(t)
+
+		parser := grizzly.DefaultParser(registry, nil, nil)
+		resources, err := parser.Parse("testdata/parsing/mixed-kinds.yaml", parseOpts)
+		req.NoError(err)
+		req.Equal(2, resources.Len())
+	})
+
+	t.Run("strict parsing fails on unknown kinds", func(t *testing.T) {
+		req := require.New(t)
+
+		parser := grizzly.DefaultParser(registry, nil, nil, grizzly.ParserStrict(true))
+		_, err := parser.Parse("testdata/parsing/mixed-kinds.yaml", parseOpts)
+		req.ErrorContains(err, "document 2: unknown kind Widget")
+	})
+}
+
+func TestParseYAMLAnchors(t *testing.T) {
+	req := require.New(t)
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+
+	content := `apiVersion: grizzly.grafana.com/v1alpha1
+kind: Dashboard
+metadata:
+  name: anchors
+  folder: general
+spec:
+  uid: anchors
+  title: Anchors
+  schemaVersion: 39
+  defaults: &panel
+    type: timeseries
+    gridPos: {w: 12, h: 8}
+  panels:
+    - *panel
+    - <<: *panel
+      type: stat
+`
+	file := filepath.Join(t.TempDir(), "anchors.yaml")
+	req.NoError(os.WriteFile(file, []byte(content), 0644))
+
+	resources, err := parser.Parse(file, grizzly.ParserOptions{})
+	req.NoError(err)
+	dashboard := resources.First()
+	req.Equal([]any{
+		map[string]any{"type": "timeseries", "gridPos": map[string]any{"w": 12, "h": 8}},
+		map[string]any{"type": "stat", "gridPos": map[string]any{"w": 12, "h": 8}},
+	}, dashboard.GetSpecValue("panels"), "aliases and merge keys are resolved")
+}
+
+func TestParseOverlays(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+
+	dir := t.TempDir()
+	base := `apiVersion: grizzly.grafana.com/v1alpha1
+kind: Dashboard
+metadata:
+  name: base
+  folder: general
+spec:
+  uid: base
+  title: Base
+  schemaVersion: 39
+  tags: [base]
+  time: {from: now-6h, to: now}
+  refresh: 1m
+`
+	req := require.New(t)
+	req.NoError(os.WriteFile(filepath.Join(dir, "base.yaml"), []byte(base), 0644))
+
+	t.Run("overlays are merged onto resources", func(t *testing.T) {
+		req := require.New(t)
+		overlay := `apiVersion: grizzly.grafana.com/v1alpha1
+kind: Dashboard
+metadata:
+  name: base
+  folder: team-a
+spec:
+  title: Team A
+  tags: [team-a]
+  time: {from: now-1h}
+  refresh: null
+`
+		overlayFile := filepath.Join(dir, "team-a.overlay.yaml")
+		req.NoError(os.WriteFile(overlayFile, []byte(overlay), 0644))
+
+		resources, err := grizzly.ParsePaths(registry, parser, []string{filepath.Join(dir, "base.yaml")}, grizzly.ParserOptions{Overlays: []string{overlayFile}})
+		req.NoError(err)
+		req.Equal(1, resources.Len())
+
+		dashboard := resources.First()
+		req.Equal("team-a", dashboard.GetMetadata("folder"))
+		req.Equal(map[string]any{
+			"uid":           "base",
+			"title":         "Team A",
+			"schemaVersion": 39,
+			"tags":          []any{"team-a"},
+			"time":          map[string]any{"from": "now-1h", "to": "now"},
+		}, dashboard.Spec())
+		req.False(dashboard.Source.Rewritable, "patched resources can't be written back to their file")
+	})
+
+	t.Run("overlays must patch a resource", func(t *testing.T) {
+		req := require.New(t)
+		overlay := "apiVersion: grizzly.grafana.com/v1alpha1\nkind: Dashboard\nmetadata:\n  name: missing\nspec:\n  title: Missing\n"
+		overlayFile := filepath.Join(dir, "missing.overlay.yaml")
+		req.NoError(os.WriteFile(overlayFile, []byte(overlay), 0644))
+
+		_, err := grizzly.ParsePaths(registry, parser, []string{filepath.Join(dir, "base.yaml")}, grizzly.ParserOptions{Overlays: []string{overlayFile}})
+		req.ErrorContains(err, "overlay Dashboard.missing")
+		req.ErrorContains(err, "no resource to patch")
+	})
+}
+
+func TestParseTransforms(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+
+	dir := t.TempDir()
+	resources := `apiVersion: grizzly.grafana.com/v1alpha1
+kind: Dashboard
+metadata:
+  name: base
+  folder: general
+spec:
+  uid: base
+  title: Base
+  tags: [base]
+---
+apiVersion: grizzly.grafana.com/v1alpha1
+kind: DashboardFolder
+metadata:
+  name: general
+spec:
+  uid: general
+  title: General
+`
+	req := require.New(t)
+	base := filepath.Join(dir, "base.yaml")
+	req.NoError(os.WriteFile(base, []byte(resources), 0644))
+
+	t.Run("transforms rewrite each resource", func(t *testing.T) {
+		req := require.New(t)
+		transform := `function(resource)
+  if resource.kind == 'Dashboard'
+  then resource + { spec+: { tags+: ['production'] } }
+  else resource
+`
+		transformFile := filepath.Join(dir, "env.jsonnet")
+		req.NoError(os.WriteFile(transformFile, []byte(transform), 0644))
+
+		resources, err := grizzly.ParsePaths(registry, parser, []string{base}, grizzly.ParserOptions{Transforms: []string{transformFile}})
+		req.NoError(err)
+		req.Equal(2, resources.Len())
+
+		dashboard, found := resources.Find(grizzly.NewResourceRef("Dashboard", "base"))
+		req.True(found)
+		req.Equal([]any{"base", "production"}, dashboard.GetSpecValue("tags"))
+		req.False(dashboard.Source.Rewritable, "transformed resources can't be written back to their file")
+
+		folder, found := resources.Find(grizzly.NewResourceRef("DashboardFolder", "general"))
+		req.True(found)
+		req.Equal("General", folder.GetSpecValue("title"))
+		req.True(folder.Source.Rewritable, "resources left as is can still be written back to their file")
+	})
+
+	t.Run("transforms may not rename resources", func(t *testing.T) {
+		req := require.New(t)
+		transform := "function(resource) resource + { metadata+: { name: 'renamed' } }\n"
+		transformFile := filepath.Join(dir, "rename.jsonnet")
+		req.NoError(os.WriteFile(transformFile, []byte(transform), 0644))
+
+		_, err := grizzly.ParsePaths(registry, parser, []string{base}, grizzly.ParserOptions{Transforms: []string{transformFile}})
+		req.ErrorContains(err, "transform "+transformFile)
+		req.ErrorContains(err, "may not change the kind or name")
+	})
+}
+
+func TestParseInputFormat(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parseOpts := grizzly.ParserOptions{DefaultFolderUID: grafana.DefaultFolder}
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "dashboard.txt")
+	content := `{"apiVersion": "grizzly.grafana.com/v1alpha1", "kind": "Dashboard", "metadata": {"name": "plain", "folder": "general"}, "spec": {"uid": "plain"}}`
+	require.NoError(t, os.WriteFile(file, []byte(content), 0644))
+
+	t.Run("files are parsed according to their extension by default", func(t *testing.T) {
+		req := require.New(t)
+
+		parser := grizzly.DefaultParser(registry, nil, nil)
+		_, err := parser.Parse(file, parseOpts)
+		req.Error(err)
+	})
+
+	t.Run("the input format can be forced", func(t *testing.T) {
+		req := require.New(t)
+
+		parser := grizzly.DefaultParser(registry, nil, nil, grizzly.ParserFormat("json"))
+		resources, err := parser.Parse(file, parseOpts)
+		req.NoError(err)
+		req.Equal(1, resources.Len())
+		first := resources.First()
+		req.Equal("plain", first.Name())
+	})
+}
+
+func TestParseArchives(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parseOpts := grizzly.ParserOptions{DefaultFolderUID: grafana.DefaultFolder}
+
+	entries := map[string]string{
+		"dashboards/first.yaml": `apiVersion: grizzly.grafana.com/v1alpha1
+kind: Dashboard
+metadata:
+  name: first
+  folder: general
+spec:
+  uid: first
+`,
+		"dashboards/second.json": `{"apiVersion": "grizzly.grafana.com/v1alpha1", "kind": "Dashboard", "metadata": {"name": "second", "folder": "general"}, "spec": {"uid": "second"}}`,
+	}
+	jsonnetEntries := map[string]string{
+		"dashboards/third.jsonnet": `{}`,
+	}
+
+	writeTarball := func(t *testing.T, file string, entries map[string]string) {
+		f, err := os.Create(file)
+		require.NoError(t, err)
+		defer f.Close()
+		gzipWriter := gzip.NewWriter(f)
+		tarWriter := tar.NewWriter(gzipWriter)
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "dashboards/", Typeflag: tar.TypeDir, Mode: 0755}))
+		for name, content := range entries {
+			require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))}))
+			_, err := tarWriter.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tarWriter.Close())
+		require.NoError(t, gzipWriter.Close())
+	}
+	writeZip := func(t *testing.T, file string, entries map[string]string) {
+		f, err := os.Create(file)
+		require.NoError(t, err)
+		defer f.Close()
+		zipWriter := zip.NewWriter(f)
+		_, err = zipWriter.Create("dashboards/")
+		require.NoError(t, err)
+		for name, content := range entries {
+			w, err := zipWriter.Create(name)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, zipWriter.Close())
+	}
+
+	archives := map[string]func(t *testing.T, file string, entries map[string]string){
+		"dashboards.tar.gz": writeTarball,
+		"dashboards.tgz":    writeTarball,
+		"dashboards.zip":    writeZip,
+	}
+	for name, write := range archives {
+		t.Run(name, func(t *testing.T) {
+			req := require.New(t)
+			dir := t.TempDir()
+
+			file := filepath.Join(dir, name)
+			write(t, file, entries)
+			parser := grizzly.DefaultParser(registry, nil, nil)
+			resources, err := parser.Parse(file, parseOpts)
+			req.NoError(err)
+			req.Equal(2, resources.Len())
+			for _, uid := range []string{"first", "second"} {
+				resource, found := resources.Find(grizzly.NewResourceRef("Dashboard", uid))
+				req.True(found, uid)
+				req.Equal(uid, resource.Spec()["uid"])
+				req.False(resource.Source.Rewritable, "archive entries can't be written back")
+				req.True(strings.HasPrefix(resource.Source.Path, file), resource.Source.Path)
+			}
+
+			withJsonnet := filepath.Join(dir, "jsonnet-"+name)
+			write(t, withJsonnet, jsonnetEntries)
+			_, err = parser.Parse(withJsonnet, parseOpts)
+			req.ErrorContains(err, "this format can't be read from archives")
+		})
+	}
+}
+
+func TestParseDeriveUIDs(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "dashboard.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"title": "Team A: Overview", "panels": []}`), 0644))
+
+	parseOpts := grizzly.ParserOptions{
+		DefaultResourceKind: "Dashboard",
+		DefaultFolderUID:    grafana.DefaultFolder,
+	}
+
+	t.Run("dashboards without UID are rejected by default", func(t *testing.T) {
+		_, err := parser.Parse(file, parseOpts)
+		require.ErrorContains(t, err, "UID not specified")
+	})
+
+	t.Run("UIDs can be derived from titles", func(t *testing.T) {
+		req := require.New(t)
+
+		opts := parseOpts
+		opts.DeriveUIDs = true
+		resources, err := parser.Parse(file, opts)
+		req.NoError(err)
+		req.Equal(1, resources.Len())
+
+		first := resources.First()
+		req.Equal("team-a-overview", first.Name())
+		req.Equal("team-a-overview", first.Spec()["uid"])
+	})
+}
+
+func TestParseJsonnetAlerting(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+	req := require.New(t)
+
+	file := filepath.Join(t.TempDir(), "alerting.jsonnet")
+	req.NoError(os.WriteFile(file, []byte(`{
+  grafanaAlertRules:: {
+    'high-latency': { title: 'High latency', folderUID: 'alerts', ruleGroup: 'latency', condition: 'A' },
+  },
+  grafanaMuteTimings:: {
+    weekends: { time_intervals: [{ weekdays: ['saturday', 'sunday'] }] },
+    nights: { time_intervals: [{ times: [{ start_time: '22:00', end_time: '06:00' }] }] },
+  },
+  grafanaNotificationPolicy:: {
+    receiver: 'grafana-default-email',
+    routes: [{ receiver: 'oncall', mute_time_intervals: ['weekends'] }],
+  },
+}`), 0644))
+
+	resources, err := parser.Parse(file, grizzly.ParserOptions{DefaultFolderUID: grafana.DefaultFolder})
+	req.NoError(err)
+	req.Equal(4, resources.Len())
+
+	rule, found := resources.Find(grizzly.NewResourceRef(grafana.AlertRuleKind, "high-latency"))
+	req.True(found)
+	req.Equal("high-latency", rule.Spec()["uid"])
+	req.Equal("High latency", rule.Spec()["title"])
+
+	weekends, found := resources.Find(grizzly.NewResourceRef(grafana.AlertMuteTimingKind, "weekends"))
+	req.True(found)
+	req.Equal("weekends", weekends.Spec()["name"])
+
+	policy, found := resources.Find(grizzly.NewResourceRef(grafana.AlertNotificationPolicyKind, grafana.GlobalAlertNotificationPolicyName))
+	req.True(found)
+	req.Equal("grafana-default-email", policy.Spec()["receiver"])
+}
+
+func TestParseJsonnetDashboardKeys(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parseOpts := grizzly.ParserOptions{DefaultFolderUID: grafana.DefaultFolder}
+
+	// the custom key isn't hidden, as often in mixins not following the
+	// grafanaDashboards convention
+	file := filepath.Join(t.TempDir(), "mixin.jsonnet")
+	require.NoError(t, os.WriteFile(file, []byte(`{
+  dashboards: {
+    'overview.json': { uid: 'overview', title: 'Overview' },
+  },
+  grafanaDashboards:: {
+    'legacy.json': { uid: 'legacy', title: 'Legacy' },
+  },
+}`), 0644))
+
+	t.Run("dashboards are read from grafanaDashboards by default", func(t *testing.T) {
+		parser := grizzly.DefaultParser(registry, nil, nil)
+		_, err := parser.Parse(file, parseOpts)
+		require.ErrorContains(t, err, "found invalid object (at .dashboards.overview.json)")
+	})
+
+	t.Run("dashboards can be read from other keys", func(t *testing.T) {
+		req := require.New(t)
+
+		parser := grizzly.DefaultParser(registry, nil, nil, grizzly.ParserJsonnetDashboardKeys([]string{"dashboards", "grafanaDashboards"}))
+		resources, err := parser.Parse(file, parseOpts)
+		req.NoError(err)
+		req.Equal(2, resources.Len())
+
+		overview, found := resources.Find(grizzly.NewResourceRef("Dashboard", "overview"))
+		req.True(found)
+		req.Equal("Overview", overview.Spec()["title"])
+		_, found = resources.Find(grizzly.NewResourceRef("Dashboard", "legacy"))
+		req.True(found)
+	})
+}
+
+func TestParseJsonnetMixin(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}, mimir.NewProvider(&config.MimirConfig{})})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+	req := require.New(t)
+
+	// the layout of monitoring mixins: hidden dashboards, alerts and rules
+	file := filepath.Join(t.TempDir(), "mixin.jsonnet")
+	req.NoError(os.WriteFile(file, []byte(`{
+  grafanaDashboards+:: {
+    'overview.json': { uid: 'overview', title: 'Overview' },
+  },
+  prometheusAlerts+:: {
+    groups: [{ name: 'alerts', rules: [{ alert: 'Down', expr: 'up == 0' }] }],
+  },
+  prometheusRules+:: {
+    groups: [{ name: 'rules', rules: [{ record: 'job:up:sum', expr: 'sum by (job) (up)' }] }],
+  },
+}`), 0644))
+
+	resources, err := parser.Parse(file, grizzly.ParserOptions{DefaultFolderUID: grafana.DefaultFolder})
+	req.NoError(err)
+	req.Equal(3, resources.Len())
+
+	_, found := resources.Find(grizzly.NewResourceRef(grafana.DashboardKind, "overview"))
+	req.True(found)
+	for _, group := range []string{"alerts", "rules"} {
+		resource, found := resources.Find(grizzly.NewResourceRef(mimir.PrometheusRuleGroupKind, group))
+		req.True(found, "rule group %s", group)
+		req.Equal("grizzly_rules", resource.GetMetadata("namespace"))
+	}
+}
+
+func TestParseJsonnetPlaylists(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+	req := require.New(t)
+
+	file := filepath.Join(t.TempDir(), "playlists.jsonnet")
+	req.NoError(os.WriteFile(file, []byte(`{
+  grafanaPlaylists:: {
+    'noc-wall': { name: 'NOC wall display', interval: '5m', items: [{ type: 'dashboard_by_tag', value: 'noc' }] },
+  },
+}`), 0644))
+
+	resources, err := parser.Parse(file, grizzly.ParserOptions{})
+	req.NoError(err)
+	req.Equal(1, resources.Len())
+
+	playlist, found := resources.Find(grizzly.NewResourceRef(grafana.PlaylistKind, "noc-wall"))
+	req.True(found)
+	req.Equal("noc-wall", playlist.Spec()["uid"])
+	req.Equal("NOC wall display", playlist.Spec()["name"])
+}
+
+func TestParseJsonnetDashboardPermissions(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{&grafana.Provider{}})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+	req := require.New(t)
+
+	file := filepath.Join(t.TempDir(), "permissions.jsonnet")
+	req.NoError(os.WriteFile(file, []byte(`{
+  grafanaDashboardPermissions:: {
+    overview: [{ team: 'SRE', permission: 'Admin' }],
+    latency: { permissions: [{ role: 'Viewer', permission: 'View' }] },
+  },
+}`), 0644))
+
+	resources, err := parser.Parse(file, grizzly.ParserOptions{})
+	req.NoError(err)
+	req.Equal(2, resources.Len())
+
+	overview, found := resources.Find(grizzly.NewResourceRef(grafana.DashboardPermissionKind, "overview"))
+	req.True(found)
+	req.Equal("overview", overview.Spec()["uid"])
+	req.Equal([]any{map[string]any{"team": "SRE", "permission": "Admin"}}, overview.Spec()["permissions"])
+
+	latency, found := resources.Find(grizzly.NewResourceRef(grafana.DashboardPermissionKind, "latency"))
+	req.True(found)
+	req.Equal([]any{map[string]any{"role": "Viewer", "permission": "View"}}, latency.Spec()["permissions"])
+}
+
+func TestParseJsonnetReports(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{grafana.NewProvider(&config.GrafanaConfig{Reports: true})})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+	req := require.New(t)
+
+	file := filepath.Join(t.TempDir(), "reports.jsonnet")
+	req.NoError(os.WriteFile(file, []byte(`{
+  grafanaReports:: {
+    'weekly-overview': { recipients: 'team@example.com', formats: ['pdf'] },
+  },
+}`), 0644))
+
+	resources, err := parser.Parse(file, grizzly.ParserOptions{})
+	req.NoError(err)
+	req.Equal(1, resources.Len())
+
+	report := resources.First()
+	req.Equal("Report", report.Kind())
+	req.Equal("weekly-overview", report.Name())
+	req.Equal("weekly-overview", report.Spec()["name"])
+}
+
+func TestParseJsonnetSyntheticMonitoringChecks(t *testing.T) {
+	registry := grizzly.NewRegistry([]grizzly.Provider{syntheticmonitoring.NewProvider(&config.SyntheticMonitoringConfig{})})
+	parser := grizzly.DefaultParser(registry, nil, nil)
+	req := require.New(t)
+
+	file := filepath.Join(t.TempDir(), "checks.jsonnet")
+	req.NoError(os.WriteFile(file, []byte(`{
+  syntheticMonitoringChecks:: {
+    website: { job: 'website', target: 'https://example.com', settings: { http: {} } },
+  },
+}`), 0644))
+
+	resources, err := parser.Parse(file, grizzly.ParserOptions{})
+	req.NoError(err)
+	req.Equal(1, resources.Len())
+
+	check := resources.First()
+	req.Equal(syntheticmonitoring.SyntheticMonitoringCheckKind, check.Kind())
+	req.Equal("website", check.Name())
+	req.Equal("http", check.GetMetadata("type"))
+	req.Equal("https://example.com", check.Spec()["target"])
+}
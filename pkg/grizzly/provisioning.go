@@ -0,0 +1,73 @@
+package grizzly
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProvisioningManifestFile is the name of the dashboard provisioning manifest
+// written next to exported resources
+const ProvisioningManifestFile = "provisioning.yaml"
+
+// provisionedKind is the kind of the resources read by Grafana's file-based
+// provisioning: only dashboards can be provisioned from plain files.
+const provisionedKind = "Dashboard"
+
+type provisioningManifest struct {
+	APIVersion int                    `yaml:"apiVersion"`
+	Providers  []provisioningProvider `yaml:"providers"`
+}
+
+type provisioningProvider struct {
+	Name    string                      `yaml:"name"`
+	Type    string                      `yaml:"type"`
+	Options provisioningProviderOptions `yaml:"options"`
+}
+
+type provisioningProviderOptions struct {
+	Path                      string `yaml:"path"`
+	FoldersFromFilesStructure bool   `yaml:"foldersFromFilesStructure"`
+}
+
+// validateProvisioningExport checks that exported dashboards can be read by
+// Grafana's file-based provisioning, which expects plain dashboard JSON
+func validateProvisioningExport(opts ExportOptions) error {
+	if opts.Layout != "" && opts.Layout != ExportLayoutDirectory {
+		return fmt.Errorf("a provisioning manifest can only be written with the %s layout", ExportLayoutDirectory)
+	}
+	if opts.OutputFormat != formatJSON || !opts.OnlySpec {
+		return fmt.Errorf("a provisioning manifest requires dashboards to be exported as JSON, without envelope (-o json --only-spec)")
+	}
+	return nil
+}
+
+// writeProvisioningManifest writes a dashboard provider reading the
+// dashboards exported to exportDir, with a folder per sub-directory
+func writeProvisioningManifest(exportDir string) error {
+	dashboardsDir, err := filepath.Abs(filepath.Join(exportDir, provisionedKind))
+	if err != nil {
+		return err
+	}
+
+	manifest := provisioningManifest{
+		APIVersion: 1,
+		Providers: []provisioningProvider{
+			{
+				Name: "grizzly",
+				Type: "file",
+				Options: provisioningProviderOptions{
+					Path:                      dashboardsDir,
+					FoldersFromFilesStructure: true,
+				},
+			},
+		},
+	}
+
+	content, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return WriteFile(filepath.Join(exportDir, ProvisioningManifestFile), content)
+}
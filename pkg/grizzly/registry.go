@@ -1,6 +1,7 @@
 package grizzly
 
 import (
+	"context"
 	"fmt"
 	"net/http/httputil"
 	"strings"
@@ -15,6 +16,8 @@ type ProviderStatus struct {
 	// Online indicates that the configuration could be used successfully to perform requests.
 	Online       bool
 	OnlineReason string
+	// OnlineDetails describes the connection, when online (ex: the authenticated user).
+	OnlineDetails string
 }
 
 // Provider describes a single Endpoint Provider
@@ -23,6 +26,8 @@ type Provider interface {
 	Group() string
 	Version() string
 	APIVersion() string
+	// GetHandlers returns the handlers of the provider, in the order their
+	// resources must be applied: a kind comes after the kinds it references.
 	GetHandlers() []Handler
 	Validate() error
 
@@ -37,11 +42,32 @@ type ProxyProvider interface {
 	SetupProxy() (*httputil.ReverseProxy, string, error)
 }
 
+// ContextProvider describes a provider whose remote calls can be bound to a
+// context, so that they are aborted once it is done
+type ContextProvider interface {
+	WithContext(ctx context.Context) Provider
+}
+
+// BindableHandler describes a handler holding settings of its own (ex: fields
+// to ignore when comparing resources), which must be kept when it is bound to
+// another instance of its provider, such as one bound to a context
+type BindableHandler interface {
+	// WithProvider returns a copy of the handler, with the same settings,
+	// whose remote calls go through the given provider
+	WithProvider(provider Provider) Handler
+}
+
 // Registry records providers
 type Registry struct {
 	Providers    []Provider
 	Handlers     map[string]Handler
 	HandlerOrder []Handler
+	// cache holds the remote resources retrieved during the run, when
+	// enabled with WithRemoteCache
+	cache *RemoteCache
+	// offline holds the resources standing in for the remote endpoints, when
+	// set with WithOfflineRemote
+	offline *Resources
 }
 
 // NewRegistry returns an empty registry
@@ -61,6 +87,91 @@ func NewRegistry(providers []Provider) Registry {
 	return registry
 }
 
+// WithContext returns a copy of the registry whose handlers abort their remote
+// calls once the context is done, for the providers supporting it. Handlers
+// implementing BindableHandler keep their settings. Other handlers, including
+// the ones registered on their own, are kept as is.
+func (r Registry) WithContext(ctx context.Context) Registry {
+	if r.cache != nil {
+		ctx = context.WithValue(ctx, remoteCacheKey{}, r.cache)
+	}
+	bound := map[string]Handler{}
+	boundProviders := map[string]Provider{}
+	providers := make([]Provider, 0, len(r.Providers))
+	for _, provider := range r.Providers {
+		if contextProvider, ok := provider.(ContextProvider); ok {
+			provider = contextProvider.WithContext(ctx)
+			for _, handler := range provider.GetHandlers() {
+				bound[handler.Kind()] = handler
+				boundProviders[handler.Kind()] = provider
+			}
+		}
+		providers = append(providers, provider)
+	}
+	if len(bound) == 0 {
+		return r
+	}
+
+	// handlers holding settings are copied along with them, instead of
+	// being replaced, and only once, as Handlers and HandlerOrder share them
+	rebound := map[string]Handler{}
+	rebind := func(handler Handler) Handler {
+		boundHandler, ok := bound[handler.Kind()]
+		if !ok {
+			return handler
+		}
+		if bindable, ok := handler.(BindableHandler); ok {
+			if _, done := rebound[handler.Kind()]; !done {
+				rebound[handler.Kind()] = bindable.WithProvider(boundProviders[handler.Kind()])
+			}
+			return rebound[handler.Kind()]
+		}
+		return boundHandler
+	}
+
+	registry := Registry{
+		Providers:    providers,
+		Handlers:     make(map[string]Handler, len(r.Handlers)),
+		HandlerOrder: make([]Handler, 0, len(r.HandlerOrder)),
+		cache:        r.cache,
+		offline:      r.offline,
+	}
+	for kind, handler := range r.Handlers {
+		registry.Handlers[kind] = rebind(handler)
+	}
+	for _, handler := range r.HandlerOrder {
+		registry.HandlerOrder = append(registry.HandlerOrder, rebind(handler))
+	}
+	return registry
+}
+
+// WithRemoteCache returns a copy of the registry caching the remote resources
+// it retrieves, so that a resource looked up several times is only fetched
+// once. The cache starts empty, and is shared by the copies later made from
+// the returned registry. It isn't used by default, as cached resources become
+// stale if they are changed by someone else during the run.
+func (r Registry) WithRemoteCache() Registry {
+	r.cache = NewRemoteCache()
+	return r
+}
+
+// RegisterHandler adds a handler to the registry, making resources of its kind
+// available to all workflows (parsing, diff, apply, ...). It allows programs
+// using Grizzly as a library to support their own resource kinds.
+// Handlers must be registered before the registry is handed to workflows, as
+// these receive a copy of it.
+func (r *Registry) RegisterHandler(handler Handler) error {
+	if r.Handlers == nil {
+		r.Handlers = map[string]Handler{}
+	}
+	if _, exists := r.Handlers[handler.Kind()]; exists {
+		return fmt.Errorf("a handler for %s is already registered", handler.Kind())
+	}
+	r.Handlers[handler.Kind()] = handler
+	r.HandlerOrder = append(r.HandlerOrder, handler)
+	return nil
+}
+
 // GetHandler returns a single provider based upon a JSON path
 func (r *Registry) GetHandler(kind string) (Handler, error) {
 	handler, exists := r.Handlers[kind]
@@ -114,6 +225,9 @@ func (r *Registry) ResourceMatchesTarget(kind string, uid string, targets []stri
 	return false
 }
 
+// Sort orders resources by kind, following the order in which handlers were
+// registered, so that resources come after the ones they may reference.
+// Resources of kinds without a handler are kept last.
 func (r *Registry) Sort(resources Resources) Resources {
 	sorted := NewResources()
 	resourceByKind := resources.GroupByKind()
@@ -122,10 +236,92 @@ func (r *Registry) Sort(resources Resources) Resources {
 		handlerResources := resourceByKind[handler.Kind()]
 		sorted.Merge(handler.Sort(handlerResources))
 	}
+	sorted.Merge(resources.Filter(func(resource Resource) bool {
+		_, known := r.Handlers[resource.Kind()]
+		return !known
+	}))
 
 	return sorted
 }
 
+// FilterKinds returns the resources of the given kinds, matched regardless of
+// case. No kinds keeps all resources, and kinds without a handler are an error.
+func (r *Registry) FilterKinds(resources Resources, kinds []string) (Resources, error) {
+	if len(kinds) == 0 {
+		return resources, nil
+	}
+
+	selected := map[string]bool{}
+	for _, kind := range kinds {
+		handler, found := r.findHandler(kind)
+		if !found {
+			known := make([]string, 0, len(r.HandlerOrder))
+			for _, handler := range r.HandlerOrder {
+				known = append(known, handler.Kind())
+			}
+			return NewResources(), fmt.Errorf("unknown kind '%s', expected one of: %s", kind, strings.Join(known, ", "))
+		}
+		selected[handler.Kind()] = true
+	}
+
+	return resources.Filter(func(resource Resource) bool {
+		return selected[resource.Kind()]
+	}), nil
+}
+
+// FilterRefs returns the resources matching the given references, along with
+// the references matching none. A reference is either a UID, matching
+// resources of any kind, or a UID prefixed with its kind (ex: Dashboard.abc123
+// or Dashboard/abc123). No references keeps all resources.
+func (r *Registry) FilterRefs(resources Resources, refs []string) (Resources, []string) {
+	if len(refs) == 0 {
+		return resources, nil
+	}
+
+	matched := make([]bool, len(refs))
+	filtered := resources.Filter(func(resource Resource) bool {
+		found := false
+		for i, ref := range refs {
+			if r.refMatches(ref, resource) {
+				matched[i] = true
+				found = true
+			}
+		}
+		return found
+	})
+
+	var unmatched []string
+	for i, ref := range refs {
+		if !matched[i] {
+			unmatched = append(unmatched, ref)
+		}
+	}
+	return filtered, unmatched
+}
+
+// refMatches checks whether a resource is the one referenced
+func (r *Registry) refMatches(ref string, resource Resource) bool {
+	if ref == resource.Name() {
+		return true
+	}
+	separator := strings.IndexAny(ref, "./")
+	if separator < 0 {
+		return false
+	}
+	handler, found := r.findHandler(ref[:separator])
+	return found && handler.Kind() == resource.Kind() && ref[separator+1:] == resource.Name()
+}
+
+// findHandler returns the handler of a kind, regardless of case
+func (r *Registry) findHandler(kind string) (Handler, bool) {
+	for _, handler := range r.HandlerOrder {
+		if strings.EqualFold(handler.Kind(), kind) {
+			return handler, true
+		}
+	}
+	return nil, false
+}
+
 func (r *Registry) Detect(data any) string {
 	m, ok := data.(map[string]any)
 	if !ok {
@@ -0,0 +1,112 @@
+package grizzly_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterHandler(t *testing.T) {
+	t.Run("registered handlers are used by workflows", func(t *testing.T) {
+		req := require.New(t)
+		provider := newFakeProvider()
+		registry := grizzly.NewRegistry(nil)
+
+		req.NoError(registry.RegisterHandler(provider.handler))
+
+		handler, err := registry.GetHandler(fakeKind)
+		req.NoError(err)
+		req.Same(provider.handler, handler)
+
+		resources := grizzly.NewResources(provider.resource("new", map[string]any{"uid": "new"}))
+		req.NoError(grizzly.Apply(context.Background(), registry, resources, grizzly.ApplyOptions{}, &fakeRecorder{}))
+		req.Equal([]string{"new"}, provider.handler.added)
+	})
+
+	t.Run("a kind can only be registered once", func(t *testing.T) {
+		req := require.New(t)
+		provider := newFakeProvider()
+		registry := provider.registry()
+
+		err := registry.RegisterHandler(newFakeProvider().handler)
+		req.ErrorContains(err, "a handler for Fake is already registered")
+	})
+}
+
+func TestRegistrySort(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider()
+	registry := provider.registry()
+
+	unknown, err := grizzly.NewResource("example.com/v1", "Unknown", "other", map[string]any{})
+	req.NoError(err)
+	resources := grizzly.NewResources(unknown, provider.resource("a", map[string]any{"uid": "a"}))
+
+	refs := []string{}
+	for _, resource := range registry.Sort(resources).AsList() {
+		refs = append(refs, resource.Ref().String())
+	}
+	req.Equal([]string{"Fake.a", "Unknown.other"}, refs, "resources of unknown kinds are kept last")
+}
+
+func TestRegistryFilterKinds(t *testing.T) {
+	provider := newFakeProvider()
+	registry := provider.registry()
+
+	other, err := grizzly.NewResource("example.com/v1", "Other", "other", map[string]any{})
+	require.NoError(t, err)
+	resources := grizzly.NewResources(other, provider.resource("a", map[string]any{"uid": "a"}))
+
+	t.Run("no kinds keeps all resources", func(t *testing.T) {
+		req := require.New(t)
+		filtered, err := registry.FilterKinds(resources, nil)
+		req.NoError(err)
+		req.Equal(2, filtered.Len())
+	})
+
+	t.Run("resources of other kinds are left out", func(t *testing.T) {
+		req := require.New(t)
+		filtered, err := registry.FilterKinds(resources, []string{"fake"})
+		req.NoError(err)
+		req.Equal(1, filtered.Len())
+		first := filtered.First()
+		req.Equal(fakeKind, first.Kind())
+	})
+
+	t.Run("unknown kinds are an error", func(t *testing.T) {
+		_, err := registry.FilterKinds(resources, []string{"Other"})
+		require.ErrorContains(t, err, "unknown kind 'Other', expected one of: Fake")
+	})
+}
+
+func TestRegistryFilterRefs(t *testing.T) {
+	provider := newFakeProvider()
+	registry := provider.registry()
+	resources := grizzly.NewResources(
+		provider.resource("abc", map[string]any{"uid": "abc"}),
+		provider.resource("def", map[string]any{"uid": "def"}),
+		provider.resource("with.dot", map[string]any{"uid": "with.dot"}),
+	)
+
+	testCases := []struct {
+		name      string
+		refs      []string
+		expected  int
+		unmatched []string
+	}{
+		{name: "no references keeps all resources", refs: nil, expected: 3},
+		{name: "UIDs match any kind", refs: []string{"abc", "with.dot"}, expected: 2},
+		{name: "UIDs can be prefixed with their kind", refs: []string{"Fake.abc", "fake/def"}, expected: 2},
+		{name: "unmatched references are returned", refs: []string{"abc", "Fake.typo", "Other.def"}, expected: 1, unmatched: []string{"Fake.typo", "Other.def"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := require.New(t)
+			filtered, unmatched := registry.FilterRefs(resources, tc.refs)
+			req.Equal(tc.expected, filtered.Len())
+			req.Equal(tc.unmatched, unmatched)
+		})
+	}
+}
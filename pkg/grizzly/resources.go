@@ -3,6 +3,7 @@ package grizzly
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	orderedmap "github.com/wk8/go-ordered-map/v2"
 	"gopkg.in/yaml.v3"
@@ -102,6 +103,33 @@ func (r *Resource) Ref() ResourceRef {
 	}
 }
 
+// Clone returns a deep copy of the resource
+func (r Resource) Clone() Resource {
+	return Resource{
+		Body:   cloneValue(r.Body).(map[string]any),
+		Source: r.Source,
+	}
+}
+
+func cloneValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		clone := make(map[string]any, len(v))
+		for key, val := range v {
+			clone[key] = cloneValue(val)
+		}
+		return clone
+	case []any:
+		clone := make([]any, len(v))
+		for i, val := range v {
+			clone[i] = cloneValue(val)
+		}
+		return clone
+	default:
+		return v
+	}
+}
+
 func (r *Resource) SetSource(source Source) {
 	r.Source = source
 }
@@ -271,6 +299,20 @@ func (r Resources) AsList() []Resource {
 	return list
 }
 
+// Sort returns the resources ordered by kind, then by name, so that
+// processing them is reproducible
+func (r Resources) Sort() Resources {
+	list := r.AsList()
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Kind() != list[j].Kind() {
+			return list[i].Kind() < list[j].Kind()
+		}
+		return list[i].Name() < list[j].Name()
+	})
+
+	return NewResources(list...)
+}
+
 func (r Resources) GroupByKind() map[string]Resources {
 	resourceByKind := map[string]Resources{}
 	_ = r.ForEach(func(resource Resource) error {
@@ -0,0 +1,30 @@
+package grizzly_test
+
+import (
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourcesSort(t *testing.T) {
+	newResource := func(kind, name string) grizzly.Resource {
+		resource, err := grizzly.NewResource("v1", kind, name, map[string]any{})
+		require.NoError(t, err)
+		return resource
+	}
+
+	resources := grizzly.NewResources(
+		newResource("Folder", "b"),
+		newResource("Dashboard", "z"),
+		newResource("Folder", "a"),
+		newResource("Dashboard", "c"),
+	)
+
+	refs := []string{}
+	for _, resource := range resources.Sort().AsList() {
+		refs = append(refs, resource.Ref().String())
+	}
+	require.Equal(t, []string{"Dashboard.c", "Dashboard.z", "Folder.a", "Folder.b"}, refs)
+	require.Equal(t, 0, grizzly.Resources{}.Sort().Len())
+}
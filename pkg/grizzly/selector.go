@@ -0,0 +1,100 @@
+package grizzly
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Selector narrows resources down to those matching all of its requirements.
+// An empty selector matches every resource.
+type Selector []selectorRequirement
+
+type selectorRequirement struct {
+	key    string
+	value  string
+	negate bool
+}
+
+// ParseSelector parses selector expressions, each of them being a
+// comma-separated list of key=value or key!=value requirements.
+//
+// The "folder" key matches the folder of a resource, and "tag" one of the
+// tags in its spec (ex: dashboard tags). Other keys match the labels of the
+// resource, then its metadata.
+func ParseSelector(expressions []string) (Selector, error) {
+	selector := Selector{}
+	for _, expression := range expressions {
+		for _, part := range strings.Split(expression, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+
+			requirement := selectorRequirement{}
+			key, value, found := strings.Cut(part, "!=")
+			if found {
+				requirement.negate = true
+			} else {
+				key, value, found = strings.Cut(part, "=")
+			}
+			key = strings.TrimSpace(key)
+			if !found || key == "" {
+				return nil, fmt.Errorf("invalid selector '%s': expected key=value or key!=value", part)
+			}
+
+			requirement.key = key
+			requirement.value = strings.TrimSpace(value)
+			selector = append(selector, requirement)
+		}
+	}
+	return selector, nil
+}
+
+// WithTags returns a copy of the selector also requiring each of the given
+// tags. Unlike selector expressions, tags may contain commas.
+func (s Selector) WithTags(tags []string) Selector {
+	selector := append(Selector{}, s...)
+	for _, tag := range tags {
+		selector = append(selector, selectorRequirement{key: "tag", value: tag})
+	}
+	return selector
+}
+
+// Matches checks whether a resource satisfies all the requirements of the selector
+func (s Selector) Matches(resource Resource) bool {
+	for _, requirement := range s {
+		if requirement.matches(resource) == requirement.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the resources matching the selector
+func (s Selector) Filter(resources Resources) Resources {
+	if len(s) == 0 {
+		return resources
+	}
+	return resources.Filter(s.Matches)
+}
+
+func (requirement selectorRequirement) matches(resource Resource) bool {
+	if requirement.key == "tag" {
+		tags, _ := resource.Spec()["tags"].([]any)
+		for _, tag := range tags {
+			if tag == requirement.value {
+				return true
+			}
+		}
+		return false
+	}
+
+	metadata := resource.metadata()
+	if labels, ok := metadata["labels"].(map[string]any); ok {
+		if value, ok := labels[requirement.key].(string); ok {
+			return value == requirement.value
+		}
+	}
+	value, _ := metadata[requirement.key].(string)
+	return value == requirement.value
+}
@@ -0,0 +1,83 @@
+package grizzly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSelector(t *testing.T) {
+	t.Run("parses requirements", func(t *testing.T) {
+		req := require.New(t)
+
+		selector, err := ParseSelector([]string{"folder=team-a, tag!=infra", "env=prod"})
+		req.NoError(err)
+		req.Equal(Selector{
+			{key: "folder", value: "team-a"},
+			{key: "tag", value: "infra", negate: true},
+			{key: "env", value: "prod"},
+		}, selector)
+	})
+
+	t.Run("rejects invalid requirements", func(t *testing.T) {
+		_, err := ParseSelector([]string{"folder"})
+		require.ErrorContains(t, err, "invalid selector 'folder'")
+
+		_, err = ParseSelector([]string{"=team-a"})
+		require.Error(t, err)
+	})
+}
+
+func TestSelectorWithTags(t *testing.T) {
+	req := require.New(t)
+
+	selector, err := ParseSelector([]string{"folder=team-a"})
+	req.NoError(err)
+
+	req.Equal(Selector{
+		{key: "folder", value: "team-a"},
+		{key: "tag", value: "infra"},
+		{key: "tag", value: "a,b"},
+	}, selector.WithTags([]string{"infra", "a,b"}))
+	req.Len(selector, 1, "the original selector is left untouched")
+}
+
+func TestSelectorFilter(t *testing.T) {
+	newDashboard := func(uid, folder string, tags ...any) Resource {
+		resource, _ := NewResource("v1", "Dashboard", uid, map[string]any{"tags": tags})
+		resource.SetMetadata("folder", folder)
+		return resource
+	}
+
+	resources := NewResources(
+		newDashboard("a", "team-a", "infra"),
+		newDashboard("b", "team-a"),
+		newDashboard("c", "team-b", "infra"),
+	)
+
+	uids := func(resources Resources) []string {
+		names := []string{}
+		for _, resource := range resources.AsList() {
+			names = append(names, resource.Name())
+		}
+		return names
+	}
+
+	for _, tc := range []struct {
+		expression string
+		expected   []string
+	}{
+		{"", []string{"a", "b", "c"}},
+		{"folder=team-a", []string{"a", "b"}},
+		{"tag=infra", []string{"a", "c"}},
+		{"folder=team-a,tag=infra", []string{"a"}},
+		{"folder!=team-a", []string{"c"}},
+		{"tag!=infra", []string{"b"}},
+	} {
+		t.Run(tc.expression, func(t *testing.T) {
+			selector, err := ParseSelector([]string{tc.expression})
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, uids(selector.Filter(resources)))
+		})
+	}
+}
@@ -315,7 +315,7 @@ func (s *Server) updateWatchedResource(name string) error {
 	}
 	if errors.As(err, &UnrecognisedFormatError{}) {
 		uerr := err.(UnrecognisedFormatError)
-		log.Infof("[watcher] Skipping %s", uerr.File)
+		log.Debugf("[watcher] Skipping %s", uerr.File)
 		return nil
 	}
 	if err != nil {
@@ -331,7 +331,7 @@ func (s *Server) updateWatchedResource(name string) error {
 		}
 		_, ok := handler.(ProxyConfigurator)
 		if ok {
-			log.Infof("[watcher] Changes detected. Reloading %s", resource.Ref())
+			log.Debugf("[watcher] Changes detected. Reloading %s", resource.Ref())
 			livereload.ReloadDashboard(resource.Name())
 		}
 	}
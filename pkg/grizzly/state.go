@@ -0,0 +1,90 @@
+package grizzly
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+)
+
+// StateFile is the default name of the file recording the resources applied
+const StateFile = ".grizzly-state.json"
+
+// ApplyState records a hash of each resource successfully applied, allowing
+// later applies to skip the resources that didn't change locally without
+// fetching their remote equivalent.
+//
+// Hashes are grouped by scope (ex: the name of a context), as the same
+// resources may be applied to several instances.
+type ApplyState struct {
+	path   string
+	scope  string
+	Scopes map[string]map[string]string `json:"scopes"`
+}
+
+// LoadApplyState reads the state recorded for a scope in the given file. A
+// missing file is an empty state.
+func LoadApplyState(path string, scope string) (*ApplyState, error) {
+	state := &ApplyState{
+		path:   path,
+		scope:  scope,
+		Scopes: map[string]map[string]string{},
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, err
+	}
+	if state.Scopes == nil {
+		state.Scopes = map[string]map[string]string{}
+	}
+	return state, nil
+}
+
+// Unchanged checks whether a resource is identical to the one last applied
+func (s *ApplyState) Unchanged(resource Resource) bool {
+	hash, err := resourceHash(resource)
+	if err != nil {
+		return false
+	}
+	return s.Scopes[s.scope][resource.Ref().String()] == hash
+}
+
+// Record remembers a resource as applied
+func (s *ApplyState) Record(resource Resource) error {
+	hash, err := resourceHash(resource)
+	if err != nil {
+		return err
+	}
+	if s.Scopes[s.scope] == nil {
+		s.Scopes[s.scope] = map[string]string{}
+	}
+	s.Scopes[s.scope][resource.Ref().String()] = hash
+	return nil
+}
+
+// Save writes the state back to its file
+func (s *ApplyState) Save() error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, content, 0644)
+}
+
+// resourceHash hashes the JSON representation of a resource, which is stable
+// as object keys are sorted
+func resourceHash(resource Resource) (string, error) {
+	content, err := json.Marshal(resource.Body)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
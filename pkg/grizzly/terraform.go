@@ -0,0 +1,101 @@
+package grizzly
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// formatTerraform is the export format writing dashboards as resources of
+// the Terraform provider for Grafana, to hand them off to Terraform
+const formatTerraform = "terraform"
+
+// terraformExtension is the extension of the files written in the Terraform
+// format
+const terraformExtension = "tf"
+
+// terraformKind is the kind of the resources exported in the Terraform
+// format: other kinds are skipped
+const terraformKind = "Dashboard"
+
+// terraformInvalidNameChars matches the characters not allowed in the names
+// of Terraform resources
+var terraformInvalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// terraformResources returns the resources which can be exported in the
+// Terraform format, reporting the others as skipped
+func terraformResources(eventsRecorder EventsRecorder, resources Resources) Resources {
+	exported := NewResources()
+	for _, resource := range resources.AsList() {
+		if resource.Kind() != terraformKind {
+			eventsRecorder.Record(Event{
+				Type:        ResourceSkipped,
+				ResourceRef: resource.Ref().String(),
+				Details:     "no Terraform equivalent",
+			})
+			continue
+		}
+		exported.Add(resource)
+	}
+	return exported
+}
+
+// formatTerraformDashboard renders a dashboard as a grafana_dashboard
+// resource, embedding its JSON model
+func formatTerraformDashboard(resource Resource) ([]byte, error) {
+	model, err := json.MarshalIndent(resource.Spec(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "resource \"grafana_dashboard\" %s {\n", terraformString(terraformName(resource.Name())))
+	if folder := resource.GetMetadata("folder"); folder != "" && !strings.EqualFold(folder, "general") {
+		fmt.Fprintf(&buf, "  folder      = %s\n", terraformString(folder))
+	}
+	// the JSON model is embedded as is: only template sequences are escaped,
+	// so that dashboard variables (ex: ${datasource}) aren't interpolated
+	fmt.Fprintf(&buf, "  config_json = <<EOT\n%s\nEOT\n", escapeTerraformTemplate(string(model)))
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// formatTerraformStream renders dashboards as a single Terraform file
+func formatTerraformStream(resources Resources) ([]byte, error) {
+	blocks := make([][]byte, 0, resources.Len())
+	for _, resource := range resources.AsList() {
+		block, err := formatTerraformDashboard(resource)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return bytes.Join(blocks, []byte("\n")), nil
+}
+
+// terraformName derives the name of a Terraform resource from a UID. Names
+// may only hold letters, digits, underscores and dashes, and must not start
+// with a digit or a dash.
+func terraformName(uid string) string {
+	name := terraformInvalidNameChars.ReplaceAllString(uid, "_")
+	if name == "" || !(name[0] == '_' || ('a' <= name[0] && name[0] <= 'z') || ('A' <= name[0] && name[0] <= 'Z')) {
+		name = "_" + name
+	}
+	return name
+}
+
+// terraformString quotes a string for Terraform, whose escape sequences are
+// those of JSON
+func terraformString(s string) string {
+	quoted, _ := json.Marshal(s)
+	return escapeTerraformTemplate(string(quoted))
+}
+
+// escapeTerraformTemplate escapes the interpolation and directive sequences
+// of Terraform strings
+func escapeTerraformTemplate(s string) string {
+	s = strings.ReplaceAll(s, "${", "$${")
+	return strings.ReplaceAll(s, "%{", "%%{")
+}
@@ -0,0 +1,106 @@
+package grizzly
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/google/go-jsonnet"
+)
+
+// ApplyTransforms runs resources through Jsonnet transforms, such as org-wide
+// policies (ex: stamping an environment tag on every dashboard). Each
+// transform is a Jsonnet file evaluating to a function, called with every
+// resource in turn, envelope included, and returning the resource to use
+// instead:
+//
+//	function(resource)
+//	  if resource.kind == 'Dashboard'
+//	  then resource + { spec+: { tags+: ['production'] } }
+//	  else resource
+//
+// Transforms are applied in order, and may not change the kind or name of
+// resources. Changed resources are no longer rewritable, as their files
+// don't hold their final definition.
+func ApplyTransforms(resources Resources, paths []string) (Resources, error) {
+	for _, path := range paths {
+		transformed, err := applyTransform(resources, path)
+		if err != nil {
+			return resources, fmt.Errorf("transform %s: %w", path, err)
+		}
+		resources = transformed
+	}
+	return resources, nil
+}
+
+func applyTransform(resources Resources, path string) (Resources, error) {
+	if _, err := os.Stat(path); err != nil {
+		return resources, err
+	}
+	currentWorkingDirectory, err := os.Getwd()
+	if err != nil {
+		return resources, err
+	}
+
+	list := resources.AsList()
+	bodies := make([]map[string]any, 0, len(list))
+	for _, resource := range list {
+		bodies = append(bodies, resource.Body)
+	}
+	input, err := json.Marshal(bodies)
+	if err != nil {
+		return resources, err
+	}
+	// JSON strings are valid Jsonnet strings
+	importPath, err := json.Marshal(path)
+	if err != nil {
+		return resources, err
+	}
+
+	vm := jsonnet.MakeVM()
+	vm.Importer(newExtendedImporter(path, currentWorkingDirectory, nil))
+	vm.NativeFunction(escapeStringRegexNativeFunc())
+	vm.NativeFunction(regexMatchNativeFunc())
+	vm.NativeFunction(regexSubstNativeFunc())
+	vm.TLACode("resources", string(input))
+	output, err := vm.EvaluateAnonymousSnippet(path, fmt.Sprintf("function(resources) std.map(import %s, resources)", importPath))
+	if err != nil {
+		return resources, err
+	}
+
+	var outputs []any
+	if err := json.Unmarshal([]byte(output), &outputs); err != nil {
+		return resources, err
+	}
+
+	for i, resource := range list {
+		body, ok := outputs[i].(map[string]any)
+		if !ok {
+			return resources, fmt.Errorf("resource %s: transforms must return a resource, not %T", resource.Ref(), outputs[i])
+		}
+		transformed := Resource{Body: body, Source: resource.Source}
+		if transformed.Ref() != resource.Ref() {
+			return resources, fmt.Errorf("resource %s: transforms may not change the kind or name of resources, %s returned", resource.Ref(), transformed.Ref())
+		}
+
+		// resources are compared through their JSON representation, which
+		// doesn't tell integers and floats apart
+		transformedJSON, err := json.Marshal(body)
+		if err != nil {
+			return resources, err
+		}
+		originalJSON, err := json.Marshal(resource.Body)
+		if err != nil {
+			return resources, err
+		}
+		if bytes.Equal(transformedJSON, originalJSON) {
+			continue
+		}
+
+		transformed.Source.Rewritable = false
+		resources.Add(transformed)
+	}
+
+	return resources, nil
+}
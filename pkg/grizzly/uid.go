@@ -0,0 +1,56 @@
+package grizzly
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseUID splits a reference to a remote resource into the handler of its
+// kind and its UID. The kind may be prefixed by the name of its provider, and
+// is matched case-insensitively (ex: Dashboard, grafana.dashboard). As UIDs
+// may contain dots, the first dot-separated prefix naming a handler is used.
+func parseUID(registry Registry, uid string) (Handler, string, error) {
+	for i := range uid {
+		if uid[i] != '.' || i == len(uid)-1 {
+			continue
+		}
+		if handler, ok := lookupHandler(registry, uid[:i]); ok {
+			return handler, uid[i+1:], nil
+		}
+	}
+
+	kind, resourceID, found := strings.Cut(uid, ".")
+	if found && resourceID != "" {
+		if _, err := registry.GetHandler(kind); err != nil {
+			return nil, "", err
+		}
+	}
+	return nil, "", fmt.Errorf("UID must be <provider>.<uid>: %s", uid)
+}
+
+// lookupHandler finds a handler by kind, optionally prefixed by the name of
+// its provider
+func lookupHandler(registry Registry, name string) (Handler, bool) {
+	for _, handler := range registry.HandlerOrder {
+		if strings.EqualFold(handler.Kind(), name) {
+			return handler, true
+		}
+	}
+
+	providerName, kind, found := strings.Cut(name, ".")
+	if !found {
+		return nil, false
+	}
+	for _, provider := range registry.Providers {
+		if !strings.EqualFold(provider.Name(), providerName) {
+			continue
+		}
+		for _, handler := range provider.GetHandlers() {
+			if strings.EqualFold(handler.Kind(), kind) {
+				registered, ok := registry.Handlers[handler.Kind()]
+				return registered, ok
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,63 @@
+package grizzly
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stubProvider struct {
+	Provider
+	name     string
+	handlers []Handler
+}
+
+func (p stubProvider) Name() string           { return p.name }
+func (p stubProvider) GetHandlers() []Handler { return p.handlers }
+
+type stubHandler struct {
+	Handler
+	kind string
+}
+
+func (h stubHandler) Kind() string { return h.kind }
+
+func TestParseUID(t *testing.T) {
+	dashboards := stubHandler{kind: "Dashboard"}
+	folders := stubHandler{kind: "DashboardFolder"}
+	registry := NewRegistry([]Provider{
+		stubProvider{name: "Grafana", handlers: []Handler{dashboards, folders}},
+	})
+
+	tests := []struct {
+		uid         string
+		handler     Handler
+		resourceID  string
+		expectedErr string
+	}{
+		{uid: "Dashboard.my-uid", handler: dashboards, resourceID: "my-uid"},
+		{uid: "Dashboard.my.dashboard.uid", handler: dashboards, resourceID: "my.dashboard.uid"},
+		{uid: "dashboardfolder.team.a", handler: folders, resourceID: "team.a"},
+		{uid: "grafana.dashboard.my.dashboard.uid", handler: dashboards, resourceID: "my.dashboard.uid"},
+		{uid: "Grafana.Dashboard.my-uid", handler: dashboards, resourceID: "my-uid"},
+		{uid: "Dashboard", expectedErr: "UID must be <provider>.<uid>: Dashboard"},
+		{uid: "Dashboard.", expectedErr: "UID must be <provider>.<uid>: Dashboard."},
+		{uid: "Unknown.my-uid", expectedErr: "couldn't find a handler for Unknown"},
+		{uid: "mimir.dashboard.my-uid", expectedErr: "couldn't find a handler for mimir"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.uid, func(t *testing.T) {
+			req := require.New(t)
+
+			handler, resourceID, err := parseUID(registry, test.uid)
+			if test.expectedErr != "" {
+				req.ErrorContains(err, test.expectedErr)
+				return
+			}
+			req.NoError(err)
+			req.Equal(test.handler, handler)
+			req.Equal(test.resourceID, resourceID)
+		})
+	}
+}
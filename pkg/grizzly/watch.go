@@ -1,11 +1,14 @@
 package grizzly
 
 import (
+	"context"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/grafana/grizzly/pkg/grizzly/notifier"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/fsnotify.v1"
 )
@@ -72,7 +75,7 @@ func (w *Watcher) Add(path string) error {
 }
 func (w *Watcher) Watch() error {
 	go func() {
-		log.Info("[watcher] Watching for changes")
+		notifier.Info(nil, "[watcher] Watching for changes")
 		for {
 			select {
 			case event, ok := <-w.watcher.Events:
@@ -84,7 +87,7 @@ func (w *Watcher) Watch() error {
 						log.Debugf("[watcher] Changes detected: %s %s ", event.Op.String(), event.Name)
 						err := w.watcherFunc(event.Name)
 						if err != nil {
-							log.Warn("[watcher] error: ", err)
+							notifier.Error(nil, fmt.Sprintf("[watcher] error: %s", err))
 						}
 					}
 				}
@@ -92,17 +95,17 @@ func (w *Watcher) Watch() error {
 				if !ok {
 					return
 				}
-				log.Warn("[watcher] error: ", err)
+				notifier.Error(nil, fmt.Sprintf("[watcher] error: %s", err))
 			}
 		}
 	}()
 	return nil
 }
 
-func (w *Watcher) Wait() error {
-	done := make(chan bool)
-	<-done
-	return nil
+// Wait blocks until the context is done, then stops watching
+func (w *Watcher) Wait(ctx context.Context) error {
+	<-ctx.Done()
+	return w.watcher.Close()
 }
 
 func (w *Watcher) isWatched(path string) bool {
@@ -2,56 +2,79 @@ package grizzly
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+	"path/filepath"
+	"sort"
+	"sync"
 	"text/tabwriter"
+	"time"
 
 	"github.com/grafana/grizzly/internal/utils"
 	"github.com/grafana/grizzly/pkg/grizzly/notifier"
 	"github.com/grafana/grizzly/pkg/term"
 	"github.com/hashicorp/go-multierror"
-	"github.com/pmezard/go-difflib/difflib"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 	terminal "golang.org/x/term"
 	"gopkg.in/yaml.v3"
 )
 
 var interactive = terminal.IsTerminal(int(os.Stdout.Fd()))
 
+// outputWriter is where the output of workflows is written, stdout by default
+var outputWriter io.Writer = os.Stdout
+
+// SetOutput changes where the output of workflows and notifications is
+// written, for instance to capture it in a buffer. Interactive features, such
+// as paging and prompts, are only enabled when writing to a terminal.
+func SetOutput(w io.Writer) {
+	outputWriter = w
+	notifier.SetOutput(w)
+
+	file, ok := w.(*os.File)
+	interactive = ok && terminal.IsTerminal(int(file.Fd()))
+}
+
 // Get retrieves a resource from a remote endpoint using its UID
-func Get(registry Registry, uid string, onlySpec bool, outputFormat string) error {
-	log.Info("Getting ", uid)
+// Get prints remote resources, identified by <kind>.<uid>. YAML documents are
+// separated by "---", JSON ones by new lines.
+func Get(ctx context.Context, registry Registry, uids []string, onlySpec bool, outputFormat string) error {
+	registry = registry.WithContext(ctx)
 
-	if strings.Count(uid, ".") == 0 {
-		return fmt.Errorf("UID must be <provider>.<uid>: %s", uid)
-	}
+	for i, uid := range uids {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		log.Info("Getting ", uid)
 
-	parts := strings.SplitN(uid, ".", 2)
-	handlerName := parts[0]
-	resourceID := parts[1]
+		handler, resourceID, err := parseUID(registry, uid)
+		if err != nil {
+			return err
+		}
 
-	handler, err := registry.GetHandler(handlerName)
-	if err != nil {
-		return err
-	}
+		resource, err := registry.getByUID(handler, resourceID)
+		if err != nil {
+			return err
+		}
 
-	resource, err := handler.GetByUID(resourceID)
-	if err != nil {
-		return err
-	}
+		resource = handler.Unprepare(*resource)
 
-	resource = handler.Unprepare(*resource)
+		content, _, _, err := Format(registry, "", resource, outputFormat, onlySpec)
+		if err != nil {
+			return err
+		}
 
-	content, _, _, err := Format(registry, "", resource, outputFormat, onlySpec)
-	if err != nil {
-		return err
+		if i > 0 && outputFormat != formatJSON {
+			fmt.Fprintln(outputWriter, "---")
+		}
+		fmt.Fprintln(outputWriter, string(content))
 	}
-
-	fmt.Println(string(content))
 	return nil
 }
 
@@ -96,14 +119,18 @@ func List(registry Registry, resources Resources, format string) error {
 }
 
 // ListRetmote outputs the keys of remote resources
-func ListRemote(registry Registry, targets []string, format string) error {
+func ListRemote(ctx context.Context, registry Registry, targets []string, format string) error {
 	log.Info("Listing remotes")
+	registry = registry.WithContext(ctx)
 
 	listedResources := []listedResource{}
 	for name, handler := range registry.Handlers {
 		if !registry.HandlerMatchesTarget(handler, targets) {
 			continue
 		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		log.Debugf("Listing remote values for handler %s", name)
 		IDs, err := handler.ListRemote()
 		if err != nil {
@@ -136,7 +163,7 @@ func listResources(listedResources []listedResource, format string) error {
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(output))
+	fmt.Fprintln(outputWriter, string(output))
 	return nil
 }
 
@@ -180,7 +207,16 @@ func listWide(listedResources []listedResource) ([]byte, error) {
 // Pull pulls remote resources and stores them in the local file system.
 // The given resourcePath must be a directory, where all resources will be stored.
 // If opts.JSONSpec is true, which is only applicable for dashboards, saves the spec as a JSON file.
-func Pull(registry Registry, resourcePath string, onlySpec bool, outputFormat string, targets []string, continueOnError bool, eventsRecorder EventsRecorder) error {
+// Resources stored in one of the excludeFolders, referenced by UID or title,
+// are skipped. With raw, resources are written exactly as the endpoints
+// return them, for the handlers supporting it (see RawRemoteHandler).
+func Pull(ctx context.Context, registry Registry, resourcePath string, onlySpec bool, outputFormat string, raw bool, targets []string, excludeFolders []string, continueOnError bool, eventsRecorder EventsRecorder) error {
+	if raw {
+		if err := validateRawFormat(outputFormat, onlySpec); err != nil {
+			return err
+		}
+	}
+
 	resourcePathIsFile, err := isFile(resourcePath)
 	if err != nil {
 		return err
@@ -190,12 +226,88 @@ func Pull(registry Registry, resourcePath string, onlySpec bool, outputFormat st
 		return fmt.Errorf("pull <resource-path> must be a directory")
 	}
 
-	var finalErr error
+	registry = registry.WithContext(ctx)
 
 	log.Infof("Pulling resources to %s", resourcePath)
-	for name, handler := range registry.Handlers {
-		if !registry.HandlerMatchesTarget(handler, targets) {
-			notifier.Info(notifier.SimpleString(handler.Kind()), "skipped")
+	return fetchRemotes(ctx, eventsRecorder, registry, remoteOptions{
+		targets:         targets,
+		excludeFolders:  excludeFolders,
+		raw:             raw,
+		continueOnError: continueOnError,
+		report:          true,
+	}, func(resource *Resource, rawContent []byte) error {
+		content, filename, _, err := Format(registry, resourcePath, resource, outputFormat, onlySpec)
+		if err != nil {
+			eventsRecorder.Record(Event{
+				Type:        ResourceFailure,
+				ResourceRef: resource.Ref().String(),
+				Details:     fmt.Sprintf("failed formatting resource: %s", err),
+			})
+			return err
+		}
+		if rawContent != nil {
+			content = rawContent
+		}
+
+		if err := WriteFile(filename, content); err != nil {
+			eventsRecorder.Record(Event{
+				Type:        ResourceFailure,
+				ResourceRef: resource.Ref().String(),
+				Details:     fmt.Sprintf("failed writing resource to file: %s", err),
+			})
+			return err
+		}
+
+		eventsRecorder.Record(Event{Type: ResourcePulled, ResourceRef: resource.Ref().String()})
+		return nil
+	})
+}
+
+// remoteOptions drives which remote resources fetchRemotes retrieves
+type remoteOptions struct {
+	targets []string
+	// excludeFolders lists folders, referenced by UID or title, whose
+	// resources are skipped
+	excludeFolders []string
+	// raw also retrieves resources exactly as the endpoints return them, for
+	// the handlers supporting it (see RawRemoteHandler)
+	raw             bool
+	continueOnError bool
+	// report notifies of the handlers not targeted, and of the number of
+	// resources found for the others
+	report bool
+	// skip, when set, is called before retrieving a resource, which is left
+	// out when it returns true
+	skip func(handler Handler, uid string) (bool, error)
+}
+
+// fetchRemotes lists the remote resources of the handlers matching the
+// targets, in a stable order, and retrieves them one by one. Each resource is
+// handed to visit, unprepared, along with its raw content when requested.
+// Failures are recorded as events, and stop the listing unless
+// opts.continueOnError is set. Errors returned by visit are expected to be
+// recorded by it.
+func fetchRemotes(ctx context.Context, eventsRecorder EventsRecorder, registry Registry, opts remoteOptions, visit func(resource *Resource, rawContent []byte) error) error {
+	var finalErr error
+
+	excluded, err := resolveFolderExclusion(registry, opts.excludeFolders)
+	if err != nil {
+		return err
+	}
+
+	// iterate over handlers in a stable order, so that results are reproducible
+	names := make([]string, 0, len(registry.Handlers))
+	for name := range registry.Handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		handler := registry.Handlers[name]
+		if !registry.HandlerMatchesTarget(handler, opts.targets) {
+			if opts.report {
+				notifier.Info(notifier.SimpleString(handler.Kind()), "skipped")
+			}
 			continue
 		}
 
@@ -209,83 +321,96 @@ func Pull(registry Registry, resourcePath string, onlySpec bool, outputFormat st
 				Details:     fmt.Sprintf("failed listing remote values: %s", err),
 			})
 
-			if continueOnError {
+			if opts.continueOnError {
 				continue
 			}
 
 			return finalErr
 		}
-		if len(UIDs) == 0 {
-			notifier.Info(nil, "No resources found")
-			continue
+		if opts.report {
+			if len(UIDs) == 0 {
+				notifier.Info(nil, "No resources found")
+				continue
+			}
+			notifier.Warn(nil, fmt.Sprintf("Pulling %d resources", len(UIDs)))
 		}
 
-		notifier.Warn(nil, fmt.Sprintf("Pulling %d resources", len(UIDs)))
+		rawHandler := rawRemoteHandler(handler, opts.raw)
+
 		for _, UID := range UIDs {
-			if !registry.ResourceMatchesTarget(handler.Kind(), UID, targets) {
+			if !registry.ResourceMatchesTarget(handler.Kind(), UID, opts.targets) {
 				continue
 			}
+			if err := ctx.Err(); err != nil {
+				return multierror.Append(finalErr, err)
+			}
 
-			resource, err := handler.GetByUID(UID)
-			if errors.Is(err, ErrNotFound) {
-				finalErr = multierror.Append(finalErr, err)
-				eventsRecorder.Record(Event{Type: ResourceNotFound, ResourceRef: UID})
-				if continueOnError {
+			if opts.skip != nil {
+				skip, err := opts.skip(handler, UID)
+				if err != nil {
+					return multierror.Append(finalErr, err)
+				}
+				if skip {
 					continue
 				}
-
-				return nil
 			}
+
+			resource, err := registry.getByUID(handler, UID)
 			if err != nil {
 				finalErr = multierror.Append(finalErr, err)
-				eventsRecorder.Record(Event{
-					Type:        ResourceFailure,
-					ResourceRef: UID,
-					Details:     fmt.Sprintf("failed pulling resource: %s", err),
-				})
+				if errors.Is(err, ErrNotFound) {
+					eventsRecorder.Record(Event{Type: ResourceNotFound, ResourceRef: NewResourceRef(handler.Kind(), UID).String()})
+				} else {
+					eventsRecorder.Record(Event{
+						Type:        ResourceFailure,
+						ResourceRef: NewResourceRef(handler.Kind(), UID).String(),
+						Details:     fmt.Sprintf("failed pulling resource: %s", err),
+					})
+				}
 
-				if continueOnError {
+				if opts.continueOnError {
 					continue
 				}
 
 				return finalErr
 			}
 
-			resource = handler.Unprepare(*resource)
-
-			content, filename, _, err := Format(registry, resourcePath, resource, outputFormat, onlySpec)
-			if err != nil {
-				finalErr = multierror.Append(finalErr, err)
+			if excluded.excludes(handler, *resource) {
 				eventsRecorder.Record(Event{
-					Type:        ResourceFailure,
+					Type:        ResourceSkipped,
 					ResourceRef: resource.Ref().String(),
-					Details:     fmt.Sprintf("failed formatting resource: %s", err),
+					Details:     fmt.Sprintf("in excluded folder %s", resource.GetMetadata("folder")),
 				})
+				continue
+			}
 
-				if continueOnError {
-					continue
+			var rawContent []byte
+			if rawHandler != nil {
+				rawContent, err = rawHandler.GetRemoteRaw(UID)
+				if err != nil {
+					finalErr = multierror.Append(finalErr, err)
+					eventsRecorder.Record(Event{
+						Type:        ResourceFailure,
+						ResourceRef: resource.Ref().String(),
+						Details:     fmt.Sprintf("failed pulling raw resource: %s", err),
+					})
+
+					if opts.continueOnError {
+						continue
+					}
+
+					return finalErr
 				}
-
-				return finalErr
 			}
 
-			err = WriteFile(filename, content)
-			if err != nil {
+			if err := visit(handler.Unprepare(*resource), rawContent); err != nil {
 				finalErr = multierror.Append(finalErr, err)
-				eventsRecorder.Record(Event{
-					Type:        ResourceFailure,
-					ResourceRef: resource.Ref().String(),
-					Details:     fmt.Sprintf("failed writing resource to file: %s", err),
-				})
-
-				if continueOnError {
+				if opts.continueOnError {
 					continue
 				}
 
 				return finalErr
 			}
-
-			eventsRecorder.Record(Event{Type: ResourcePulled, ResourceRef: resource.Ref().String()})
 		}
 	}
 
@@ -315,8 +440,8 @@ func Show(registry Registry, resources Resources, outputFormat string) error {
 				Content: string(content),
 			})
 		} else {
-			fmt.Printf("%s:\n", resource.Ref().String())
-			fmt.Println(string(content))
+			fmt.Fprintf(outputWriter, "%s:\n", resource.Ref().String())
+			fmt.Fprintln(outputWriter, string(content))
 		}
 	}
 	if interactive {
@@ -325,72 +450,214 @@ func Show(registry Registry, resources Resources, outputFormat string) error {
 	return nil
 }
 
+// DiffOptions holds the options driving how resources are compared
+type DiffOptions struct {
+	OnlySpec     bool
+	OutputFormat string
+	// DiffFormat selects how differences are reported: human-readable text by
+	// default, or a structured document (json, yaml)
+	DiffFormat string
+	// ContextLines is the number of unchanged lines shown around each change.
+	// Zero selects DefaultDiffContextLines, and a negative value shows
+	// resources in full.
+	ContextLines int
+	// Selector narrows down the resources to compare
+	Selector Selector
+	// Folder, when set, moves the resources stored in folders (ex: dashboards)
+	// to this folder, referenced by UID or title
+	Folder string
+	// OnlyChanges leaves unchanged resources out of the report, while still
+	// counting them in the summary
+	OnlyChanges bool
+	// MaxDiffLines is the maximum number of lines of changes shown for each
+	// resource, the rest being summarized. Zero shows all changes.
+	MaxDiffLines int
+}
+
 // Diff compares resources to those at the endpoints
-func Diff(registry Registry, resources Resources, onlySpec bool, outputFormat string) error {
+func Diff(ctx context.Context, registry Registry, resources Resources, opts DiffOptions) error {
+	if err := validateDiffFormat(opts.DiffFormat); err != nil {
+		return err
+	}
 	log.Infof("Diff-ing %d resources", resources.Len())
 
-	for _, resource := range resources.AsList() {
-		handler, err := registry.GetHandler(resource.Kind())
-		if err != nil {
-			return err
-		}
-
-		resource = *handler.Unprepare(resource)
-
-		local, _, _, err := Format(registry, "", &resource, outputFormat, onlySpec)
-		if err != nil {
-			return err
-		}
-
-		uid := resource.Name()
-
-		log.Debugf("Getting the remote value for `%s`", resource.Ref())
-		remote, err := handler.GetRemote(resource)
-		if errors.Is(err, ErrNotFound) {
-			notifier.NotFound(resource)
-			continue
-		}
+	// structured diffs are the only output of the command
+	if opts.DiffFormat != formatJSON && opts.DiffFormat != formatYAML {
+		warnUnresolvedReferences(registry, resources)
+	}
+	return reportDiffs(opts, notifier.NotFound, func(callback func(diff ResourceDiff)) error {
+		return forEachDiff(ctx, registry, resources, opts, callback)
+	})
+}
 
-		if err != nil {
-			return fmt.Errorf("Error retrieving resource from %s %s: %v", resource.Kind(), uid, err)
-		}
+// DiffLocal compares two sets of local resources to each other, without
+// involving the endpoints: resources are paired by kind and UID, and those
+// only found in one of the sets are reported as added or removed
+func DiffLocal(ctx context.Context, registry Registry, before, after Resources, opts DiffOptions) error {
+	if err := validateDiffFormat(opts.DiffFormat); err != nil {
+		return err
+	}
+	log.Infof("Diff-ing %d resources against %d resources", after.Len(), before.Len())
 
-		remote = handler.Unprepare(*remote)
+	return reportDiffs(opts, notifier.Added, func(callback func(diff ResourceDiff)) error {
+		return forEachLocalDiff(ctx, registry, before, after, opts, callback)
+	})
+}
 
-		remoteRepresentation, _, _, err := Format(registry, "", remote, outputFormat, onlySpec)
+// reportDiffs reports the differences found by a comparison, announcing new
+// resources with the given notifier
+func reportDiffs(opts DiffOptions, announceNew func(obj fmt.Stringer), forEach func(callback func(diff ResourceDiff)) error) error {
+	if opts.DiffFormat == formatJSON || opts.DiffFormat == formatYAML {
+		diffs := []ResourceDiff{}
+		err := forEach(func(diff ResourceDiff) {
+			if !opts.OnlyChanges || diff.Status != DiffStatusUnchanged {
+				diffs = append(diffs, diff)
+			}
+		})
 		if err != nil {
 			return err
 		}
+		return printDiffs(diffs, opts.DiffFormat)
+	}
 
-		if string(local) == string(remoteRepresentation) {
-			notifier.NoChanges(resource)
-		} else {
-			diff := difflib.UnifiedDiff{
-				A:        difflib.SplitLines(string(remoteRepresentation)),
-				B:        difflib.SplitLines(string(local)),
-				FromFile: "Remote",
-				ToFile:   "Local",
-				Context:  3,
+	counts := map[DiffStatus]int{}
+	err := forEach(func(diff ResourceDiff) {
+		ref := NewResourceRef(diff.Kind, diff.UID)
+		counts[diff.Status]++
+
+		switch diff.Status {
+		case DiffStatusNew:
+			announceNew(ref)
+		case DiffStatusRemoved:
+			notifier.Error(ref, "removed")
+		case DiffStatusUnchanged:
+			if !opts.OnlyChanges {
+				notifier.NoChanges(ref)
 			}
-			difference, _ := difflib.GetUnifiedDiffString(diff)
-			notifier.HasChanges(resource, difference)
+		default:
+			notifier.HasChanges(ref, truncateDiff(diff.changes(), opts.MaxDiffLines))
 		}
+	})
+	if err != nil {
+		return err
 	}
 
+	notifier.Info(nil, diffSummary(counts))
 	return nil
 }
 
+// diffSummary tallies the outcome of a diff (ex: "12 unchanged, 4 changed, 1 new")
+func diffSummary(counts map[DiffStatus]int) string {
+	summary := fmt.Sprintf("%d %s, %d %s, %d %s",
+		counts[DiffStatusUnchanged], DiffStatusUnchanged,
+		counts[DiffStatusChanged], DiffStatusChanged,
+		counts[DiffStatusNew], DiffStatusNew,
+	)
+	if counts[DiffStatusRemoved] > 0 {
+		summary += fmt.Sprintf(", %d %s", counts[DiffStatusRemoved], DiffStatusRemoved)
+	}
+	return summary
+}
+
 type EventsRecorder interface {
 	Record(event Event)
 	Summary() Summary
 }
 
+// ApplyOptions holds the options driving how resources are applied
+type ApplyOptions struct {
+	ContinueOnError bool
+	// DryRun compares resources with their remote equivalent without
+	// adding or updating them.
+	DryRun bool
+	// Interactive shows the changes made to each resource and asks for
+	// confirmation before applying them. It only applies when stdout is a
+	// terminal.
+	Interactive bool
+	// ContextLines is the number of unchanged lines shown around each change
+	// in interactive mode, as DiffOptions.ContextLines
+	ContextLines int
+	// MaxDiffLines is the maximum number of lines of changes shown for each
+	// resource in interactive mode, zero showing them all
+	MaxDiffLines int
+	// Selector narrows down the resources to apply
+	Selector Selector
+	// Folder, when set, moves the resources stored in folders (ex: dashboards)
+	// to this folder, referenced by UID or title
+	Folder string
+	// PreApply, when set, is called before adding or updating each resource.
+	// An error prevents the change, and reports the resource as failed.
+	PreApply ApplyHook
+	// PostApply, when set, is called once each resource has been added or
+	// updated. An error reports the resource as failed, although the change
+	// has already been made.
+	PostApply ApplyHook
+	// State, when set, is used to skip the resources that didn't change since
+	// they were last applied, without fetching them. It is updated with the
+	// resources successfully applied.
+	State *ApplyState
+	// ResourceTimeout, when set, bounds the time spent applying each
+	// resource. A resource taking longer is reported as failed.
+	ResourceTimeout time.Duration
+	// MaxResources, when set, makes Apply refuse to run on more resources,
+	// as a safety net against templating bugs generating many resources
+	MaxResources int
+	// Force updates the resources identical to their remote equivalent
+	// instead of leaving them unchanged (ex: to re-provision an instance
+	// after a restore). The state isn't used to skip resources either.
+	Force bool
+	// Dereference rewrites the references resources make to others by name
+	// (ex: datasources of dashboards) to the UIDs they have remotely, for
+	// the handlers supporting it
+	Dereference bool
+}
+
+// ApplyAction is the change made to a remote resource when applying it
+type ApplyAction string
+
+const (
+	ApplyActionAdd    ApplyAction = "add"
+	ApplyActionUpdate ApplyAction = "update"
+)
+
+// ApplyHook is called with a resource, and the change made to its remote
+// equivalent, when applying it
+type ApplyHook func(resource Resource, action ApplyAction) error
+
 // Apply pushes resources to endpoints
-func Apply(registry Registry, resources Resources, continueOnError bool, eventsRecorder EventsRecorder) error {
+func Apply(ctx context.Context, registry Registry, resources Resources, opts ApplyOptions, eventsRecorder EventsRecorder) error {
 	var finalErr error
 
+	if _, ok := registry.offlineResources(); ok && !opts.DryRun {
+		return fmt.Errorf("resources can't be applied to an offline remote, only compared to it with a dry run")
+	}
+
+	registry = registry.WithContext(ctx)
+	resources = opts.Selector.Filter(resources)
+	resources = overrideFolder(registry, resources, opts.Folder)
+	if opts.MaxResources > 0 && resources.Len() > opts.MaxResources {
+		return fmt.Errorf("refusing to apply %d resources, more than the maximum of %d: %w", resources.Len(), opts.MaxResources, ErrTooManyResources)
+	}
+	warnUnresolvedReferences(registry, resources)
+	// resources referenced by others (ex: folders) are applied first
+	resources = registry.Sort(resources)
+	registry.prefetchRemotes(resources)
+
 	for _, resource := range resources.AsList() {
-		err := applyResource(registry, resource, eventsRecorder)
+		// changes already made are kept: only the remaining ones are abandoned
+		if err := ctx.Err(); err != nil {
+			return multierror.Append(finalErr, err)
+		}
+		if opts.State != nil && !opts.Force && opts.State.Unchanged(resource) {
+			eventsRecorder.Record(Event{
+				Type:        ResourceNotChanged,
+				ResourceRef: resource.Ref().String(),
+				Details:     "unchanged since last apply",
+			})
+			continue
+		}
+
+		err := applyResourceWithTimeout(ctx, registry, resource, opts, eventsRecorder)
 		if err != nil {
 			finalErr = multierror.Append(finalErr, err)
 
@@ -400,7 +667,7 @@ func Apply(registry Registry, resources Resources, continueOnError bool, eventsR
 				Details:     err.Error(),
 			})
 
-			if !continueOnError {
+			if !opts.ContinueOnError {
 				return finalErr
 			}
 		}
@@ -409,29 +676,79 @@ func Apply(registry Registry, resources Resources, continueOnError bool, eventsR
 	return finalErr
 }
 
-func applyResource(registry Registry, resource Resource, trailRecorder EventsRecorder) error {
+// applyResourceWithTimeout applies a resource, giving up on its remote calls
+// once opts.ResourceTimeout is elapsed
+func applyResourceWithTimeout(ctx context.Context, registry Registry, resource Resource, opts ApplyOptions, trailRecorder EventsRecorder) error {
+	if opts.ResourceTimeout <= 0 {
+		return applyResource(registry, resource, opts, trailRecorder)
+	}
+
+	resourceCtx, cancel := context.WithTimeout(ctx, opts.ResourceTimeout)
+	defer cancel()
+
+	err := applyResource(registry.WithContext(resourceCtx), resource, opts, trailRecorder)
+	if err != nil && ctx.Err() == nil && errors.Is(resourceCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", opts.ResourceTimeout, err)
+	}
+	return err
+}
+
+func applyResource(registry Registry, resource Resource, opts ApplyOptions, trailRecorder EventsRecorder) error {
 	resourceRef := resource.Ref().String()
+	// the resource as given, as preparing it may alter it
+	localResource := resource.Clone()
 
 	handler, err := registry.GetHandler(resource.Kind())
 	if err != nil {
 		return err
 	}
 
+	if opts.Dereference {
+		resource, err = dereference(handler, resource)
+		if err != nil {
+			return err
+		}
+	}
+
 	log.Debugf("Getting the remote value for `%s`", resource.Ref())
-	existingResource, err := handler.GetRemote(resource)
+	existingResource, err := registry.getRemote(handler, resource)
 	if errors.Is(err, ErrNotFound) {
+		if opts.DryRun {
+			trailRecorder.Record(Event{
+				Type:        ResourceWouldBeAdded,
+				ResourceRef: resourceRef,
+			})
+			return nil
+		}
+
 		log.Debugf("`%s` was not found, adding it...", resource.Ref())
 
+		confirmed, err := confirmChange(registry, handler, opts, resource, nil)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			trailRecorder.Record(Event{
+				Type:        ResourceSkipped,
+				ResourceRef: resourceRef,
+			})
+			return nil
+		}
+
 		resource = *handler.Prepare(nil, resource)
-		if err := handler.Add(resource); err != nil {
+		err = applyChange(registry, opts, resource, ApplyActionAdd, func() error {
+			return handler.Add(resource)
+		})
+		if err != nil {
 			return err
 		}
 
 		trailRecorder.Record(Event{
 			Type:        ResourceAdded,
 			ResourceRef: resourceRef,
+			Details:     appliedDetails(handler, resource),
 		})
-		return nil
+		return recordApplied(opts, localResource)
 	}
 	if err != nil {
 		return err
@@ -439,162 +756,1076 @@ func applyResource(registry Registry, resource Resource, trailRecorder EventsRec
 
 	log.Debugf("`%s` was found, updating it...", resource.Ref())
 
-	resourceRepresentation, err := resource.YAML()
+	comparableResource := comparableForm(handler, resource)
+	resourceRepresentation, err := comparableResource.YAML()
 	if err != nil {
 		return err
 	}
 
+	// the resource as compared to the remote one, as preparing it may alter it
+	comparedResource := resource.Clone()
 	resource = *handler.Prepare(existingResource, resource)
 	existingResource = handler.Unprepare(*existingResource)
-	existingResourceRepresentation, err := existingResource.YAML()
+	comparableExistingResource := comparableForm(handler, *existingResource)
+	existingResourceRepresentation, err := comparableExistingResource.YAML()
 	if err != nil {
 		return err
 	}
 
-	if resourceRepresentation == existingResourceRepresentation {
+	unchanged := resourceRepresentation == existingResourceRepresentation
+	if unchanged && !opts.Force {
 		trailRecorder.Record(Event{
 			Type:        ResourceNotChanged,
 			ResourceRef: resourceRef,
 		})
+		return recordApplied(opts, localResource)
+	}
+
+	if opts.DryRun {
+		eventType := ResourceWouldBeUpdated
+		if unchanged {
+			eventType = ResourceWouldBeForceUpdated
+		}
+		trailRecorder.Record(Event{
+			Type:        eventType,
+			ResourceRef: resourceRef,
+		})
+		return nil
+	}
+
+	confirmed, err := confirmChange(registry, handler, opts, comparedResource, existingResource)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		trailRecorder.Record(Event{
+			Type:        ResourceSkipped,
+			ResourceRef: resourceRef,
+		})
 		return nil
 	}
 
-	if err = handler.Update(*existingResource, resource); err != nil {
+	err = applyChange(registry, opts, resource, ApplyActionUpdate, func() error {
+		return handler.Update(*existingResource, resource)
+	})
+	if err != nil {
 		return err
 	}
 
+	eventType := ResourceUpdated
+	if unchanged {
+		eventType = ResourceForceUpdated
+	}
 	trailRecorder.Record(Event{
-		Type:        ResourceUpdated,
+		Type:        eventType,
 		ResourceRef: resourceRef,
+		Details:     appliedDetails(handler, resource),
 	})
 
-	return nil
+	return recordApplied(opts, localResource)
 }
 
-// Snapshot pushes resources to endpoints as snapshots, if supported
-func Snapshot(registry Registry, resources Resources, expiresSeconds int) error {
-	for _, resource := range resources.AsList() {
-		handler, err := registry.GetHandler(resource.Kind())
-		if err != nil {
-			return err
-		}
-		snapshotHandler, ok := handler.(SnapshotHandler)
-		if !ok {
-			notifier.NotSupported(resource, "snapshot")
-			continue
-		}
-		err = snapshotHandler.Snapshot(resource, expiresSeconds)
-		if err != nil {
-			return err
-		}
+// appliedDetails describes the add or update of a resource, for the handlers
+// supporting it
+func appliedDetails(handler Handler, resource Resource) string {
+	detailsHandler, ok := handler.(AppliedDetailsHandler)
+	if !ok {
+		return ""
 	}
-	return nil
+	return detailsHandler.AppliedDetails(resource)
 }
 
-// Watch watches a directory for changes then pushes Jsonnet resource to endpoints
-// when changes are noticed.
-func Watch(registry Registry, watchDir string, resourcePath string, parser Parser, parserOpts ParserOptions, trailRecorder EventsRecorder) error {
-	updateWatchedResource := func(path string) error {
-		log.Infof("Changes detected in %q. Applying %q", path, resourcePath)
-		resources, err := parser.Parse(resourcePath, parserOpts)
-		if err != nil {
-			log.Error("Error parsing resource file: ", err)
-		}
-		err = Apply(registry, resources, false, trailRecorder) // TODO?
-		if err != nil {
-			log.Error("Error applying resources: ", err)
-		}
-		return nil
-	}
-	watcher, err := NewWatcher(updateWatchedResource)
-	if err != nil {
-		return err
-	}
-	err = watcher.Add(watchDir)
-	if err != nil {
-		return err
+// dereference rewrites the references a resource makes to others by name,
+// for the handlers supporting it, and reports each rewrite
+func dereference(handler Handler, resource Resource) (Resource, error) {
+	dereferenceHandler, ok := handler.(DereferenceHandler)
+	if !ok {
+		return resource, nil
 	}
-	err = watcher.Watch()
+	dereferenced, rewrites, err := dereferenceHandler.Dereference(resource)
 	if err != nil {
-		return err
+		return resource, fmt.Errorf("dereferencing: %w", err)
 	}
-	err = watcher.Wait()
-	if err != nil {
-		return err
+	for _, rewrite := range rewrites {
+		notifier.Info(resource.Ref(), "dereferenced "+rewrite)
 	}
-	return nil
+	return dereferenced, nil
 }
 
-// Export renders Jsonnet resources then saves them to a directory
-func Export(eventsRecorder EventsRecorder, registry Registry, exportDir string, resources Resources, onlySpec bool, outputFormat string, continueOnError bool) error {
-	if err := utils.EnsureDirectoryExists(exportDir, 0755); err != nil {
-		return err
+// recordApplied remembers a resource as applied, when tracking state
+func recordApplied(opts ApplyOptions, resource Resource) error {
+	if opts.State == nil || opts.DryRun {
+		return nil
 	}
+	return opts.State.Record(resource)
+}
 
-	var finalErr error
-	for _, resource := range resources.AsList() {
-		err := exportResource(eventsRecorder, registry, exportDir, resource, onlySpec, outputFormat)
-		if err != nil {
-			finalErr = multierror.Append(finalErr, err)
+// overrideFolder moves the resources of kinds stored in folders to the given
+// folder, when set. Other resources are left untouched.
+func overrideFolder(registry Registry, resources Resources, folder string) Resources {
+	if folder == "" {
+		return resources
+	}
 
-			eventsRecorder.Record(Event{
-				Type:        ResourceFailure,
-				ResourceRef: resource.Ref().String(),
-				Details:     err.Error(),
-			})
+	overridden := NewResources()
+	_ = resources.ForEach(func(resource Resource) error {
+		handler, err := registry.GetHandler(resource.Kind())
+		if err == nil && handler.UsesFolders() {
+			resource = resource.Clone()
+			resource.SetMetadata("folder", folder)
+		}
+		overridden.Add(resource)
+		return nil
+	})
+	return overridden
+}
 
-			if !continueOnError {
-				return finalErr
+// folderExclusion holds the UIDs of the folders whose resources are skipped
+type folderExclusion map[string]bool
+
+// resolveFolderExclusion resolves folders referenced by UID or title to their
+// UID, using the handlers managing folders. Folders that can't be found are
+// kept as given, as they may not exist on every instance.
+func resolveFolderExclusion(registry Registry, refs []string) (folderExclusion, error) {
+	excluded := folderExclusion{}
+	for _, ref := range refs {
+		uid := ref
+		for _, handler := range registry.HandlerOrder {
+			resolver, ok := handler.(FolderResolverHandler)
+			if !ok {
+				continue
+			}
+			resolved, err := resolver.ResolveFolderUID(ref)
+			if errors.Is(err, ErrNotFound) {
+				notifier.Warn(nil, fmt.Sprintf("Excluded folder %s not found", ref))
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("resolving excluded folder %s: %w", ref, err)
 			}
+			uid = resolved
 		}
+		excluded[uid] = true
 	}
+	return excluded, nil
+}
+
+// excludes checks whether a resource is stored in an excluded folder
+func (excluded folderExclusion) excludes(handler Handler, resource Resource) bool {
+	return handler.UsesFolders() && excluded[resource.GetMetadata("folder")]
+}
+
+// applyChange makes a change to a remote resource, surrounded by the hooks
+// of the options
+func applyChange(registry Registry, opts ApplyOptions, resource Resource, action ApplyAction, change func() error) error {
+	if opts.PreApply != nil {
+		if err := opts.PreApply(resource, action); err != nil {
+			return fmt.Errorf("pre-apply hook: %w", err)
+		}
+	}
+
+	if err := change(); err != nil {
+		if errors.Is(err, ErrWriteRejected) {
+			return WriteRejectedError{Action: action, Err: err}
+		}
+		return err
+	}
+	registry.cache.Invalidate(resource.Kind(), resource.Name())
+
+	if opts.PostApply != nil {
+		if err := opts.PostApply(resource, action); err != nil {
+			return fmt.Errorf("post-apply hook: %w", err)
+		}
+	}
+	return nil
+}
+
+// Rollback restores a previous version of a remote resource, identified by
+// <kind>.<uid>. When resourcePath is set, the restored resource is also
+// written there.
+func Rollback(ctx context.Context, registry Registry, uid string, version int64, resourcePath string, onlySpec bool, outputFormat string) error {
+	registry = registry.WithContext(ctx)
+
+	handler, resourceID, err := parseUID(registry, uid)
+	if err != nil {
+		return err
+	}
+	rollbackHandler, ok := handler.(RollbackHandler)
+	if !ok {
+		return fmt.Errorf("%s does not support rollbacks: %w", handler.Kind(), ErrNotImplemented)
+	}
+
+	resource, newVersion, err := rollbackHandler.Rollback(resourceID, version)
+	if err != nil {
+		return err
+	}
+	registry.cache.Invalidate(handler.Kind(), resourceID)
+	notifier.Info(resource.Ref(), fmt.Sprintf("version %d restored as version %d", version, newVersion))
+
+	if resourcePath == "" {
+		return nil
+	}
+
+	resource = handler.Unprepare(*resource)
+	content, filename, _, err := Format(registry, resourcePath, resource, outputFormat, onlySpec)
+	if err != nil {
+		return err
+	}
+	if err := WriteFile(filename, content); err != nil {
+		return err
+	}
+	notifier.Info(resource.Ref(), "written to "+filename)
+	return nil
+}
+
+// Versions outputs the versions kept for a remote resource, given as
+// <kind>.<uid>, when supported
+func Versions(ctx context.Context, registry Registry, uid string, format string) error {
+	registry = registry.WithContext(ctx)
+
+	handler, resourceID, err := parseUID(registry, uid)
+	if err != nil {
+		return err
+	}
+	versionsHandler, ok := handler.(VersionsHandler)
+	if !ok {
+		return fmt.Errorf("%s does not support listing versions: %w", handler.Kind(), ErrNotImplemented)
+	}
+
+	versions, err := versionsHandler.Versions(resourceID)
+	if err != nil {
+		return err
+	}
+
+	var output []byte
+	switch format {
+	case formatYAML:
+		output, err = yaml.Marshal(versions)
+	case formatJSON:
+		output, err = json.MarshalIndent(versions, "", "  ")
+	case formatDefault:
+		var out bytes.Buffer
+		w := tabwriter.NewWriter(&out, 0, 0, 4, ' ', 0)
+		f := "%v\t%s\t%s\t%s\n"
+		fmt.Fprintf(w, f, "VERSION", "CREATED", "AUTHOR", "MESSAGE")
+		for _, version := range versions {
+			fmt.Fprintf(w, f, version.Version, version.Created, version.Author, version.Message)
+		}
+		err = w.Flush()
+		output = out.Bytes()
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(outputWriter, string(output))
+	return nil
+}
+
+// Validate checks resources with the validation of their handler, which doesn't
+// reach remote endpoints. With remote, resources are also checked against their
+// endpoints, when supported, without changing them. Invalid resources are reported
+// one by one, and make the returned error.
+func Validate(ctx context.Context, registry Registry, resources Resources, remote bool) error {
+	var finalErr error
+	invalid := 0
+
+	registry = registry.WithContext(ctx)
+	warnUnresolvedReferences(registry, resources)
+	for _, resource := range resources.AsList() {
+		if err := ctx.Err(); err != nil {
+			return multierror.Append(finalErr, err)
+		}
+
+		handler, err := registry.GetHandler(resource.Kind())
+		if err != nil {
+			return err
+		}
+
+		err = handler.Validate(resource)
+		if err == nil && remote {
+			if remoteHandler, ok := handler.(RemoteValidateHandler); ok {
+				err = remoteHandler.ValidateRemote(resource)
+			} else {
+				notifier.NotSupported(resource, "remote validation")
+			}
+		}
+		if err != nil {
+			invalid++
+			finalErr = multierror.Append(finalErr, fmt.Errorf("%s: %w", resource.Ref(), err))
+			notifier.Error(resource.Ref(), err.Error())
+			continue
+		}
+		notifier.Info(resource.Ref(), "valid")
+	}
+
+	notifier.Info(nil, fmt.Sprintf("%d valid, %d invalid", resources.Len()-invalid, invalid))
+	return finalErr
+}
+
+// warnUnresolvedReferences warns about the references resources make to
+// others which aren't among them (ex: dashboards of a playlist which aren't
+// applied along with it)
+func warnUnresolvedReferences(registry Registry, resources Resources) {
+	for _, resource := range resources.AsList() {
+		handler, err := registry.GetHandler(resource.Kind())
+		if err != nil {
+			continue
+		}
+		referenceHandler, ok := handler.(ReferenceHandler)
+		if !ok {
+			continue
+		}
+		for _, warning := range referenceHandler.UnresolvedReferences(resource, resources) {
+			notifier.Warn(resource.Ref(), warning)
+		}
+	}
+}
+
+// CheckConflicts looks for the remote resources that local resources could
+// collide with once applied (ex: dashboards of another team with the same
+// UID or title), without applying them. Potential collisions, and resources
+// that couldn't be checked, are reported one by one, and make the returned
+// error.
+func CheckConflicts(ctx context.Context, registry Registry, resources Resources) error {
+	var finalErr error
+	clear, conflicting := 0, 0
+
+	registry = registry.WithContext(ctx)
+	for _, resource := range resources.AsList() {
+		if err := ctx.Err(); err != nil {
+			return multierror.Append(finalErr, err)
+		}
+
+		handler, err := registry.GetHandler(resource.Kind())
+		if err != nil {
+			return err
+		}
+		conflictHandler, ok := handler.(ConflictHandler)
+		if !ok {
+			notifier.NotSupported(resource, "conflict detection")
+			continue
+		}
 
+		conflicts, err := conflictHandler.Conflicts(resource)
+		if err != nil {
+			finalErr = multierror.Append(finalErr, fmt.Errorf("%s: %w", resource.Ref(), err))
+			notifier.Error(resource.Ref(), fmt.Sprintf("could not check conflicts: %s", err))
+			continue
+		}
+		if len(conflicts) == 0 {
+			clear++
+			notifier.Info(resource.Ref(), "no conflicts")
+			continue
+		}
+		conflicting++
+		for _, conflict := range conflicts {
+			notifier.Warn(resource.Ref(), conflict)
+		}
+	}
+
+	notifier.Info(nil, fmt.Sprintf("%d without conflicts, %d with potential conflicts", clear, conflicting))
+	if conflicting > 0 {
+		finalErr = multierror.Append(finalErr, fmt.Errorf("%d resources may collide with remote ones", conflicting))
+	}
 	return finalErr
 }
 
-func exportResource(eventsRecorder EventsRecorder, registry Registry, exportDir string, resource Resource, onlySpec bool, outputFormat string) error {
-	updatedResourceBytes, _, extension, err := Format(registry, "", &resource, outputFormat, onlySpec)
+// CheckHealth checks whether the remote versions of resources work, such as
+// datasources right after they were provisioned, reporting the outcome for
+// each of them. It fails if any check fails or couldn't run.
+func CheckHealth(ctx context.Context, registry Registry, resources Resources) error {
+	var finalErr error
+	healthy, failing := 0, 0
+
+	registry = registry.WithContext(ctx)
+	for _, resource := range resources.AsList() {
+		if err := ctx.Err(); err != nil {
+			return multierror.Append(finalErr, err)
+		}
+
+		handler, err := registry.GetHandler(resource.Kind())
+		if err != nil {
+			return err
+		}
+		healthCheckHandler, ok := handler.(HealthCheckHandler)
+		if !ok {
+			notifier.NotSupported(resource, "health checks")
+			continue
+		}
+
+		isHealthy, message, err := healthCheckHandler.CheckHealth(resource)
+		if errors.Is(err, ErrNotFound) {
+			failing++
+			notifier.NotFound(resource.Ref())
+			continue
+		}
+		if err != nil {
+			finalErr = multierror.Append(finalErr, fmt.Errorf("%s: %w", resource.Ref(), err))
+			notifier.Error(resource.Ref(), fmt.Sprintf("could not check health: %s", err))
+			continue
+		}
+		if message == "" {
+			message = "health check failed"
+			if isHealthy {
+				message = "health check passed"
+			}
+		}
+		if !isHealthy {
+			failing++
+			notifier.Error(resource.Ref(), message)
+			continue
+		}
+		healthy++
+		notifier.Info(resource.Ref(), message)
+	}
+
+	notifier.Info(nil, fmt.Sprintf("%d healthy, %d failing", healthy, failing))
+	if failing > 0 {
+		finalErr = multierror.Append(finalErr, fmt.Errorf("%d resources failed their health check", failing))
+	}
+	return finalErr
+}
+
+// Snapshot pushes resources to endpoints as snapshots, if supported
+func Snapshot(ctx context.Context, registry Registry, resources Resources, opts SnapshotOptions) error {
+	registry = registry.WithContext(ctx)
+
+	for _, resource := range resources.AsList() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		handler, err := registry.GetHandler(resource.Kind())
+		if err != nil {
+			return err
+		}
+		snapshotHandler, ok := handler.(SnapshotHandler)
+		if !ok {
+			notifier.NotSupported(resource, "snapshot")
+			continue
+		}
+		err = snapshotHandler.Snapshot(resource, opts)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render renders resources as PNG images written to a directory, under
+// <kind>/<name>.png, if supported. Resources are rendered as they are on the
+// remote endpoint, so they must have been applied first.
+func Render(ctx context.Context, registry Registry, resources Resources, outputDir string, opts RenderOptions) error {
+	registry = registry.WithContext(ctx)
+
+	for _, resource := range resources.AsList() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		handler, err := registry.GetHandler(resource.Kind())
+		if err != nil {
+			return err
+		}
+		renderHandler, ok := handler.(RenderHandler)
+		if !ok {
+			notifier.NotSupported(resource, "rendering")
+			continue
+		}
+		image, err := renderHandler.Render(resource, opts)
+		if errors.Is(err, ErrNotImplemented) {
+			notifier.NotSupported(resource, "rendering")
+			notifier.Warn(resource, err.Error())
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		filename := fmt.Sprintf("%s.png", resource.Name())
+		if opts.PanelID != 0 {
+			filename = fmt.Sprintf("%s-panel-%d.png", resource.Name(), opts.PanelID)
+		}
+		path := filepath.Join(outputDir, resource.Kind(), filename)
+		if err := WriteFile(path, image); err != nil {
+			return err
+		}
+		notifier.Info(resource, "rendered to "+path)
+	}
+	return nil
+}
+
+type listedSnapshot struct {
+	Kind         string `yaml:"kind" json:"kind"`
+	SnapshotInfo `yaml:",inline"`
+}
+
+// ListSnapshots outputs the snapshots stored by remote endpoints
+func ListSnapshots(ctx context.Context, registry Registry, format string) error {
+	registry = registry.WithContext(ctx)
+
+	listedSnapshots := []listedSnapshot{}
+	for _, name := range snapshotManagerNames(registry) {
+		snapshots, err := registry.Handlers[name].(SnapshotManager).ListSnapshots()
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range snapshots {
+			listedSnapshots = append(listedSnapshots, listedSnapshot{Kind: name, SnapshotInfo: snapshot})
+		}
+	}
+
+	var output []byte
+	var err error
+	switch format {
+	case formatYAML:
+		output, err = yaml.Marshal(listedSnapshots)
+	case formatJSON:
+		output, err = json.MarshalIndent(listedSnapshots, "", "  ")
+	case formatDefault:
+		var out bytes.Buffer
+		w := tabwriter.NewWriter(&out, 0, 0, 4, ' ', 0)
+		f := "%s\t%s\t%s\t%s\n"
+		fmt.Fprintf(w, f, "KIND", "KEY", "NAME", "EXPIRES")
+		for _, snapshot := range listedSnapshots {
+			fmt.Fprintf(w, f, snapshot.Kind, snapshot.Key, snapshot.Name, snapshot.Expires)
+		}
+		err = w.Flush()
+		output = out.Bytes()
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
 	if err != nil {
 		return err
 	}
+	fmt.Fprintln(outputWriter, string(output))
+	return nil
+}
 
-	dir := fmt.Sprintf("%s/%s", exportDir, resource.Kind())
+// DeleteSnapshot deletes a snapshot, by key, from the endpoint storing it
+func DeleteSnapshot(ctx context.Context, registry Registry, key string) error {
+	registry = registry.WithContext(ctx)
+
+	for _, name := range snapshotManagerNames(registry) {
+		err := registry.Handlers[name].(SnapshotManager).DeleteSnapshot(key)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		notifier.Info(nil, fmt.Sprintf("Snapshot %s deleted", key))
+		return nil
+	}
+	return fmt.Errorf("snapshot %s not found", key)
+}
+
+// snapshotManagerNames returns the sorted names of the handlers able to
+// manage snapshots
+func snapshotManagerNames(registry Registry) []string {
+	names := []string{}
+	for name, handler := range registry.Handlers {
+		if _, ok := handler.(SnapshotManager); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+const (
+	// WatchModeApply applies resources when changes are noticed
+	WatchModeApply = "apply"
+	// WatchModeDiff shows how resources differ from their remote equivalent
+	// when changes are noticed, without applying them
+	WatchModeDiff = "diff"
+	// WatchModeValidate validates resources when changes are noticed
+	WatchModeValidate = "validate"
+)
+
+// Watch watches a directory for changes then, when changes are noticed,
+// performs the action selected by mode (apply, diff or validate) on the
+// Jsonnet resources, until the context is done. An empty mode applies them.
+func Watch(ctx context.Context, registry Registry, watchDir string, resourcePath string, mode string, parser Parser, parserOpts ParserOptions, trailRecorder EventsRecorder) error {
+	var action func(resources Resources) error
+	switch mode {
+	case "", WatchModeApply:
+		mode = WatchModeApply
+		action = func(resources Resources) error {
+			return Apply(ctx, registry, resources, ApplyOptions{}, trailRecorder)
+		}
+	case WatchModeDiff:
+		action = func(resources Resources) error {
+			return Diff(ctx, registry, resources, DiffOptions{})
+		}
+	case WatchModeValidate:
+		action = func(resources Resources) error {
+			return Validate(ctx, registry, resources, false)
+		}
+	default:
+		return fmt.Errorf("unknown watch mode %q, expected one of: %s, %s, %s", mode, WatchModeApply, WatchModeDiff, WatchModeValidate)
+	}
+
+	updateWatchedResource := func(path string) error {
+		notifier.Info(nil, fmt.Sprintf("Changes detected in %q. Running %s on %q", path, mode, resourcePath))
+		resources, err := parser.Parse(resourcePath, parserOpts)
+		if err != nil {
+			notifier.Error(nil, fmt.Sprintf("Error parsing resource file: %s", err))
+			return nil
+		}
+		if err := action(resources); err != nil {
+			notifier.Error(nil, fmt.Sprintf("Error running %s on resources: %s", mode, err))
+		}
+		return nil
+	}
+	watcher, err := NewWatcher(updateWatchedResource)
+	if err != nil {
+		return err
+	}
+	err = watcher.Add(watchDir)
+	if err != nil {
+		return err
+	}
+	err = watcher.Watch()
+	if err != nil {
+		return err
+	}
+	err = watcher.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+const (
+	// ExportLayoutDirectory writes one file per resource, in a directory per kind
+	ExportLayoutDirectory = "directory"
+	// ExportLayoutStream writes all resources to a single file: a YAML stream
+	// of documents, or a JSON array
+	ExportLayoutStream = "stream"
+)
+
+// ExportOptions holds the options driving how resources are exported
+type ExportOptions struct {
+	OnlySpec        bool
+	OutputFormat    string
+	ContinueOnError bool
+	// Layout selects how exported resources are laid out on disk. Defaults to
+	// ExportLayoutDirectory.
+	Layout string
+	// Selector narrows down the resources to export
+	Selector Selector
+	// Folder, when set, moves the resources stored in folders (ex: dashboards)
+	// to this folder, referenced by UID or title
+	Folder string
+	// Provisioning also writes a manifest allowing Grafana's file-based
+	// provisioning to read the exported dashboards
+	Provisioning bool
+	// SkipExisting skips the resources whose file already exists in the
+	// export directory, without comparing it nor, when exporting remote
+	// resources, fetching them. This makes resuming an interrupted export cheap.
+	SkipExisting bool
+	// Workers is the number of resources written concurrently with the
+	// directory layout. Events are still reported in order. Defaults to 1.
+	Workers int
+	// ExcludeFolders lists folders, referenced by UID or title, whose
+	// resources are skipped when exporting remote resources
+	ExcludeFolders []string
+	// Raw writes remote resources exactly as the endpoints return them, for
+	// the handlers supporting it (see RawRemoteHandler). It only applies to
+	// ExportRemote, and requires JSON files holding only the spec.
+	Raw bool
+
+	// rawContents holds the raw remote resources fetched by ExportRemote
+	rawContents map[ResourceRef][]byte
+}
+
+// Export renders Jsonnet resources then saves them to a directory, or to a
+// single file when using ExportLayoutStream
+func Export(ctx context.Context, eventsRecorder EventsRecorder, registry Registry, exportPath string, resources Resources, opts ExportOptions) error {
+	resources = opts.Selector.Filter(resources)
+	resources = overrideFolder(registry, resources, opts.Folder)
+
+	if opts.Provisioning {
+		if err := validateProvisioningExport(opts); err != nil {
+			return err
+		}
+	}
+	if opts.SkipExisting && opts.Layout == ExportLayoutStream {
+		return fmt.Errorf("existing files can only be skipped with the %s layout", ExportLayoutDirectory)
+	}
+	if opts.Raw {
+		if opts.Layout == ExportLayoutStream {
+			return fmt.Errorf("raw resources can only be exported with the %s layout", ExportLayoutDirectory)
+		}
+		if err := validateRawFormat(opts.OutputFormat, opts.OnlySpec); err != nil {
+			return err
+		}
+	}
+	if opts.OutputFormat == formatTerraform {
+		resources = terraformResources(eventsRecorder, resources)
+	}
+
+	switch opts.Layout {
+	case "", ExportLayoutDirectory:
+		if err := exportDirectory(ctx, eventsRecorder, registry, exportPath, resources, opts); err != nil {
+			return err
+		}
+		if opts.Provisioning {
+			return writeProvisioningManifest(exportPath)
+		}
+		return nil
+	case ExportLayoutStream:
+		return exportStream(eventsRecorder, exportPath, resources, opts)
+	default:
+		return fmt.Errorf("unknown export layout: %s", opts.Layout)
+	}
+}
+
+// ExportRemote fetches all the remote resources matching the given targets,
+// then saves them using the same layout as Export
+func ExportRemote(ctx context.Context, eventsRecorder EventsRecorder, registry Registry, exportPath string, targets []string, opts ExportOptions) error {
+	resources := NewResources()
+	registry = registry.WithContext(ctx)
+	opts.rawContents = map[ResourceRef][]byte{}
+
+	var skip func(handler Handler, uid string) (bool, error)
+	if opts.SkipExisting && opts.Layout != ExportLayoutStream {
+		skip = func(handler Handler, uid string) (bool, error) {
+			exists, err := exportedFileExists(exportPath, handler.Kind(), uid, opts.OutputFormat)
+			if err != nil || !exists {
+				return false, err
+			}
+			eventsRecorder.Record(Event{
+				Type:        ResourceSkipped,
+				ResourceRef: NewResourceRef(handler.Kind(), uid).String(),
+				Details:     "already exported",
+			})
+			return true, nil
+		}
+	}
+
+	finalErr := fetchRemotes(ctx, eventsRecorder, registry, remoteOptions{
+		targets:         targets,
+		excludeFolders:  opts.ExcludeFolders,
+		raw:             opts.Raw,
+		continueOnError: opts.ContinueOnError,
+		skip:            skip,
+	}, func(resource *Resource, rawContent []byte) error {
+		if rawContent != nil {
+			opts.rawContents[resource.Ref()] = rawContent
+		}
+		resources.Add(*resource)
+		return nil
+	})
+	if finalErr != nil && (!opts.ContinueOnError || ctx.Err() != nil) {
+		return finalErr
+	}
+
+	if err := Export(ctx, eventsRecorder, registry, exportPath, resources, opts); err != nil {
+		finalErr = multierror.Append(finalErr, err)
+	}
+
+	return finalErr
+}
+
+// rawRemoteHandler returns the handler as a RawRemoteHandler when raw
+// resources are requested, and announces the handlers not supporting it,
+// whose resources are written as usual
+func rawRemoteHandler(handler Handler, raw bool) RawRemoteHandler {
+	if !raw {
+		return nil
+	}
+	rawHandler, ok := handler.(RawRemoteHandler)
+	if !ok {
+		notifier.NotSupported(notifier.SimpleString(handler.Kind()), "raw resources, they are written as usual")
+		return nil
+	}
+	return rawHandler
+}
+
+// validateRawFormat checks that raw resources can be written in a format:
+// they are written as returned by the endpoints, which is JSON, without
+// envelope
+func validateRawFormat(outputFormat string, onlySpec bool) error {
+	if outputFormat != formatJSON || !onlySpec {
+		return fmt.Errorf("raw resources can only be written as JSON, without envelope (-o json --only-spec)")
+	}
+	return nil
+}
+
+// exportOutcome is the result of exporting a resource
+type exportOutcome struct {
+	event Event
+	err   error
+	// cancelled is set when the resource wasn't exported, as the context was done
+	cancelled bool
+}
+
+func exportDirectory(ctx context.Context, eventsRecorder EventsRecorder, registry Registry, exportDir string, resources Resources, opts ExportOptions) error {
+	if err := utils.EnsureDirectoryExists(exportDir, 0755); err != nil {
+		return err
+	}
+
+	workers := max(opts.Workers, 1)
+	ctx, cancel := context.WithCancel(ctx)
+
+	// resources are written concurrently, but their outcome is reported in
+	// order: each resource gets its own buffered channel
+	list := resources.AsList()
+	outcomes := make([]chan exportOutcome, len(list))
+	for i := range outcomes {
+		outcomes[i] = make(chan exportOutcome, 1)
+	}
+	directories := &exportDirectories{created: map[string]bool{}}
+
+	dispatched := make(chan struct{})
+	go func() {
+		defer close(dispatched)
+		group := errgroup.Group{}
+		group.SetLimit(workers)
+		for i, resource := range list {
+			if err := ctx.Err(); err != nil {
+				outcomes[i] <- exportOutcome{err: err, cancelled: true}
+				continue
+			}
+			group.Go(func() error {
+				// the export may have stopped while waiting for a worker
+				if err := ctx.Err(); err != nil {
+					outcomes[i] <- exportOutcome{err: err, cancelled: true}
+					return nil
+				}
+				event, err := exportResource(registry, exportDir, directories, resource, opts)
+				outcomes[i] <- exportOutcome{event: event, err: err}
+				return nil
+			})
+		}
+		_ = group.Wait()
+	}()
+	// when stopping early, no resource is written once the export returns:
+	// the pending ones aren't dispatched, and those being written are waited for
+	defer func() {
+		cancel()
+		<-dispatched
+	}()
+
+	var finalErr error
+	for i, resource := range list {
+		outcome := <-outcomes[i]
+		if outcome.cancelled {
+			return multierror.Append(finalErr, outcome.err)
+		}
+		if outcome.err == nil {
+			eventsRecorder.Record(outcome.event)
+			continue
+		}
+
+		finalErr = multierror.Append(finalErr, outcome.err)
+
+		eventsRecorder.Record(Event{
+			Type:        ResourceFailure,
+			ResourceRef: resource.Ref().String(),
+			Details:     outcome.err.Error(),
+		})
+
+		if !opts.ContinueOnError {
+			return finalErr
+		}
+	}
+
+	return finalErr
+}
+
+// exportDirectories creates the directories resources are exported to, one
+// at a time, as resources may be exported concurrently
+type exportDirectories struct {
+	mu      sync.Mutex
+	created map[string]bool
+}
+
+func (d *exportDirectories) ensure(dir string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.created[dir] {
+		return nil
+	}
 	if err := utils.EnsureDirectoryExists(dir, 0755); err != nil {
 		return err
 	}
+	d.created[dir] = true
+	return nil
+}
 
-	path := fmt.Sprintf("%s/%s.%s", dir, resource.Name(), extension)
+// exportStream writes all resources into a single file
+func exportStream(eventsRecorder EventsRecorder, exportFile string, resources Resources, opts ExportOptions) error {
+	content, err := formatStream(resources, opts.OutputFormat, opts.OnlySpec)
+	if err != nil {
+		return err
+	}
 
-	existingResourceBytes, err := os.ReadFile(path)
+	existingContent, err := os.ReadFile(exportFile)
 	isNotExist := os.IsNotExist(err)
 	if err != nil && !isNotExist {
 		return err
 	}
 
-	if string(existingResourceBytes) == string(updatedResourceBytes) {
+	eventType := ResourceNotChanged
+	if string(existingContent) != string(content) {
+		if err := WriteFile(exportFile, content); err != nil {
+			return err
+		}
+		eventType = ResourceUpdated
+		if isNotExist {
+			eventType = ResourceAdded
+		}
+	}
+
+	for _, resource := range resources.AsList() {
 		eventsRecorder.Record(Event{
-			Type:        ResourceNotChanged,
+			Type:        eventType,
 			ResourceRef: resource.Ref().String(),
 		})
-		return nil
+	}
+
+	return nil
+}
+
+// formatStream renders resources as a single document: a JSON array, a YAML
+// stream with one document per resource, or a Terraform file
+func formatStream(resources Resources, format string, onlySpec bool) ([]byte, error) {
+	if format == formatTerraform {
+		return formatTerraformStream(resources)
+	}
+
+	bodies := make([]any, 0, resources.Len())
+	for _, resource := range resources.AsList() {
+		if onlySpec {
+			bodies = append(bodies, resource.Spec())
+		} else {
+			bodies = append(bodies, resource.Body)
+		}
+	}
+
+	if format == formatJSON {
+		return json.MarshalIndent(bodies, "", "  ")
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	for _, body := range bodies {
+		if err := encoder.Encode(body); err != nil {
+			return nil, err
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportResource writes a resource to the directory of its kind, and returns
+// the event describing the outcome
+func exportResource(registry Registry, exportDir string, directories *exportDirectories, resource Resource, opts ExportOptions) (Event, error) {
+	event := Event{ResourceRef: resource.Ref().String()}
+
+	var updatedResourceBytes []byte
+	var extension string
+	var err error
+	if opts.OutputFormat == formatTerraform {
+		updatedResourceBytes, err = formatTerraformDashboard(resource)
+		extension = terraformExtension
+	} else {
+		updatedResourceBytes, _, extension, err = Format(registry, "", &resource, opts.OutputFormat, opts.OnlySpec)
+	}
+	if err != nil {
+		return event, err
+	}
+
+	if content, ok := opts.rawContents[resource.Ref()]; ok {
+		updatedResourceBytes = content
+	}
+
+	handler, err := registry.GetHandler(resource.Kind())
+	if err != nil {
+		return event, err
+	}
+
+	dir := fmt.Sprintf("%s/%s", exportDir, resource.Kind())
+	// resources living within a folder are grouped by folder, to keep the
+	// layout they have on the remote system
+	if folder := resource.GetMetadata("folder"); handler.UsesFolders() && folder != "" {
+		dir = fmt.Sprintf("%s/%s", dir, folder)
+	}
+	if err := directories.ensure(dir); err != nil {
+		return event, err
+	}
+
+	path := fmt.Sprintf("%s/%s.%s", dir, resource.Name(), extension)
+
+	existingResourceBytes, err := os.ReadFile(path)
+	isNotExist := os.IsNotExist(err)
+	if err != nil && !isNotExist {
+		return event, err
+	}
+
+	if opts.SkipExisting && !isNotExist {
+		event.Type = ResourceSkipped
+		event.Details = "already exported"
+		return event, nil
+	}
+
+	if string(existingResourceBytes) == string(updatedResourceBytes) {
+		event.Type = ResourceNotChanged
+		return event, nil
 	}
 
 	err = os.WriteFile(path, updatedResourceBytes, 0644)
 	if err != nil {
-		return err
+		return event, err
 	}
 
-	eventType := ResourceUpdated
+	event.Type = ResourceUpdated
 	if isNotExist {
-		eventType = ResourceAdded
+		event.Type = ResourceAdded
 	}
+	return event, nil
+}
 
-	eventsRecorder.Record(Event{
-		Type:        eventType,
-		ResourceRef: resource.Ref().String(),
-	})
+// exportedFileExists checks whether a resource was already exported to
+// exportDir, without knowing its folder: it is looked for at the root of the
+// directory of its kind, then in its sub-directories.
+func exportedFileExists(exportDir, kind, uid, outputFormat string) (bool, error) {
+	extension := formatYAML
+	switch outputFormat {
+	case formatJSON:
+		extension = formatJSON
+	case formatTerraform:
+		extension = terraformExtension
+	}
+	filename := fmt.Sprintf("%s.%s", uid, extension)
+	kindDir := filepath.Join(exportDir, kind)
 
-	return nil
+	if exists, err := isFile(filepath.Join(kindDir, filename)); exists || err != nil {
+		return exists, err
+	}
+
+	entries, err := os.ReadDir(kindDir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if exists, err := isFile(filepath.Join(kindDir, entry.Name(), filename)); exists || err != nil {
+			return exists, err
+		}
+	}
+	return false, nil
 }
 
 func isFile(resourcePath string) (bool, error) {
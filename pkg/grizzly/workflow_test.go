@@ -0,0 +1,1214 @@
+package grizzly_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/grizzly/pkg/grizzly"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeKind = "Fake"
+
+type fakeProvider struct {
+	handler *fakeHandler
+}
+
+func (p *fakeProvider) Name() string                   { return "Fake" }
+func (p *fakeProvider) Group() string                  { return "grizzly.fake.com" }
+func (p *fakeProvider) Version() string                { return "v1alpha1" }
+func (p *fakeProvider) APIVersion() string             { return p.Group() + "/" + p.Version() }
+func (p *fakeProvider) GetHandlers() []grizzly.Handler { return []grizzly.Handler{p.handler} }
+func (p *fakeProvider) Validate() error                { return nil }
+
+func (p *fakeProvider) Status() grizzly.ProviderStatus {
+	return grizzly.ProviderStatus{Active: true, Online: true}
+}
+
+func (p *fakeProvider) registry() grizzly.Registry {
+	return grizzly.NewRegistry([]grizzly.Provider{p})
+}
+
+func (p *fakeProvider) resource(name string, spec map[string]any) grizzly.Resource {
+	resource, _ := grizzly.NewResource(p.APIVersion(), fakeKind, name, spec)
+	return resource
+}
+
+// fakeHandler stores resources in memory, and records the calls made to
+// mutate them.
+type fakeHandler struct {
+	grizzly.BaseHandler
+
+	remote  map[string]grizzly.Resource
+	ignore  []string
+	added   []string
+	updated []string
+
+	snapshots map[string]grizzly.SnapshotInfo
+	// readOnly rejects changes, as a read-only replica would
+	readOnly bool
+	// noRenderer makes rendering unsupported, as without an image renderer
+	noRenderer bool
+	// history holds the previous versions of remote resources
+	history map[string][]grizzly.Resource
+}
+
+func newFakeProvider(remote ...grizzly.Resource) *fakeProvider {
+	provider := &fakeProvider{}
+	provider.handler = &fakeHandler{
+		BaseHandler: grizzly.NewBaseHandler(provider, fakeKind, false),
+		remote:      map[string]grizzly.Resource{},
+		snapshots:   map[string]grizzly.SnapshotInfo{},
+		history:     map[string][]grizzly.Resource{},
+	}
+	for _, resource := range remote {
+		provider.handler.remote[resource.Name()] = resource
+	}
+	return provider
+}
+
+func (h *fakeHandler) ResourceFilePath(resource grizzly.Resource, filetype string) string {
+	return fmt.Sprintf("fakes/%s.%s", resource.Name(), filetype)
+}
+
+func (h *fakeHandler) GetSpecUID(resource grizzly.Resource) (string, error) {
+	uid, ok := resource.GetSpecString("uid")
+	if !ok {
+		return "", fmt.Errorf("UID not specified")
+	}
+	return uid, nil
+}
+
+func (h *fakeHandler) GetByUID(uid string) (*grizzly.Resource, error) {
+	resource, ok := h.remote[uid]
+	if !ok {
+		return nil, grizzly.ErrNotFound
+	}
+	return &resource, nil
+}
+
+func (h *fakeHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
+	return h.GetByUID(resource.Name())
+}
+
+func (h *fakeHandler) ListRemote() ([]string, error) {
+	uids := make([]string, 0, len(h.remote))
+	for uid := range h.remote {
+		uids = append(uids, uid)
+	}
+	return uids, nil
+}
+
+func (h *fakeHandler) Add(resource grizzly.Resource) error {
+	if h.readOnly {
+		return fmt.Errorf("status 403: %w", grizzly.ErrWriteRejected)
+	}
+	h.added = append(h.added, resource.Name())
+	h.remote[resource.Name()] = resource
+	return nil
+}
+
+func (h *fakeHandler) Update(existing, resource grizzly.Resource) error {
+	if h.readOnly {
+		return fmt.Errorf("status 403: %w", grizzly.ErrWriteRejected)
+	}
+	h.updated = append(h.updated, resource.Name())
+	h.remote[resource.Name()] = resource
+	return nil
+}
+
+func (h *fakeHandler) Validate(resource grizzly.Resource) error {
+	return nil
+}
+
+// ValidateRemote rejects titles already used by other remote resources
+func (h *fakeHandler) ValidateRemote(resource grizzly.Resource) error {
+	title, _ := resource.GetSpecString("title")
+	for uid, remote := range h.remote {
+		if remoteTitle, _ := remote.GetSpecString("title"); uid != resource.Name() && remoteTitle == title {
+			return fmt.Errorf("title %s is used by %s", title, uid)
+		}
+	}
+	return nil
+}
+
+func (h *fakeHandler) Conflicts(resource grizzly.Resource) ([]string, error) {
+	if _, ok := h.remote[resource.Name()]; ok {
+		return []string{fmt.Sprintf("%s already exists", resource.Name())}, nil
+	}
+	return nil, nil
+}
+
+func (h *fakeHandler) CheckHealth(resource grizzly.Resource) (bool, string, error) {
+	remote, ok := h.remote[resource.Name()]
+	if !ok {
+		return false, "", grizzly.ErrNotFound
+	}
+	if broken, _ := remote.GetSpecValue("broken").(bool); broken {
+		return false, "connection refused", nil
+	}
+	return true, "connected", nil
+}
+
+func (h *fakeHandler) UnresolvedReferences(resource grizzly.Resource, resources grizzly.Resources) []string {
+	references, _ := resource.GetSpecValue("references").([]any)
+	var unresolved []string
+	for _, reference := range references {
+		if _, found := resources.Find(grizzly.NewResourceRef(fakeKind, fmt.Sprint(reference))); !found {
+			unresolved = append(unresolved, fmt.Sprintf("references %s, which is missing", reference))
+		}
+	}
+	return unresolved
+}
+
+func (h *fakeHandler) DiffIgnorePaths() []string {
+	return h.ignore
+}
+
+func (h *fakeHandler) Snapshot(resource grizzly.Resource, opts grizzly.SnapshotOptions) error {
+	key := "snapshot-" + resource.Name()
+	h.snapshots[key] = grizzly.SnapshotInfo{Key: key, Name: opts.Name}
+	return nil
+}
+
+func (h *fakeHandler) Render(resource grizzly.Resource, opts grizzly.RenderOptions) ([]byte, error) {
+	if h.noRenderer {
+		return nil, fmt.Errorf("no image renderer: %w", grizzly.ErrNotImplemented)
+	}
+	return []byte(fmt.Sprintf("%s@%s", resource.Name(), opts.Theme)), nil
+}
+
+// GetRemoteRaw returns remote resources with their keys out of order, as
+// re-encoding them would sort them
+func (h *fakeHandler) GetRemoteRaw(uid string) ([]byte, error) {
+	if _, ok := h.remote[uid]; !ok {
+		return nil, grizzly.ErrNotFound
+	}
+	return []byte(fmt.Sprintf(`{"zeta":1.50,"uid":%q}`, uid)), nil
+}
+
+func (h *fakeHandler) ListSnapshots() ([]grizzly.SnapshotInfo, error) {
+	snapshots := make([]grizzly.SnapshotInfo, 0, len(h.snapshots))
+	for _, snapshot := range h.snapshots {
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+func (h *fakeHandler) DeleteSnapshot(key string) error {
+	if _, ok := h.snapshots[key]; !ok {
+		return grizzly.ErrNotFound
+	}
+	delete(h.snapshots, key)
+	return nil
+}
+
+func (h *fakeHandler) Rollback(uid string, version int64) (*grizzly.Resource, int64, error) {
+	versions := h.history[uid]
+	if version < 1 || int(version) > len(versions) {
+		return nil, 0, grizzly.ErrNotFound
+	}
+	restored := versions[version-1].Clone()
+	h.history[uid] = append(versions, restored)
+	h.remote[uid] = restored
+	return &restored, int64(len(h.history[uid])), nil
+}
+
+func (h *fakeHandler) Versions(uid string) ([]grizzly.Version, error) {
+	history, ok := h.history[uid]
+	if !ok {
+		return nil, grizzly.ErrNotFound
+	}
+	versions := make([]grizzly.Version, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		title, _ := history[i].GetSpecString("title")
+		versions = append(versions, grizzly.Version{Version: int64(i + 1), Author: "admin", Message: title})
+	}
+	return versions, nil
+}
+
+type fakeRecorder struct {
+	events []grizzly.Event
+}
+
+func (r *fakeRecorder) Record(event grizzly.Event) {
+	r.events = append(r.events, event)
+}
+
+func (r *fakeRecorder) Summary() grizzly.Summary {
+	summary := grizzly.Summary{EventCounts: map[grizzly.EventType]int{}}
+	for _, event := range r.events {
+		summary.EventCounts[event.Type]++
+	}
+	return summary
+}
+
+func (r *fakeRecorder) count(eventType grizzly.EventType) int {
+	return r.Summary().EventCounts[eventType]
+}
+
+func TestApply(t *testing.T) {
+	unchanged := newFakeProvider().resource("unchanged", map[string]any{"title": "unchanged"})
+	changed := newFakeProvider().resource("changed", map[string]any{"title": "before"})
+
+	local := func(provider *fakeProvider) grizzly.Resources {
+		return grizzly.NewResources(
+			provider.resource("new", map[string]any{"title": "new"}),
+			provider.resource("unchanged", map[string]any{"title": "unchanged"}),
+			provider.resource("changed", map[string]any{"title": "after"}),
+		)
+	}
+
+	t.Run("resources are added and updated", func(t *testing.T) {
+		req := require.New(t)
+		provider := newFakeProvider(unchanged, changed)
+		recorder := &fakeRecorder{}
+
+		err := grizzly.Apply(context.Background(), provider.registry(), local(provider), grizzly.ApplyOptions{}, recorder)
+		req.NoError(err)
+
+		req.Equal([]string{"new"}, provider.handler.added)
+		req.Equal([]string{"changed"}, provider.handler.updated)
+		req.Equal(1, recorder.count(grizzly.ResourceAdded))
+		req.Equal(1, recorder.count(grizzly.ResourceUpdated))
+		req.Equal(1, recorder.count(grizzly.ResourceNotChanged))
+	})
+
+	t.Run("force updates unchanged resources", func(t *testing.T) {
+		req := require.New(t)
+		provider := newFakeProvider(unchanged, changed)
+		recorder := &fakeRecorder{}
+
+		err := grizzly.Apply(context.Background(), provider.registry(), local(provider), grizzly.ApplyOptions{Force: true}, recorder)
+		req.NoError(err)
+
+		req.Equal([]string{"new"}, provider.handler.added)
+		req.ElementsMatch([]string{"unchanged", "changed"}, provider.handler.updated)
+		req.Equal(1, recorder.count(grizzly.ResourceUpdated))
+		req.Equal(1, recorder.count(grizzly.ResourceForceUpdated), "forced updates are told apart from changes")
+		req.Equal(0, recorder.count(grizzly.ResourceNotChanged))
+	})
+
+	t.Run("dry-run does not mutate remote resources", func(t *testing.T) {
+		req := require.New(t)
+		provider := newFakeProvider(unchanged, changed)
+		recorder := &fakeRecorder{}
+
+		err := grizzly.Apply(context.Background(), provider.registry(), local(provider), grizzly.ApplyOptions{DryRun: true}, recorder)
+		req.NoError(err)
+
+		req.Empty(provider.handler.added)
+		req.Empty(provider.handler.updated)
+		req.Equal(1, recorder.count(grizzly.ResourceWouldBeAdded))
+		req.Equal(1, recorder.count(grizzly.ResourceWouldBeUpdated))
+		req.Equal(1, recorder.count(grizzly.ResourceNotChanged))
+	})
+
+	t.Run("hooks are called around each change", func(t *testing.T) {
+		req := require.New(t)
+		provider := newFakeProvider(unchanged, changed)
+		recorder := &fakeRecorder{}
+
+		var calls []string
+		hook := func(stage string) grizzly.ApplyHook {
+			return func(resource grizzly.Resource, action grizzly.ApplyAction) error {
+				calls = append(calls, fmt.Sprintf("%s %s %s", stage, action, resource.Name()))
+				return nil
+			}
+		}
+
+		err := grizzly.Apply(context.Background(), provider.registry(), local(provider), grizzly.ApplyOptions{
+			PreApply:  hook("pre"),
+			PostApply: hook("post"),
+		}, recorder)
+		req.NoError(err)
+
+		req.ElementsMatch([]string{
+			"pre add new", "post add new",
+			"pre update changed", "post update changed",
+		}, calls)
+	})
+
+	t.Run("pre-apply hooks can prevent changes", func(t *testing.T) {
+		req := require.New(t)
+		provider := newFakeProvider(unchanged, changed)
+		recorder := &fakeRecorder{}
+
+		err := grizzly.Apply(context.Background(), provider.registry(), local(provider), grizzly.ApplyOptions{
+			ContinueOnError: true,
+			PreApply: func(resource grizzly.Resource, action grizzly.ApplyAction) error {
+				if action == grizzly.ApplyActionAdd {
+					return errors.New("new resources are forbidden")
+				}
+				return nil
+			},
+		}, recorder)
+		req.ErrorContains(err, "pre-apply hook: new resources are forbidden")
+
+		req.Empty(provider.handler.added)
+		req.Equal([]string{"changed"}, provider.handler.updated)
+		req.Equal(1, recorder.count(grizzly.ResourceFailure))
+		req.Equal(1, recorder.count(grizzly.ResourceUpdated))
+	})
+
+	t.Run("writes rejected by read-only endpoints fail their resource", func(t *testing.T) {
+		req := require.New(t)
+		provider := newFakeProvider(unchanged, changed)
+		provider.handler.readOnly = true
+		recorder := &fakeRecorder{}
+
+		err := grizzly.Apply(context.Background(), provider.registry(), local(provider), grizzly.ApplyOptions{ContinueOnError: true}, recorder)
+		req.ErrorIs(err, grizzly.ErrWriteRejected)
+		var rejected grizzly.WriteRejectedError
+		req.ErrorAs(err, &rejected)
+		req.ErrorContains(err, "rejected the add as read-only")
+
+		req.Equal(2, recorder.count(grizzly.ResourceFailure), "each rejected write is reported")
+		req.Equal(1, recorder.count(grizzly.ResourceNotChanged))
+	})
+}
+
+func TestApplyIgnoredFields(t *testing.T) {
+	req := require.New(t)
+	remote := newFakeProvider().resource("dashboard", map[string]any{
+		"title":   "dashboard",
+		"version": 12,
+		"meta":    map[string]any{"updated": "yesterday"},
+	})
+	provider := newFakeProvider(remote)
+	provider.handler.ignore = []string{"version", "meta.updated"}
+	recorder := &fakeRecorder{}
+
+	local := provider.resource("dashboard", map[string]any{
+		"title":   "dashboard",
+		"version": 1,
+		"meta":    map[string]any{"updated": "today"},
+	})
+
+	err := grizzly.Apply(context.Background(), provider.registry(), grizzly.NewResources(local), grizzly.ApplyOptions{}, recorder)
+	req.NoError(err)
+
+	req.Empty(provider.handler.updated)
+	req.Equal(1, recorder.count(grizzly.ResourceNotChanged))
+	req.Equal(1, local.GetSpecValue("version"), "ignored fields must not be removed from the applied resource")
+}
+
+func TestApplyMaxResources(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider()
+	resources := grizzly.NewResources(
+		provider.resource("first", map[string]any{"title": "first"}),
+		provider.resource("second", map[string]any{"title": "second"}),
+	)
+
+	err := grizzly.Apply(context.Background(), provider.registry(), resources, grizzly.ApplyOptions{MaxResources: 1}, &fakeRecorder{})
+	req.ErrorIs(err, grizzly.ErrTooManyResources)
+	req.ErrorContains(err, "refusing to apply 2 resources, more than the maximum of 1")
+	req.Empty(provider.handler.added, "nothing is applied")
+
+	req.NoError(grizzly.Apply(context.Background(), provider.registry(), resources, grizzly.ApplyOptions{MaxResources: 2}, &fakeRecorder{}))
+	req.Len(provider.handler.added, 2)
+}
+
+func TestApplyCancelled(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider()
+	recorder := &fakeRecorder{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := grizzly.Apply(ctx, provider.registry(), grizzly.NewResources(
+		provider.resource("first", map[string]any{"title": "first"}),
+		provider.resource("second", map[string]any{"title": "second"}),
+	), grizzly.ApplyOptions{ContinueOnError: true}, recorder)
+	req.ErrorIs(err, context.Canceled)
+
+	req.Empty(provider.handler.added)
+	req.Equal(0, recorder.count(grizzly.ResourceFailure))
+}
+
+// slowProvider binds the fake handler to a context, and blocks on the
+// resources marked as slow until that context is done
+type slowProvider struct {
+	*fakeProvider
+	ctx  context.Context
+	slow map[string]bool
+}
+
+func (p *slowProvider) WithContext(ctx context.Context) grizzly.Provider {
+	return &slowProvider{fakeProvider: p.fakeProvider, ctx: ctx, slow: p.slow}
+}
+
+func (p *slowProvider) GetHandlers() []grizzly.Handler {
+	return []grizzly.Handler{&slowHandler{fakeHandler: p.handler, provider: p}}
+}
+
+type slowHandler struct {
+	*fakeHandler
+	provider *slowProvider
+}
+
+func (h *slowHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
+	if h.provider.slow[resource.Name()] && h.provider.ctx != nil {
+		<-h.provider.ctx.Done()
+		return nil, h.provider.ctx.Err()
+	}
+	return h.fakeHandler.GetRemote(resource)
+}
+
+func TestApplyResourceTimeout(t *testing.T) {
+	req := require.New(t)
+	provider := &slowProvider{fakeProvider: newFakeProvider(), slow: map[string]bool{"hung": true}}
+	registry := grizzly.NewRegistry([]grizzly.Provider{provider})
+	recorder := &fakeRecorder{}
+
+	err := grizzly.Apply(context.Background(), registry, grizzly.NewResources(
+		provider.resource("hung", map[string]any{"title": "hung"}),
+		provider.resource("fast", map[string]any{"title": "fast"}),
+	), grizzly.ApplyOptions{ContinueOnError: true, ResourceTimeout: 10 * time.Millisecond}, recorder)
+	req.ErrorIs(err, context.DeadlineExceeded)
+	req.ErrorContains(err, "timed out after 10ms")
+
+	req.Equal([]string{"fast"}, provider.handler.added, "the following resources are still applied")
+	req.Equal(1, recorder.count(grizzly.ResourceFailure))
+}
+
+// folderProvider stores the fake resources in folders
+type folderProvider struct {
+	*fakeProvider
+}
+
+func (p *folderProvider) GetHandlers() []grizzly.Handler {
+	return []grizzly.Handler{&folderHandler{fakeHandler: p.handler}}
+}
+
+type folderHandler struct {
+	*fakeHandler
+}
+
+func (h *folderHandler) UsesFolders() bool { return true }
+
+func TestRemoteExcludeFolders(t *testing.T) {
+	inFolder := func(name, folder string) grizzly.Resource {
+		resource := newFakeProvider().resource(name, map[string]any{"uid": name})
+		resource.SetMetadata("folder", folder)
+		return resource
+	}
+	registry := func() grizzly.Registry {
+		provider := &folderProvider{fakeProvider: newFakeProvider(
+			inFolder("kept", "team-a"),
+			inFolder("excluded", "generated"),
+		)}
+		return grizzly.NewRegistry([]grizzly.Provider{provider})
+	}
+
+	t.Run("export", func(t *testing.T) {
+		req := require.New(t)
+		recorder := &fakeRecorder{}
+
+		exportDir := t.TempDir()
+		err := grizzly.ExportRemote(context.Background(), recorder, registry(), exportDir, nil, grizzly.ExportOptions{
+			OutputFormat:   "yaml",
+			ExcludeFolders: []string{"generated"},
+		})
+		req.NoError(err)
+
+		req.Equal(1, recorder.count(grizzly.ResourceSkipped))
+		exported, err := filepath.Glob(filepath.Join(exportDir, fakeKind, "*", "*"))
+		req.NoError(err)
+		req.Len(exported, 1)
+		req.Contains(exported[0], "kept")
+	})
+
+	t.Run("pull", func(t *testing.T) {
+		req := require.New(t)
+		recorder := &fakeRecorder{}
+
+		pullDir := t.TempDir()
+		err := grizzly.Pull(context.Background(), registry(), pullDir, false, "yaml", false, nil, []string{"generated"}, false, recorder)
+		req.NoError(err)
+
+		req.Equal(1, recorder.count(grizzly.ResourceSkipped))
+		req.Equal(1, recorder.count(grizzly.ResourcePulled))
+		pulled, err := filepath.Glob(filepath.Join(pullDir, "*", "*"))
+		req.NoError(err)
+		req.Len(pulled, 1)
+		req.Contains(pulled[0], "kept")
+	})
+}
+
+func TestExportStream(t *testing.T) {
+	provider := newFakeProvider()
+	resources := grizzly.NewResources(
+		provider.resource("first", map[string]any{"uid": "first"}),
+		provider.resource("second", map[string]any{"uid": "second"}),
+	)
+
+	t.Run("yaml resources are written as a stream of documents", func(t *testing.T) {
+		req := require.New(t)
+		exportFile := filepath.Join(t.TempDir(), "backup.yaml")
+		recorder := &fakeRecorder{}
+
+		err := grizzly.Export(context.Background(), recorder, provider.registry(), exportFile, resources, grizzly.ExportOptions{
+			OnlySpec:     true,
+			OutputFormat: "yaml",
+			Layout:       grizzly.ExportLayoutStream,
+		})
+		req.NoError(err)
+
+		content, err := os.ReadFile(exportFile)
+		req.NoError(err)
+		req.Equal("uid: first\n---\nuid: second\n", string(content))
+		req.Equal(2, recorder.count(grizzly.ResourceAdded))
+	})
+
+	t.Run("json resources are written as an array", func(t *testing.T) {
+		req := require.New(t)
+		exportFile := filepath.Join(t.TempDir(), "backup.json")
+
+		err := grizzly.Export(context.Background(), &fakeRecorder{}, provider.registry(), exportFile, resources, grizzly.ExportOptions{
+			OnlySpec:     true,
+			OutputFormat: "json",
+			Layout:       grizzly.ExportLayoutStream,
+		})
+		req.NoError(err)
+
+		content, err := os.ReadFile(exportFile)
+		req.NoError(err)
+		req.JSONEq(`[{"uid": "first"}, {"uid": "second"}]`, string(content))
+
+		recorder := &fakeRecorder{}
+		err = grizzly.Export(context.Background(), recorder, provider.registry(), exportFile, resources, grizzly.ExportOptions{
+			OnlySpec:     true,
+			OutputFormat: "json",
+			Layout:       grizzly.ExportLayoutStream,
+		})
+		req.NoError(err)
+		req.Equal(2, recorder.count(grizzly.ResourceNotChanged))
+	})
+}
+
+func TestExportProvisioning(t *testing.T) {
+	provider := newFakeProvider()
+	resources := grizzly.NewResources(provider.resource("first", map[string]any{"uid": "first"}))
+
+	t.Run("a provisioning manifest is written next to the resources", func(t *testing.T) {
+		req := require.New(t)
+		exportDir := t.TempDir()
+
+		err := grizzly.Export(context.Background(), &fakeRecorder{}, provider.registry(), exportDir, resources, grizzly.ExportOptions{
+			OnlySpec:     true,
+			OutputFormat: "json",
+			Provisioning: true,
+		})
+		req.NoError(err)
+
+		content, err := os.ReadFile(filepath.Join(exportDir, grizzly.ProvisioningManifestFile))
+		req.NoError(err)
+		req.Contains(string(content), "path: "+filepath.Join(exportDir, "Dashboard"))
+		req.Contains(string(content), "foldersFromFilesStructure: true")
+	})
+
+	t.Run("resources must be exported as plain JSON", func(t *testing.T) {
+		err := grizzly.Export(context.Background(), &fakeRecorder{}, provider.registry(), t.TempDir(), resources, grizzly.ExportOptions{
+			OutputFormat: "yaml",
+			Provisioning: true,
+		})
+		require.ErrorContains(t, err, "-o json --only-spec")
+	})
+}
+
+func TestExportTerraform(t *testing.T) {
+	provider := newFakeProvider()
+	provider.handler.BaseHandler = grizzly.NewBaseHandler(provider, "Dashboard", true)
+	dashboard, err := grizzly.NewResource(provider.APIVersion(), "Dashboard", "42-cpu.usage", map[string]any{
+		"uid":   "42-cpu.usage",
+		"title": "${host} CPU",
+	})
+	require.NoError(t, err)
+	dashboard.SetMetadata("folder", "team-a")
+	other := newFakeProvider().resource("other", map[string]any{"uid": "other"})
+
+	expected := `resource "grafana_dashboard" "_42-cpu_usage" {
+  folder      = "team-a"
+  config_json = <<EOT
+{
+  "title": "$${host} CPU",
+  "uid": "42-cpu.usage"
+}
+EOT
+}
+`
+
+	t.Run("dashboards are written as grafana_dashboard resources", func(t *testing.T) {
+		req := require.New(t)
+		exportDir := t.TempDir()
+		recorder := &fakeRecorder{}
+
+		err := grizzly.Export(context.Background(), recorder, provider.registry(), exportDir, grizzly.NewResources(dashboard, other), grizzly.ExportOptions{
+			OutputFormat: "terraform",
+		})
+		req.NoError(err)
+
+		content, err := os.ReadFile(filepath.Join(exportDir, "Dashboard", "team-a", "42-cpu.usage.tf"))
+		req.NoError(err)
+		req.Equal(expected, string(content))
+		req.Equal(1, recorder.count(grizzly.ResourceAdded))
+		req.Equal(1, recorder.count(grizzly.ResourceSkipped), "resources other than dashboards have no Terraform equivalent")
+	})
+
+	t.Run("dashboards can be written to a single file", func(t *testing.T) {
+		req := require.New(t)
+		exportFile := filepath.Join(t.TempDir(), "dashboards.tf")
+
+		err := grizzly.Export(context.Background(), &fakeRecorder{}, provider.registry(), exportFile, grizzly.NewResources(dashboard), grizzly.ExportOptions{
+			OutputFormat: "terraform",
+			Layout:       grizzly.ExportLayoutStream,
+		})
+		req.NoError(err)
+
+		content, err := os.ReadFile(exportFile)
+		req.NoError(err)
+		req.Equal(expected, string(content))
+	})
+}
+
+func TestExportFolders(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider()
+	provider.handler.BaseHandler = grizzly.NewBaseHandler(provider, fakeKind, true)
+	exportDir := t.TempDir()
+
+	inFolder := provider.resource("in-folder", map[string]any{"uid": "in-folder"})
+	inFolder.SetMetadata("folder", "team-a")
+	noFolder := provider.resource("no-folder", map[string]any{"uid": "no-folder"})
+
+	err := grizzly.Export(context.Background(), &fakeRecorder{}, provider.registry(), exportDir, grizzly.NewResources(inFolder, noFolder), grizzly.ExportOptions{
+		OutputFormat: "json",
+	})
+	req.NoError(err)
+
+	req.FileExists(filepath.Join(exportDir, fakeKind, "team-a", "in-folder.json"))
+	req.FileExists(filepath.Join(exportDir, fakeKind, "no-folder.json"))
+}
+
+func TestExportWorkers(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider()
+	provider.handler.BaseHandler = grizzly.NewBaseHandler(provider, fakeKind, true)
+	exportDir := t.TempDir()
+
+	resources := grizzly.NewResources()
+	for i := 0; i < 50; i++ {
+		uid := fmt.Sprintf("dashboard-%02d", i)
+		resource := provider.resource(uid, map[string]any{"uid": uid})
+		resource.SetMetadata("folder", fmt.Sprintf("team-%d", i%3))
+		resources.Add(resource)
+	}
+	recorder := &fakeRecorder{}
+
+	err := grizzly.Export(context.Background(), recorder, provider.registry(), exportDir, resources, grizzly.ExportOptions{
+		OutputFormat: "json",
+		Workers:      8,
+	})
+	req.NoError(err)
+
+	req.Equal(50, recorder.count(grizzly.ResourceAdded))
+	for i, resource := range resources.AsList() {
+		req.Equal(resource.Ref().String(), recorder.events[i].ResourceRef, "events are reported in order")
+		req.FileExists(filepath.Join(exportDir, fakeKind, resource.GetMetadata("folder"), resource.Name()+".json"))
+	}
+}
+
+func TestExportWorkersStopOnError(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider()
+	exportDir := t.TempDir()
+
+	resources := grizzly.NewResources()
+	for i := 0; i < 50; i++ {
+		uid := fmt.Sprintf("dashboard-%02d", i)
+		resources.Add(provider.resource(uid, map[string]any{"uid": uid}))
+	}
+	// the first resource can't be written, as its path is taken by a directory
+	req.NoError(os.MkdirAll(filepath.Join(exportDir, fakeKind, "dashboard-00.json"), 0755))
+
+	err := grizzly.Export(context.Background(), &fakeRecorder{}, provider.registry(), exportDir, resources, grizzly.ExportOptions{
+		OutputFormat: "json",
+		Workers:      8,
+	})
+	req.Error(err)
+
+	written, err := filepath.Glob(filepath.Join(exportDir, fakeKind, "*.json"))
+	req.NoError(err)
+	time.Sleep(50 * time.Millisecond)
+	after, err := filepath.Glob(filepath.Join(exportDir, fakeKind, "*.json"))
+	req.NoError(err)
+	req.Equal(written, after, "no resource is written once the export has returned")
+}
+
+func TestExportFolderOverride(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider()
+	provider.handler.BaseHandler = grizzly.NewBaseHandler(provider, fakeKind, true)
+	exportDir := t.TempDir()
+
+	inFolder := provider.resource("in-folder", map[string]any{"uid": "in-folder"})
+	inFolder.SetMetadata("folder", "team-a")
+	noFolder := provider.resource("no-folder", map[string]any{"uid": "no-folder"})
+
+	err := grizzly.Export(context.Background(), &fakeRecorder{}, provider.registry(), exportDir, grizzly.NewResources(inFolder, noFolder), grizzly.ExportOptions{
+		OutputFormat: "json",
+		Folder:       "sandbox",
+	})
+	req.NoError(err)
+
+	req.FileExists(filepath.Join(exportDir, fakeKind, "sandbox", "in-folder.json"))
+	req.FileExists(filepath.Join(exportDir, fakeKind, "sandbox", "no-folder.json"))
+	req.Equal("team-a", inFolder.GetMetadata("folder"), "the given resources must be left untouched")
+}
+
+func TestExportRemote(t *testing.T) {
+	req := require.New(t)
+	remote := newFakeProvider()
+	provider := newFakeProvider(
+		remote.resource("first", map[string]any{"uid": "first"}),
+		remote.resource("second", map[string]any{"uid": "second"}),
+	)
+	exportDir := t.TempDir()
+	recorder := &fakeRecorder{}
+
+	err := grizzly.ExportRemote(context.Background(), recorder, provider.registry(), exportDir, []string{fakeKind + ".first"}, grizzly.ExportOptions{
+		OutputFormat: "yaml",
+	})
+	req.NoError(err)
+
+	req.FileExists(filepath.Join(exportDir, fakeKind, "first.yaml"))
+	req.NoFileExists(filepath.Join(exportDir, fakeKind, "second.yaml"))
+	req.Equal(1, recorder.count(grizzly.ResourceAdded))
+}
+
+func TestExportRemoteRaw(t *testing.T) {
+	provider := newFakeProvider(newFakeProvider().resource("first", map[string]any{"uid": "first", "zeta": 1.5}))
+
+	t.Run("resources are written as returned by the endpoints", func(t *testing.T) {
+		req := require.New(t)
+		exportDir := t.TempDir()
+
+		err := grizzly.ExportRemote(context.Background(), &fakeRecorder{}, provider.registry(), exportDir, nil, grizzly.ExportOptions{
+			OutputFormat: "json",
+			OnlySpec:     true,
+			Raw:          true,
+		})
+		req.NoError(err)
+
+		content, err := os.ReadFile(filepath.Join(exportDir, fakeKind, "first.json"))
+		req.NoError(err)
+		req.Equal(`{"zeta":1.50,"uid":"first"}`, string(content))
+	})
+
+	t.Run("raw resources are JSON specs", func(t *testing.T) {
+		err := grizzly.ExportRemote(context.Background(), &fakeRecorder{}, provider.registry(), t.TempDir(), nil, grizzly.ExportOptions{
+			OutputFormat: "yaml",
+			OnlySpec:     true,
+			Raw:          true,
+		})
+		require.ErrorContains(t, err, "raw resources can only be written as JSON, without envelope")
+	})
+}
+
+func TestPullRaw(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider(newFakeProvider().resource("first", map[string]any{"uid": "first", "zeta": 1.5}))
+	pullDir := t.TempDir()
+
+	err := grizzly.Pull(context.Background(), provider.registry(), pullDir, true, "json", true, nil, nil, false, &fakeRecorder{})
+	req.NoError(err)
+
+	content, err := os.ReadFile(filepath.Join(pullDir, "fakes", "first.json"))
+	req.NoError(err)
+	req.Equal(`{"zeta":1.50,"uid":"first"}`, string(content))
+}
+
+func TestValidate(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider(newFakeProvider().resource("taken", map[string]any{"title": "Overview"}))
+	resources := grizzly.NewResources(
+		provider.resource("latency", map[string]any{"title": "Latency"}),
+		provider.resource("clash", map[string]any{"title": "Overview"}),
+	)
+
+	var out bytes.Buffer
+	grizzly.SetOutput(&out)
+	t.Cleanup(func() { grizzly.SetOutput(os.Stdout) })
+
+	req.NoError(grizzly.Validate(context.Background(), provider.registry(), resources, false))
+
+	out.Reset()
+	err := grizzly.Validate(context.Background(), provider.registry(), resources, true)
+	req.ErrorContains(err, "Fake.clash: title Overview is used by taken")
+	req.Contains(out.String(), "1 valid, 1 invalid")
+	req.Empty(provider.handler.added, "remote validation must not change remote resources")
+}
+
+func TestValidateReferences(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider()
+	resources := grizzly.NewResources(
+		provider.resource("overview", map[string]any{}),
+		provider.resource("rotation", map[string]any{"references": []any{"overview", "latency"}}),
+	)
+
+	var out bytes.Buffer
+	grizzly.SetOutput(&out)
+	t.Cleanup(func() { grizzly.SetOutput(os.Stdout) })
+
+	req.NoError(grizzly.Validate(context.Background(), provider.registry(), resources, false), "unresolved references are only warnings")
+	req.Contains(out.String(), "Fake.rotation references latency, which is missing")
+	req.NotContains(out.String(), "references overview")
+
+	out.Reset()
+	req.NoError(grizzly.Apply(context.Background(), provider.registry(), resources, grizzly.ApplyOptions{DryRun: true}, &fakeRecorder{}))
+	req.Contains(out.String(), "Fake.rotation references latency, which is missing", "dry runs show unresolved references too")
+}
+
+func TestCheckConflicts(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider(newFakeProvider().resource("taken", map[string]any{"title": "Overview"}))
+	resources := grizzly.NewResources(
+		provider.resource("free", map[string]any{"title": "Latency"}),
+		provider.resource("taken", map[string]any{"title": "Overview"}),
+	)
+
+	var out bytes.Buffer
+	grizzly.SetOutput(&out)
+	t.Cleanup(func() { grizzly.SetOutput(os.Stdout) })
+
+	err := grizzly.CheckConflicts(context.Background(), provider.registry(), resources)
+	req.ErrorContains(err, "1 resources may collide with remote ones")
+	req.Contains(out.String(), "Fake.free no conflicts")
+	req.Contains(out.String(), "Fake.taken taken already exists")
+	req.Contains(out.String(), "1 without conflicts, 1 with potential conflicts")
+	req.Empty(provider.handler.added, "conflict detection must not change remote resources")
+	req.Empty(provider.handler.updated, "conflict detection must not change remote resources")
+}
+
+func TestCheckHealth(t *testing.T) {
+	req := require.New(t)
+	remote := newFakeProvider()
+	provider := newFakeProvider(
+		remote.resource("working", map[string]any{}),
+		remote.resource("broken", map[string]any{"broken": true}),
+	)
+	resources := grizzly.NewResources(
+		provider.resource("working", map[string]any{}),
+		provider.resource("broken", map[string]any{}),
+		provider.resource("missing", map[string]any{}),
+	)
+
+	var out bytes.Buffer
+	grizzly.SetOutput(&out)
+	t.Cleanup(func() { grizzly.SetOutput(os.Stdout) })
+
+	err := grizzly.CheckHealth(context.Background(), provider.registry(), resources)
+	req.ErrorContains(err, "2 resources failed their health check")
+	req.Contains(out.String(), "Fake.working connected")
+	req.Contains(out.String(), "Fake.broken connection refused")
+	req.Contains(out.String(), "Fake.missing not found")
+	req.Contains(out.String(), "1 healthy, 2 failing")
+
+	out.Reset()
+	req.NoError(grizzly.CheckHealth(context.Background(), provider.registry(), grizzly.NewResources(provider.resource("working", map[string]any{}))))
+	req.Contains(out.String(), "1 healthy, 0 failing")
+}
+
+func TestWatchUnknownMode(t *testing.T) {
+	provider := newFakeProvider()
+	err := grizzly.Watch(context.Background(), provider.registry(), t.TempDir(), "resources.jsonnet", "deploy", nil, grizzly.ParserOptions{}, &fakeRecorder{})
+	require.ErrorContains(t, err, `unknown watch mode "deploy"`)
+}
+
+func TestSnapshots(t *testing.T) {
+	provider := newFakeProvider()
+	registry := provider.registry()
+
+	resources := grizzly.NewResources(provider.resource("a", map[string]any{"uid": "a"}))
+	if err := grizzly.Snapshot(context.Background(), registry, resources, grizzly.SnapshotOptions{Name: "release"}); err != nil {
+		t.Fatal(err)
+	}
+	snapshot, ok := provider.handler.snapshots["snapshot-a"]
+	if !ok || snapshot.Name != "release" {
+		t.Fatalf("expected a snapshot named release, got %v", provider.handler.snapshots)
+	}
+
+	if err := grizzly.ListSnapshots(context.Background(), registry, "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := grizzly.DeleteSnapshot(context.Background(), registry, "snapshot-a"); err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.handler.snapshots) != 0 {
+		t.Errorf("expected the snapshot to be deleted, got %v", provider.handler.snapshots)
+	}
+
+	if err := grizzly.DeleteSnapshot(context.Background(), registry, "snapshot-a"); err == nil {
+		t.Error("expected an error when deleting an unknown snapshot")
+	}
+}
+
+func TestRender(t *testing.T) {
+	provider := newFakeProvider()
+	registry := provider.registry()
+	resources := grizzly.NewResources(provider.resource("a", map[string]any{"uid": "a"}))
+
+	dir := t.TempDir()
+	if err := grizzly.Render(context.Background(), registry, resources, dir, grizzly.RenderOptions{Theme: "dark"}); err != nil {
+		t.Fatal(err)
+	}
+	image, err := os.ReadFile(filepath.Join(dir, fakeKind, "a.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(image) != "a@dark" {
+		t.Errorf("expected the rendered image, got %q", image)
+	}
+
+	if err := grizzly.Render(context.Background(), registry, resources, dir, grizzly.RenderOptions{PanelID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, fakeKind, "a-panel-2.png")); err != nil {
+		t.Errorf("expected the panel to be rendered to its own file: %s", err)
+	}
+
+	// resources are skipped when no renderer is available
+	provider.handler.noRenderer = true
+	dir = t.TempDir()
+	if err := grizzly.Render(context.Background(), registry, resources, dir, grizzly.RenderOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("expected no images, got %v", entries)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	provider := newFakeProvider()
+	provider.handler.history["a"] = []grizzly.Resource{
+		provider.resource("a", map[string]any{"uid": "a", "title": "first"}),
+		provider.resource("a", map[string]any{"uid": "a", "title": "second"}),
+	}
+	registry := provider.registry()
+	dir := t.TempDir()
+
+	if err := grizzly.Rollback(context.Background(), registry, "Fake.a", 1, dir, true, "json"); err != nil {
+		t.Fatal(err)
+	}
+	restored := provider.handler.remote["a"]
+	if title := restored.Spec()["title"]; title != "first" {
+		t.Errorf("expected version 1 to be restored, got title %v", title)
+	}
+	if len(provider.handler.history["a"]) != 3 {
+		t.Errorf("expected the rollback to create a new version, got %d versions", len(provider.handler.history["a"]))
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "fakes", "a.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `"title": "first"`) {
+		t.Errorf("expected the restored resource to be written, got %s", content)
+	}
+
+	if err := grizzly.Rollback(context.Background(), registry, "Fake.a", 42, "", false, "yaml"); !errors.Is(err, grizzly.ErrNotFound) {
+		t.Errorf("expected unknown versions to be reported as not found, got %v", err)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	req := require.New(t)
+
+	var out bytes.Buffer
+	grizzly.SetOutput(&out)
+	t.Cleanup(func() { grizzly.SetOutput(os.Stdout) })
+
+	provider := newFakeProvider()
+	provider.handler.history["a"] = []grizzly.Resource{
+		provider.resource("a", map[string]any{"uid": "a", "title": "first"}),
+		provider.resource("a", map[string]any{"uid": "a", "title": "second"}),
+	}
+	registry := provider.registry()
+
+	req.NoError(grizzly.Versions(context.Background(), registry, "Fake.a", "default"))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	req.Len(lines, 3)
+	req.Regexp(`^VERSION\s+CREATED\s+AUTHOR\s+MESSAGE$`, lines[0])
+	req.Regexp(`^2\s+admin\s+second$`, lines[1], "latest versions come first")
+
+	out.Reset()
+	req.NoError(grizzly.Versions(context.Background(), registry, "Fake.a", "json"))
+	var versions []grizzly.Version
+	req.NoError(json.Unmarshal(out.Bytes(), &versions))
+	req.Len(versions, 2)
+
+	req.ErrorIs(grizzly.Versions(context.Background(), registry, "Fake.unknown", "default"), grizzly.ErrNotFound)
+}
+
+func TestSetOutput(t *testing.T) {
+	req := require.New(t)
+
+	var out bytes.Buffer
+	grizzly.SetOutput(&out)
+	t.Cleanup(func() { grizzly.SetOutput(os.Stdout) })
+
+	provider := newFakeProvider()
+	remote := provider.resource("remote", map[string]any{"uid": "remote", "title": "from remote"})
+	provider.handler.remote["remote"] = remote
+	registry := provider.registry()
+	resources := grizzly.NewResources(provider.resource("local", map[string]any{"uid": "local", "title": "from local"}))
+
+	req.NoError(grizzly.List(registry, resources, "default"))
+	req.Contains(out.String(), "UID")
+	req.Contains(out.String(), "local")
+
+	out.Reset()
+	req.NoError(grizzly.Show(registry, resources, "yaml"))
+	req.Contains(out.String(), "title: from local")
+
+	out.Reset()
+	req.NoError(grizzly.Get(context.Background(), registry, []string{fakeKind + ".remote"}, false, "yaml"))
+	req.Contains(out.String(), "title: from remote")
+}
+
+func TestGetSeveral(t *testing.T) {
+	req := require.New(t)
+	var out bytes.Buffer
+	grizzly.SetOutput(&out)
+	t.Cleanup(func() { grizzly.SetOutput(os.Stdout) })
+
+	provider := newFakeProvider(
+		newFakeProvider().resource("a", map[string]any{"uid": "a"}),
+		newFakeProvider().resource("b", map[string]any{"uid": "b"}),
+	)
+	registry := provider.registry()
+
+	req.NoError(grizzly.Get(context.Background(), registry, []string{fakeKind + ".a", fakeKind + ".b"}, true, "yaml"))
+	req.Equal("uid: a\n\n---\nuid: b\n\n", out.String())
+
+	out.Reset()
+	req.NoError(grizzly.Get(context.Background(), registry, []string{fakeKind + ".a", fakeKind + ".b"}, true, "json"))
+	decoder := json.NewDecoder(&out)
+	for _, uid := range []string{"a", "b"} {
+		var spec map[string]any
+		req.NoError(decoder.Decode(&spec))
+		req.Equal(uid, spec["uid"])
+	}
+
+	req.ErrorContains(grizzly.Get(context.Background(), registry, []string{"a"}, true, "yaml"), "UID must be <provider>.<uid>")
+}
+
+func TestExportSkipExisting(t *testing.T) {
+	req := require.New(t)
+	remote := newFakeProvider()
+	provider := newFakeProvider(
+		remote.resource("first", map[string]any{"uid": "first", "title": "remote"}),
+		remote.resource("second", map[string]any{"uid": "second", "title": "remote"}),
+	)
+	exportDir := t.TempDir()
+
+	// an interrupted export, which only wrote the first resource
+	existing := filepath.Join(exportDir, fakeKind, "first.yaml")
+	req.NoError(os.MkdirAll(filepath.Dir(existing), 0755))
+	req.NoError(os.WriteFile(existing, []byte("partial"), 0644))
+
+	recorder := &fakeRecorder{}
+	err := grizzly.ExportRemote(context.Background(), recorder, provider.registry(), exportDir, nil, grizzly.ExportOptions{
+		OutputFormat: "yaml",
+		SkipExisting: true,
+	})
+	req.NoError(err)
+
+	content, err := os.ReadFile(existing)
+	req.NoError(err)
+	req.Equal("partial", string(content))
+	req.FileExists(filepath.Join(exportDir, fakeKind, "second.yaml"))
+	req.Equal(1, recorder.count(grizzly.ResourceSkipped))
+	req.Equal(1, recorder.count(grizzly.ResourceAdded))
+
+	t.Run("files in folder sub-directories are found", func(t *testing.T) {
+		req := require.New(t)
+		provider.handler.BaseHandler = grizzly.NewBaseHandler(provider, fakeKind, true)
+		exportDir := t.TempDir()
+		existing := filepath.Join(exportDir, fakeKind, "team-a", "second.yaml")
+		req.NoError(os.MkdirAll(filepath.Dir(existing), 0755))
+		req.NoError(os.WriteFile(existing, []byte("partial"), 0644))
+
+		recorder := &fakeRecorder{}
+		err := grizzly.ExportRemote(context.Background(), recorder, provider.registry(), exportDir, []string{fakeKind + ".second"}, grizzly.ExportOptions{
+			OutputFormat: "yaml",
+			SkipExisting: true,
+		})
+		req.NoError(err)
+		req.Equal(1, recorder.count(grizzly.ResourceSkipped))
+		req.Equal(0, recorder.count(grizzly.ResourceAdded))
+	})
+
+	t.Run("the stream layout is rejected", func(t *testing.T) {
+		err := grizzly.Export(context.Background(), &fakeRecorder{}, provider.registry(), filepath.Join(t.TempDir(), "all.yaml"), grizzly.NewResources(), grizzly.ExportOptions{
+			Layout:       grizzly.ExportLayoutStream,
+			SkipExisting: true,
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestApplyState(t *testing.T) {
+	req := require.New(t)
+	provider := newFakeProvider()
+	registry := provider.registry()
+	stateFile := filepath.Join(t.TempDir(), grizzly.StateFile)
+
+	apply := func(scope string, resources ...grizzly.Resource) *fakeRecorder {
+		state, err := grizzly.LoadApplyState(stateFile, scope)
+		req.NoError(err)
+		recorder := &fakeRecorder{}
+		req.NoError(grizzly.Apply(context.Background(), registry, grizzly.NewResources(resources...), grizzly.ApplyOptions{State: state}, recorder))
+		req.NoError(state.Save())
+		return recorder
+	}
+
+	apply("prod",
+		provider.resource("a", map[string]any{"uid": "a", "title": "first"}),
+		provider.resource("b", map[string]any{"uid": "b", "title": "first"}),
+	)
+	req.Equal([]string{"a", "b"}, provider.handler.added)
+
+	// changes made remotely are not noticed for resources unchanged locally,
+	// as they aren't fetched
+	provider.handler.remote["a"] = provider.resource("a", map[string]any{"uid": "a", "title": "remote"})
+
+	recorder := apply("prod",
+		provider.resource("a", map[string]any{"uid": "a", "title": "first"}),
+		provider.resource("b", map[string]any{"uid": "b", "title": "second"}),
+	)
+	req.Equal(1, recorder.count(grizzly.ResourceNotChanged))
+	req.Equal([]string{"b"}, provider.handler.updated)
+	remote := provider.handler.remote["a"]
+	req.Equal("remote", remote.Spec()["title"])
+
+	// each scope has its own state
+	recorder = apply("staging",
+		provider.resource("a", map[string]any{"uid": "a", "title": "first"}),
+	)
+	req.Equal(1, recorder.count(grizzly.ResourceUpdated))
+	req.Equal([]string{"b", "a"}, provider.handler.updated)
+}
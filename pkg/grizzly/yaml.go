@@ -1,7 +1,7 @@
 package grizzly
 
 import (
-	"bufio"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -13,12 +13,16 @@ import (
 type YAMLParser struct {
 	registry Registry
 	logger   *log.Entry
+	// strict makes documents of an unknown kind fail the parsing, instead of
+	// being skipped
+	strict bool
 }
 
-func NewYAMLParser(registry Registry) *YAMLParser {
+func NewYAMLParser(registry Registry, strict bool) *YAMLParser {
 	return &YAMLParser{
 		registry: registry,
 		logger:   log.WithField("parser", "yaml"),
+		strict:   strict,
 	}
 }
 
@@ -28,7 +32,9 @@ func (parser *YAMLParser) Accept(file string) bool {
 	return extension == ".yaml" || extension == ".yml"
 }
 
-// Parse evaluates a YAML file and parses it into resources
+// Parse evaluates a YAML file and parses it into resources. Documents are
+// decoded one at a time, and only kept once turned into resources, so that
+// large streams of documents (ex: exports) aren't held twice in memory.
 func (parser *YAMLParser) Parse(file string, options ParserOptions) (Resources, error) {
 	parser.logger.WithField("file", file).Debug("Parsing file")
 
@@ -38,12 +44,23 @@ func (parser *YAMLParser) Parse(file string, options ParserOptions) (Resources,
 	}
 	defer f.Close()
 
-	reader := bufio.NewReader(f)
-	decoder := yaml.NewDecoder(reader)
+	source := Source{
+		Path:       file,
+		Rewritable: true,
+	}
+	return parser.parseReader(f, source, options)
+}
+
+// parseReader parses the YAML documents read from r into resources
+func (parser *YAMLParser) parseReader(r io.Reader, source Source, options ParserOptions) (Resources, error) {
+	source.Format = formatYAML
+	// the decoder buffers its reads on its own
+	decoder := yaml.NewDecoder(r)
 	resources := NewResources()
+	var skipped []string
 	for i := 0; ; i++ {
 		var m any
-		err = decoder.Decode(&m)
+		err := decoder.Decode(&m)
 		if err == io.EOF {
 			break
 		}
@@ -51,12 +68,15 @@ func (parser *YAMLParser) Parse(file string, options ParserOptions) (Resources,
 			return Resources{}, err
 		}
 
-		source := Source{
-			Format:     formatYAML,
-			Path:       file,
-			Rewritable: true,
+		if kind, known := parser.kind(m); !known {
+			if parser.strict {
+				return Resources{}, fmt.Errorf("document %d: unknown kind %s", i+1, kind)
+			}
+			skipped = append(skipped, fmt.Sprintf("document %d (kind %s)", i+1, kind))
+			continue
 		}
-		parsedResources, err := parseAny(parser.registry, m, options.DefaultResourceKind, options.DefaultFolderUID, source)
+
+		parsedResources, err := parseAny(parser.registry, m, options, source)
 		if err != nil {
 			return Resources{}, err
 		}
@@ -64,5 +84,25 @@ func (parser *YAMLParser) Parse(file string, options ParserOptions) (Resources,
 		resources.Merge(parsedResources)
 	}
 
-	return resources, nil
+	for _, document := range skipped {
+		parser.logger.WithField("file", source.Path).Warnf("Skipped %s: unknown kind", document)
+	}
+
+	return resources.Sort(), nil
+}
+
+// kind returns the kind of an enveloped document, and whether a handler
+// exists for it. Other documents are considered known, and left to the
+// resource parsing to identify.
+func (parser *YAMLParser) kind(document any) (string, bool) {
+	m, ok := document.(map[string]any)
+	if !ok || !DetectEnvelope(m) {
+		return "", true
+	}
+	kind, ok := m["kind"].(string)
+	if !ok {
+		return "", true
+	}
+	_, err := parser.registry.GetHandler(kind)
+	return kind, err == nil
 }
@@ -19,20 +19,11 @@ import (
 )
 
 var loadRulesEndpoint = "%s/prometheus/config/v1/rules/%s"
-var listRulesEndpoint = "%s/prometheus/api/v1/rules"
+var listRulesEndpoint = "%s/prometheus/config/v1/rules"
 
-type ListGroupResponse struct {
-	Status string `yaml:"status"`
-	Data   struct {
-		DataGroups []DataGroups `yaml:"groups"`
-	} `yaml:"data"`
-}
-
-type DataGroups struct {
-	Name  string        `yaml:"name"`
-	File  string        `yaml:"file"`
-	Rules []interface{} `yaml:"rules"`
-}
+// errNotFound is returned for 404 responses, which the ruler sends when a
+// tenant has no rule groups
+var errNotFound = errors.New("not found")
 
 type Client struct {
 	config *config.MimirConfig
@@ -43,23 +34,20 @@ func NewHTTPClient(config *config.MimirConfig) Mimir {
 }
 
 func (c *Client) ListRules() (map[string][]models.PrometheusRuleGroup, error) {
+	// the ruler configuration API returns rule groups as they were written,
+	// unlike the Prometheus API which returns their evaluation state
 	url := fmt.Sprintf(listRulesEndpoint, c.config.Address)
 	res, err := c.doRequest(http.MethodGet, url, nil)
-	if err != nil {
-		return nil, err
+	if errors.Is(err, errNotFound) {
+		return map[string][]models.PrometheusRuleGroup{}, nil
 	}
-
-	var response ListGroupResponse
-	if err := yaml.Unmarshal(res, &response); err != nil {
+	if err != nil {
 		return nil, err
 	}
 
 	groups := make(map[string][]models.PrometheusRuleGroup)
-	for _, g := range response.Data.DataGroups {
-		groups[g.File] = append(groups[g.File], models.PrometheusRuleGroup{
-			Name:  g.Name,
-			Rules: g.Rules,
-		})
+	if err := yaml.Unmarshal(res, &groups); err != nil {
+		return nil, err
 	}
 
 	return groups, nil
@@ -115,6 +103,9 @@ func (c *Client) doRequest(method string, url string, body []byte) ([]byte, erro
 		return nil, fmt.Errorf("cannot read response body: %s", err)
 	}
 
+	if res.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", errNotFound, strings.TrimSpace(string(b)))
+	}
 	if res.StatusCode >= 300 {
 		return nil, fmt.Errorf("error loading rules: %d, error: %s", res.StatusCode, strings.TrimSpace(string(b)))
 	}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/grizzly/pkg/config"
+	"github.com/grafana/grizzly/pkg/mimir/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ListRules(t *testing.T) {
+	t.Run("rule groups are read from the ruler configuration", func(t *testing.T) {
+		req := require.New(t)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req.Equal("/prometheus/config/v1/rules", r.URL.Path)
+			req.Equal("tenant", r.Header.Get("X-Scope-OrgID"))
+			_, _ = w.Write([]byte(`
+grizzly_rules:
+  - name: grizzly_alerts
+    interval: 1m
+    rules:
+      - alert: PromScrapeFailed
+        expr: up != 1
+`))
+		}))
+		t.Cleanup(server.Close)
+
+		groups, err := NewHTTPClient(&config.MimirConfig{Address: server.URL, TenantID: "tenant"}).ListRules()
+		req.NoError(err)
+		req.Equal(map[string][]models.PrometheusRuleGroup{
+			"grizzly_rules": {
+				{
+					Name:     "grizzly_alerts",
+					Interval: "1m",
+					Rules: []interface{}{
+						map[string]interface{}{"alert": "PromScrapeFailed", "expr": "up != 1"},
+					},
+				},
+			},
+		}, groups)
+	})
+
+	t.Run("tenants without rule groups have no rules", func(t *testing.T) {
+		req := require.New(t)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "no rule groups found", http.StatusNotFound)
+		}))
+		t.Cleanup(server.Close)
+
+		groups, err := NewHTTPClient(&config.MimirConfig{Address: server.URL, TenantID: "tenant"}).ListRules()
+		req.NoError(err)
+		req.Empty(groups)
+	})
+}
@@ -2,8 +2,9 @@ package models
 
 // PrometheusRuleGroup encapsulates a list of rules
 type PrometheusRuleGroup struct {
-	Name  string        `yaml:"name"`
-	Rules []interface{} `yaml:"rules"`
+	Name     string        `yaml:"name"`
+	Interval string        `yaml:"interval,omitempty"`
+	Rules    []interface{} `yaml:"rules"`
 }
 
 // PrometheusRuleGrouping encapsulates a set of named rule groups
@@ -103,6 +103,9 @@ func (h *RuleHandler) getRemoteRuleGroup(uid string) (*grizzly.Resource, error)
 					spec := map[string]interface{}{
 						"rules": group.Rules,
 					}
+					if group.Interval != "" {
+						spec["interval"] = group.Interval
+					}
 					resource, err := grizzly.NewResource(h.APIVersion(), h.Kind(), group.Name, spec)
 					if err != nil {
 						return nil, err
@@ -138,6 +141,9 @@ func (h *RuleHandler) writeRuleGroup(resource grizzly.Resource) error {
 		Name:  resource.Name(),
 		Rules: []interface{}{},
 	}
+	if interval, ok := resource.GetSpecString("interval"); ok {
+		newGroup.Interval = interval
+	}
 	rules := resource.Spec()["rules"].([]interface{})
 	for _, ruleIf := range rules {
 		rule := ruleIf.(map[string]interface{})
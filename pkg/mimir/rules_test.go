@@ -31,6 +31,15 @@ func TestRules(t *testing.T) {
 		require.Equal(t, "PrometheusRuleGroup", res.Kind())
 	})
 
+	t.Run("get remote rule group - evaluation interval", func(t *testing.T) {
+		client.mockResponse(t, true, nil)
+		res, err := h.getRemoteRuleGroup("first_rules.grizzly_recording_rules")
+		require.NoError(t, err)
+		interval, ok := res.GetSpecString("interval")
+		require.True(t, ok)
+		require.Equal(t, "5m", interval)
+	})
+
 	t.Run("get remote rule group - error from mimir client", func(t *testing.T) {
 		client.mockResponse(t, false, errMimirClient)
 		res, err := h.getRemoteRuleGroup("first_rules.grizzly_alerts")
@@ -81,6 +81,14 @@ func (h *SyntheticMonitoringHandler) Prepare(existing *grizzly.Resource, resourc
 	return &resource
 }
 
+// resourceJob returns the job of a check, which defaults to its name
+func resourceJob(resource grizzly.Resource) string {
+	if job, ok := resource.GetSpecString("job"); ok && job != "" {
+		return job
+	}
+	return resource.Name()
+}
+
 // Validate returns the uid of resource
 func (h *SyntheticMonitoringHandler) Validate(resource grizzly.Resource) error {
 	job, exist := resource.GetSpecString("job")
@@ -108,13 +116,23 @@ func (h *SyntheticMonitoringHandler) GetSpecUID(resource grizzly.Resource) (stri
 
 // GetByUID retrieves JSON for a resource from an endpoint, by UID
 func (h *SyntheticMonitoringHandler) GetByUID(uid string) (*grizzly.Resource, error) {
-	return h.getRemoteCheck(uid)
+	return h.getRemoteCheck(func(check synthetic_monitoring.Check) bool {
+		return h.getUID(check) == uid
+	})
 }
 
-// GetRemote retrieves a datasource as a Resource
+// GetRemote retrieves a check as a Resource. Synthetic Monitoring identifies
+// checks by their job and target, so checks declaring a target are matched
+// on both, allowing the same job to probe several targets.
 func (h *SyntheticMonitoringHandler) GetRemote(resource grizzly.Resource) (*grizzly.Resource, error) {
-	uid := fmt.Sprintf("%s.%s", resource.GetMetadata("type"), resource.Name())
-	return h.getRemoteCheck(uid)
+	target, ok := resource.GetSpecString("target")
+	if !ok || target == "" {
+		return h.GetByUID(fmt.Sprintf("%s.%s", resource.GetMetadata("type"), resource.Name()))
+	}
+	job := resourceJob(resource)
+	return h.getRemoteCheck(func(check synthetic_monitoring.Check) bool {
+		return check.Job == job && check.Target == target
+	})
 }
 
 // ListRemote retrieves as list of UIDs of all remote resources
@@ -181,8 +199,8 @@ func (h *SyntheticMonitoringHandler) getRemoteCheckList() ([]string, error) {
 	return checkIDs, nil
 }
 
-// getRemoteCheck retrieves a check object from SM
-func (h *SyntheticMonitoringHandler) getRemoteCheck(uid string) (*grizzly.Resource, error) {
+// getRemoteCheck retrieves the first check object from SM matching the given function
+func (h *SyntheticMonitoringHandler) getRemoteCheck(matches func(check synthetic_monitoring.Check) bool) (*grizzly.Resource, error) {
 	smClient, err := h.Provider.(ClientProvider).Client()
 	if err != nil {
 		return nil, err
@@ -202,7 +220,7 @@ func (h *SyntheticMonitoringHandler) getRemoteCheck(uid string) (*grizzly.Resour
 	}
 
 	for _, check := range checkList {
-		if h.getUID(check) == uid {
+		if matches(check) {
 			var probeNames []string
 			for _, probeID := range check.Probes {
 				probeNames = append(probeNames, probes.ByID[probeID].Name)
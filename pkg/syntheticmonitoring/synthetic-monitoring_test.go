@@ -160,3 +160,17 @@ func TestSyntheticMonitoringHandler_ResourceFilePath(t *testing.T) {
 		req.Equal("synthetic-monitoring/check-some-check.yaml", handler.ResourceFilePath(resource, "yaml"))
 	})
 }
+
+func TestSyntheticMonitoringResourceJob(t *testing.T) {
+	t.Run("job is read from the spec", func(t *testing.T) {
+		resource, err := grizzly.NewResource("grizzly.grafana.com/v1alpha1", SyntheticMonitoringCheckKind, "test", map[string]any{"job": "foo"})
+		require.NoError(t, err)
+		require.Equal(t, "foo", resourceJob(resource))
+	})
+
+	t.Run("job defaults to the name", func(t *testing.T) {
+		resource, err := grizzly.NewResource("grizzly.grafana.com/v1alpha1", SyntheticMonitoringCheckKind, "test", map[string]any{})
+		require.NoError(t, err)
+		require.Equal(t, "test", resourceJob(resource))
+	})
+}